@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/export"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"go.uber.org/zap"
+)
+
+// runExport implements "slack-mcp-server export", which writes the history of
+// one channel to disk without starting the MCP server.
+func runExport(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	channel := flags.String("channel", "", "Channel ID or name, e.g. C0123456789, #general or @username")
+	from := flags.String("from", "", "First day to export (YYYY-MM-DD), defaults to the oldest message")
+	to := flags.String("to", "", "Last day to export (YYYY-MM-DD), defaults to the newest message")
+	format := flags.String("format", "jsonl", "Output format: jsonl, slack or html")
+	output := flags.String("output", "", "Output file, or directory for the slack format (default <channel>.<format> or slack-export)")
+	files := flags.Bool("files", false, "Download attached files next to the export")
+	source := flags.String("source", "slack", "Data source (slack or archive:/path/to/export.zip)")
+	_ = flags.Parse(args)
+
+	logger, err := newLogger("stdio")
+	if err != nil {
+		panic(err)
+	}
+	defer logger.Sync()
+
+	if *channel == "" {
+		logger.Fatal("-channel is required", zap.String("context", "console"))
+	}
+	f, err := export.ParseFormat(*format)
+	if err != nil {
+		logger.Fatal("Invalid export format", zap.String("context", "console"), zap.Error(err))
+	}
+	opts := export.Options{Format: f, Output: *output, Files: *files}
+	if opts.Oldest, err = parseDay(*from); err != nil {
+		logger.Fatal("Invalid -from date", zap.String("context", "console"), zap.Error(err))
+	}
+	if opts.Latest, err = parseDay(*to); err != nil {
+		logger.Fatal("Invalid -to date", zap.String("context", "console"), zap.Error(err))
+	}
+	if !opts.Latest.IsZero() {
+		// the whole last day is included
+		opts.Latest = opts.Latest.AddDate(0, 0, 1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	p := newProvider("stdio", *source, logger)
+	if err := p.RefreshUsers(ctx); err != nil {
+		logger.Fatal("Failed to load users", zap.String("context", "console"), zap.Error(err))
+	}
+	if err := p.RefreshChannels(ctx); err != nil {
+		logger.Fatal("Failed to load channels", zap.String("context", "console"), zap.Error(err))
+	}
+
+	opts.Users = p.ProvideUsersMap().Users
+	if opts.Channel, err = lookupChannel(p, *channel); err != nil {
+		logger.Fatal("Invalid channel", zap.String("context", "console"), zap.Error(err))
+	}
+	if opts.Output == "" {
+		opts.Output = "slack-export"
+		if f != export.FormatSlack {
+			opts.Output = strings.TrimLeft(opts.Channel.Name, "#@") + f.Ext()
+		}
+	}
+
+	res, err := export.Run(ctx, p.Slack(), opts)
+	if err != nil {
+		logger.Fatal("Export failed", zap.String("context", "console"), zap.Error(err))
+	}
+	logger.Info("Export finished",
+		zap.String("context", "console"),
+		zap.String("channel", opts.Channel.ID),
+		zap.String("output", res.Output),
+		zap.Int("messages", res.Messages),
+		zap.Int("threads", res.Threads),
+		zap.Int("replies", res.Replies),
+		zap.Int("files", res.Files),
+	)
+}
+
+// lookupChannel resolves a channel ID, #name or @username from the channels cache.
+func lookupChannel(p *provider.ApiProvider, ref string) (provider.Channel, error) {
+	cms := p.ProvideChannelsMaps()
+	id := ref
+	if strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "@") {
+		var ok bool
+		if id, ok = cms.ChannelsInv[ref]; !ok {
+			return provider.Channel{}, fmt.Errorf("channel %q not found", ref)
+		}
+	}
+	if c, ok := cms.Channels[id]; ok {
+		return c, nil
+	}
+	return provider.Channel{ID: id, Name: id}, nil
+}
+
+func parseDay(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
@@ -21,9 +21,16 @@ var defaultSseHost = "127.0.0.1"
 var defaultSsePort = 13080
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExport(os.Args[2:])
+		return
+	}
+
 	var transport string
 	flag.StringVar(&transport, "t", "stdio", "Transport type (stdio, sse or http)")
 	flag.StringVar(&transport, "transport", "stdio", "Transport type (stdio, sse or http)")
+	var source string
+	flag.StringVar(&source, "source", "slack", "Data source (slack or archive:/path/to/export.zip)")
 	flag.Parse()
 
 	logger, err := newLogger(transport)
@@ -40,7 +47,7 @@ func main() {
 		)
 	}
 
-	p := provider.New(transport, logger)
+	p := newProvider(transport, source, logger)
 	s := server.NewMCPServer(p, logger)
 
 	go func() {
@@ -48,6 +55,7 @@ func main() {
 
 		newUsersWatcher(p, &once, logger)()
 		newChannelsWatcher(p, &once, logger)()
+		p.SyncMessages(context.Background())
 	}()
 
 	switch transport {
@@ -74,12 +82,31 @@ func main() {
 			port = strconv.Itoa(defaultSsePort)
 		}
 
-		sseServer := s.ServeSSE(":" + port)
+		listenerCfg := server.ListenerConfigFromEnv()
+		if err := listenerCfg.Validate(); err != nil {
+			logger.Fatal("Invalid listener configuration",
+				zap.String("context", "console"),
+				zap.Error(err),
+			)
+		}
+
+		httpCfg := server.HTTPConfigFromEnv()
+		if err := httpCfg.Validate(); err != nil {
+			logger.Fatal("Invalid HTTP configuration",
+				zap.String("context", "console"),
+				zap.Error(err),
+			)
+		}
+		httpCfg = httpCfg.WithDefaultBaseURL(listenerCfg, host, port)
+
+		sseServer := s.ServeSSE(host+":"+port, httpCfg)
 		logger.Info(
-			fmt.Sprintf("SSE server listening on %s", fmt.Sprintf("%s:%s/sse", host, port)),
+			fmt.Sprintf("SSE server listening on %s", listenAddress(listenerCfg, host, port)+httpCfg.BasePath+"/sse"),
 			zap.String("context", "console"),
 			zap.String("host", host),
 			zap.String("port", port),
+			zap.String("unix_socket", listenerCfg.UnixSocket),
+			zap.String("endpoint", httpCfg.EndpointURL("/sse")),
 		)
 
 		if ready, _ := p.IsReady(); !ready {
@@ -88,7 +115,7 @@ func main() {
 			)
 		}
 
-		if err := sseServer.Start(host + ":" + port); err != nil {
+		if err := s.StartSSE(sseServer, host+":"+port, listenerCfg, httpCfg); err != nil {
 			logger.Fatal("Server error",
 				zap.String("context", "console"),
 				zap.Error(err),
@@ -104,12 +131,31 @@ func main() {
 			port = strconv.Itoa(defaultSsePort)
 		}
 
-		httpServer := s.ServeHTTP(":" + port)
+		listenerCfg := server.ListenerConfigFromEnv()
+		if err := listenerCfg.Validate(); err != nil {
+			logger.Fatal("Invalid listener configuration",
+				zap.String("context", "console"),
+				zap.Error(err),
+			)
+		}
+
+		httpCfg := server.HTTPConfigFromEnv()
+		if err := httpCfg.Validate(); err != nil {
+			logger.Fatal("Invalid HTTP configuration",
+				zap.String("context", "console"),
+				zap.Error(err),
+			)
+		}
+		httpCfg = httpCfg.WithDefaultBaseURL(listenerCfg, host, port)
+
+		httpServer := s.ServeHTTP(host+":"+port, httpCfg)
 		logger.Info(
-			fmt.Sprintf("HTTP server listening on %s", fmt.Sprintf("%s:%s", host, port)),
+			fmt.Sprintf("HTTP server listening on %s", listenAddress(listenerCfg, host, port)),
 			zap.String("context", "console"),
 			zap.String("host", host),
 			zap.String("port", port),
+			zap.String("unix_socket", listenerCfg.UnixSocket),
+			zap.String("endpoint", httpCfg.EndpointURL("/mcp")),
 		)
 
 		if ready, _ := p.IsReady(); !ready {
@@ -118,7 +164,7 @@ func main() {
 			)
 		}
 
-		if err := httpServer.Start(host + ":" + port); err != nil {
+		if err := s.StartHTTP(httpServer, host+":"+port, listenerCfg, httpCfg); err != nil {
 			logger.Fatal("Server error",
 				zap.String("context", "console"),
 				zap.Error(err),
@@ -133,6 +179,31 @@ func main() {
 	}
 }
 
+// newProvider connects to Slack, or opens an archive for "archive:<path>".
+func newProvider(transport, source string, logger *zap.Logger) *provider.ApiProvider {
+	if source == "" || source == "slack" {
+		return provider.New(transport, logger)
+	}
+
+	path, ok := strings.CutPrefix(source, "archive:")
+	if !ok || path == "" {
+		logger.Fatal("Invalid source",
+			zap.String("context", "console"),
+			zap.String("source", source),
+			zap.String("allowed", "slack, archive:<path>"),
+		)
+	}
+	p, err := provider.NewArchive(transport, path, logger)
+	if err != nil {
+		logger.Fatal("Failed to open archive",
+			zap.String("context", "console"),
+			zap.String("path", path),
+			zap.Error(err),
+		)
+	}
+	return p
+}
+
 func newUsersWatcher(p *provider.ApiProvider, once *sync.Once, logger *zap.Logger) func() {
 	return func() {
 		logger.Info("Caching users collection...",
@@ -197,6 +268,14 @@ func newChannelsWatcher(p *provider.ApiProvider, once *sync.Once, logger *zap.Lo
 	}
 }
 
+// listenAddress returns a human readable address the SSE/HTTP server listens on.
+func listenAddress(cfg server.ListenerConfig, host, port string) string {
+	if cfg.UnixSocket != "" {
+		return "unix:" + cfg.UnixSocket
+	}
+	return fmt.Sprintf("%s://%s:%s", cfg.Scheme(), host, port)
+}
+
 func validateToolConfig(config string) error {
 	if config == "" || config == "true" || config == "1" {
 		return nil
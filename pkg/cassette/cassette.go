@@ -0,0 +1,147 @@
+// Package cassette records Slack HTTP traffic to a file and plays it back,
+// so a bug report can carry the exact API responses that triggered it.
+//
+// A cassette is a JSON Lines file with one Interaction per line. Tokens,
+// cookies and authorization headers are scrubbed before anything is written.
+package cassette
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Interaction is a single recorded request and what came back for it.
+type Interaction struct {
+	Request  Request   `json:"request"`
+	Response *Response `json:"response,omitempty"`
+	// Error is set instead of Response when the round trip failed.
+	Error string `json:"error,omitempty"`
+}
+
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	// Body holds text responses, BodyBase64 binary ones such as file downloads.
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"body_base64,omitempty"`
+}
+
+const redacted = "REDACTED"
+
+// secretPattern matches Slack tokens and session cookies, e.g. xoxp-…, xoxc-…, xoxd-….
+var secretPattern = regexp.MustCompile(`xox[a-z]-[0-9A-Za-z%._\-]+`)
+
+// keptHeaders are the only headers written to a cassette, everything else
+// (Authorization, Cookie, Set-Cookie, …) is dropped.
+var keptHeaders = []string{"Content-Type", "Retry-After"}
+
+// volatileFields change between runs without changing the meaning of the
+// request, they are ignored when matching requests during replay.
+var volatileFields = []string{"token", "client_req_id", "browse_session_id", "version_ts", "build_version_ts"}
+
+// Scrub replaces every Slack token and cookie value in s.
+func Scrub(s string) string {
+	return secretPattern.ReplaceAllStringFunc(s, func(secret string) string {
+		return secret[:5] + redacted
+	})
+}
+
+func scrubHeader(h http.Header) http.Header {
+	out := http.Header{}
+	for _, k := range keptHeaders {
+		if v := h.Values(k); len(v) > 0 {
+			out[k] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func scrubURL(u *url.URL) string {
+	cp := *u
+	cp.User = nil
+	if q := cp.Query(); q.Has("token") {
+		q.Set("token", redacted)
+		cp.RawQuery = q.Encode()
+	}
+	return Scrub(cp.String())
+}
+
+func encodeBody(body []byte) (text, b64 string) {
+	if len(body) == 0 {
+		return "", ""
+	}
+	if utf8.Valid(body) {
+		return Scrub(string(body)), ""
+	}
+	return "", base64.StdEncoding.EncodeToString(body)
+}
+
+func (r *Response) body() []byte {
+	if r.BodyBase64 != "" {
+		b, _ := base64.StdEncoding.DecodeString(r.BodyBase64)
+		return b
+	}
+	return []byte(r.Body)
+}
+
+// requestKey identifies a request for replay: method, host, path, query and
+// body, with volatile fields removed and form or JSON keys sorted.
+func requestKey(method, rawURL, body string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return method + " " + rawURL + " " + body
+	}
+	q := u.Query()
+	for _, f := range volatileFields {
+		q.Del(f)
+	}
+	return method + " " + u.Host + u.Path + "?" + q.Encode() + " " + normalizeBody(body)
+}
+
+// routeKey identifies the endpoint only, it is the fallback when no request
+// matches exactly, e.g. because the request carries a time relative to now.
+func routeKey(method, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return method + " " + rawURL
+	}
+	return method + " " + u.Host + u.Path
+}
+
+func normalizeBody(body string) string {
+	trimmed := strings.TrimSpace(body)
+	if strings.HasPrefix(trimmed, "{") {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(trimmed), &m); err == nil {
+			for _, f := range volatileFields {
+				delete(m, f)
+			}
+			b, _ := json.Marshal(m) // map keys are sorted by encoding/json
+			return string(b)
+		}
+	}
+	form, err := url.ParseQuery(body)
+	if err != nil {
+		return body
+	}
+	for _, f := range volatileFields {
+		form.Del(f)
+	}
+	return form.Encode() // sorted by key
+
+}
@@ -0,0 +1,154 @@
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// ErrNotRecorded is returned for requests the cassette has no answer for.
+var ErrNotRecorded = errors.New("cassette: request was not recorded")
+
+// Player is a RoundTripper that answers requests from a cassette instead of
+// the network.
+//
+// Requests are matched on method, URL and body, ignoring tokens and other
+// per-run values. Identical requests get the recorded answers in order, so a
+// retried call sees the same sequence of failures as during recording. When
+// nothing matches exactly, the next unused answer for the same endpoint is
+// used; once they are all used up, the last one is repeated.
+type Player struct {
+	mu     sync.Mutex
+	all    []*recorded
+	exact  map[string][]*recorded
+	routes map[string][]*recorded
+}
+
+type recorded struct {
+	Interaction
+	used bool
+}
+
+// Replay returns the player for the cassette at path, loading it the first
+// time. Every HTTP client of the process shares it.
+func Replay(path string) (*Player, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if p, ok := players[path]; ok {
+		return p, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p, err := Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	players[path] = p
+	return p, nil
+}
+
+// Load reads a cassette.
+func Load(r io.Reader) (*Player, error) {
+	p := &Player{
+		exact:  make(map[string][]*recorded),
+		routes: make(map[string][]*recorded),
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		rec := &recorded{}
+		if err := json.Unmarshal(sc.Bytes(), &rec.Interaction); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		req := rec.Request
+		key := requestKey(req.Method, req.URL, req.Body)
+		route := routeKey(req.Method, req.URL)
+		p.all = append(p.all, rec)
+		p.exact[key] = append(p.exact[key], rec)
+		p.routes[route] = append(p.routes[route], rec)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Len returns the number of recorded interactions.
+func (p *Player) Len() int {
+	return len(p.all)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	rawURL := scrubURL(req.URL)
+	text, _ := encodeBody(body)
+	rec := p.next(requestKey(req.Method, rawURL, text), routeKey(req.Method, rawURL))
+	if rec == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, rawURL)
+	}
+	if rec.Error != "" || rec.Response == nil {
+		return nil, errors.New(rec.Error)
+	}
+
+	header := rec.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	respBody := rec.Response.body()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Response.Status, http.StatusText(rec.Response.Status)),
+		StatusCode:    rec.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+func (p *Player) next(key, route string) *recorded {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, candidates := range [][]*recorded{p.exact[key], p.routes[route]} {
+		for _, rec := range candidates {
+			if !rec.used {
+				rec.used = true
+				return rec
+			}
+		}
+	}
+	if candidates := p.exact[key]; len(candidates) > 0 {
+		return candidates[len(candidates)-1]
+	}
+	if candidates := p.routes[route]; len(candidates) > 0 {
+		return candidates[len(candidates)-1]
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+var (
+	registryMu sync.Mutex
+	recorders  = make(map[string]*Recorder)
+	players    = make(map[string]*Player)
+)
+
+// Recorder appends scrubbed interactions to a cassette file.
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// Record returns the recorder for path. The file is truncated the first time
+// a process asks for it; every HTTP client of the process then shares it, so
+// all Slack traffic ends up in one cassette in the order it happened.
+func Record(path string) (*Recorder, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if r, ok := recorders[path]; ok {
+		return r, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recorder{file: f, enc: json.NewEncoder(f)}
+	recorders[path] = r
+	return r, nil
+}
+
+// Wrap returns a RoundTripper that records every round trip made through next.
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	return &recordingTransport{next: next, recorder: r}
+}
+
+// Close flushes and closes the cassette file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+func (r *Recorder) write(in Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(in)
+}
+
+type recordingTransport struct {
+	next     http.RoundTripper
+	recorder *Recorder
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	in := Interaction{Request: Request{
+		Method: req.Method,
+		URL:    scrubURL(req.URL),
+		Header: scrubHeader(req.Header),
+	}}
+	in.Request.Body, _ = encodeBody(reqBody)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		in.Error = Scrub(err.Error())
+		_ = t.recorder.write(in)
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in.Response = &Response{Status: resp.StatusCode, Header: scrubHeader(resp.Header)}
+	in.Response.Body, in.Response.BodyBase64 = encodeBody(respBody)
+	if err := t.recorder.write(in); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
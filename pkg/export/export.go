@@ -0,0 +1,299 @@
+// Package export writes the full history of a conversation, threads included,
+// to disk as JSON Lines, a Slack export or a single HTML page.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/slack-go/slack"
+)
+
+type Format string
+
+const (
+	// FormatJSONL writes one message per line, replies right after their parent.
+	FormatJSONL Format = "jsonl"
+	// FormatSlack writes a directory in the layout of a Slack workspace export,
+	// which can be opened again with --source archive:<dir>.
+	FormatSlack Format = "slack"
+	// FormatHTML writes a single HTML page with user names resolved.
+	FormatHTML Format = "html"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{FormatJSONL, FormatSlack, FormatHTML}
+
+// ParseFormat validates a format name, empty means JSON Lines.
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return FormatJSONL, nil
+	}
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export format %q, use one of: jsonl, slack, html", s)
+}
+
+// Ext is the file extension of the format, empty for directories.
+func (f Format) Ext() string {
+	switch f {
+	case FormatJSONL:
+		return ".jsonl"
+	case FormatHTML:
+		return ".html"
+	}
+	return ""
+}
+
+// pageSize is the number of messages requested per history or replies call.
+var pageSize = 200
+
+// API is the part of the Slack API an export needs. Calls made through the
+// provider's client go through its per-method rate limiters.
+type API interface {
+	GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
+	GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) (msgs []slack.Message, hasMore bool, nextCursor string, err error)
+	GetFileContext(ctx context.Context, downloadURL string, writer io.Writer) error
+}
+
+type Options struct {
+	Channel provider.Channel
+	Users   map[string]slack.User
+	// Oldest and Latest bound the export, zero values leave it open.
+	Oldest time.Time
+	Latest time.Time
+	Format Format
+	// Output is the file to write, or the directory for FormatSlack.
+	Output string
+	// Files downloads attached files next to the export.
+	Files bool
+}
+
+// Result summarises a finished export.
+type Result struct {
+	Output   string
+	Messages int
+	Threads  int
+	Replies  int
+	Files    int
+}
+
+// thread is a top level message and, when it started a thread, its replies.
+type thread struct {
+	Parent  slack.Message
+	Replies []slack.Message
+}
+
+// Run fetches the history of opts.Channel with all thread replies and writes it
+// in the requested format.
+func Run(ctx context.Context, api API, opts Options) (*Result, error) {
+	if opts.Channel.ID == "" {
+		return nil, errors.New("channel is required")
+	}
+	if opts.Output == "" {
+		return nil, errors.New("output path is required")
+	}
+	if opts.Format == "" {
+		opts.Format = FormatJSONL
+	}
+
+	threads, err := fetch(ctx, api, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Output: opts.Output}
+	for _, t := range threads {
+		res.Messages += 1 + len(t.Replies)
+		res.Replies += len(t.Replies)
+		if len(t.Replies) > 0 {
+			res.Threads++
+		}
+	}
+
+	var files map[string]string
+	if opts.Files {
+		if files, err = downloadFiles(ctx, api, threads, attachmentsDir(opts)); err != nil {
+			return nil, err
+		}
+		res.Files = len(files)
+	}
+
+	switch opts.Format {
+	case FormatJSONL:
+		err = writeFile(opts.Output, func(w io.Writer) error { return writeJSONL(w, opts, threads) })
+	case FormatSlack:
+		err = writeSlackExport(opts, threads)
+	case FormatHTML:
+		err = writeFile(opts.Output, func(w io.Writer) error { return writeHTML(w, opts, threads, files) })
+	default:
+		err = fmt.Errorf("unknown export format %q", opts.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func fetch(ctx context.Context, api API, opts Options) ([]thread, error) {
+	params := &slack.GetConversationHistoryParameters{
+		ChannelID: opts.Channel.ID,
+		Limit:     pageSize,
+		Oldest:    toTS(opts.Oldest),
+		Latest:    toTS(opts.Latest),
+		Inclusive: true,
+	}
+
+	var threads []thread
+	for {
+		history, err := api.GetConversationHistoryContext(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch history of %s: %w", opts.Channel.ID, err)
+		}
+		for _, m := range history.Messages {
+			threads = append(threads, thread{Parent: m})
+		}
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		params.Cursor = history.ResponseMetaData.NextCursor
+	}
+	// history comes newest first
+	sort.SliceStable(threads, func(i, j int) bool { return threads[i].Parent.Timestamp < threads[j].Parent.Timestamp })
+
+	for i := range threads {
+		parent := &threads[i].Parent
+		if parent.ReplyCount == 0 || parent.ThreadTimestamp != parent.Timestamp {
+			continue
+		}
+		replies, err := fetchReplies(ctx, api, opts.Channel.ID, parent.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		threads[i].Replies = replies
+	}
+	return threads, nil
+}
+
+func fetchReplies(ctx context.Context, api API, channelID, ts string) ([]slack.Message, error) {
+	params := &slack.GetConversationRepliesParameters{
+		ChannelID: channelID,
+		Timestamp: ts,
+		Limit:     pageSize,
+	}
+
+	var replies []slack.Message
+	for {
+		msgs, hasMore, next, err := api.GetConversationRepliesContext(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch replies of %s/%s: %w", channelID, ts, err)
+		}
+		for _, m := range msgs {
+			// every page starts with the parent
+			if m.Timestamp != ts {
+				replies = append(replies, m)
+			}
+		}
+		if !hasMore || next == "" {
+			break
+		}
+		params.Cursor = next
+	}
+	return replies, nil
+}
+
+func toTS(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d.000000", t.Unix())
+}
+
+// attachmentsDir is where downloaded files go, for Slack exports the same
+// place slackdump puts them so the archive source finds them again.
+func attachmentsDir(opts Options) string {
+	if opts.Format == FormatSlack {
+		return filepath.Join(opts.Output, folderName(opts.Channel), "attachments")
+	}
+	return filepath.Join(filepath.Dir(opts.Output), "attachments")
+}
+
+// downloadFiles saves every attached file and returns their paths relative to
+// the parent of the attachments directory, keyed by file ID.
+func downloadFiles(ctx context.Context, api API, threads []thread, dir string) (map[string]string, error) {
+	files := make(map[string]string)
+	for _, t := range threads {
+		for _, m := range append([]slack.Message{t.Parent}, t.Replies...) {
+			for _, f := range m.Files {
+				url := f.URLPrivateDownload
+				if url == "" {
+					url = f.URLPrivate
+				}
+				if f.ID == "" || url == "" || f.Mode == "tombstone" || f.IsExternal {
+					continue
+				}
+				if _, ok := files[f.ID]; ok {
+					continue
+				}
+
+				name := f.ID + "-" + filepath.Base(filepath.Clean("/"+f.Name))
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					return nil, err
+				}
+				err := writeFile(filepath.Join(dir, name), func(w io.Writer) error {
+					return api.GetFileContext(ctx, url, w)
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to download file %s: %w", f.ID, err)
+				}
+				files[f.ID] = filepath.ToSlash(filepath.Join(filepath.Base(dir), name))
+			}
+		}
+	}
+	return files, nil
+}
+
+// writeFile writes to path through a temporary file, so a failed export does
+// not leave a truncated file behind.
+func writeFile(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// userName returns the display name for a user ID, falling back to the ID.
+func userName(users map[string]slack.User, id string) string {
+	u, ok := users[id]
+	if !ok {
+		return id
+	}
+	if u.RealName != "" {
+		return u.RealName
+	}
+	if u.Profile.DisplayName != "" {
+		return u.Profile.DisplayName
+	}
+	return u.Name
+}
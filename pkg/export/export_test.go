@@ -0,0 +1,161 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/archive"
+	"github.com/korotovsky/slack-mcp-server/pkg/test/fakeslack"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var general = provider.Channel{ID: "C001", Name: "#general", Topic: "Company wide announcements", Members: []string{"U001", "U002", "U003"}}
+
+func newAPI(t *testing.T) (*slack.Client, *fakeslack.Server, map[string]slack.User) {
+	t.Helper()
+	srv := fakeslack.New()
+	t.Cleanup(srv.Close)
+
+	api := slack.New(fakeslack.Token, slack.OptionAPIURL(srv.APIURL()))
+	list, err := api.GetUsers()
+	require.NoError(t, err)
+	users := make(map[string]slack.User)
+	for _, u := range list {
+		users[u.ID] = u
+	}
+	return api, srv, users
+}
+
+func TestUnitExportJSONL(t *testing.T) {
+	api, _, users := newAPI(t)
+	defer func(size int) { pageSize = size }(pageSize)
+	pageSize = 1
+
+	out := filepath.Join(t.TempDir(), "general.jsonl")
+	res, err := Run(context.Background(), api, Options{Channel: general, Users: users, Format: FormatJSONL, Output: out})
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Output: out, Messages: 4, Threads: 1, Replies: 2}, res)
+
+	f, err := os.Open(out)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []record
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var rec record
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &rec))
+		lines = append(lines, rec)
+	}
+	require.Len(t, lines, 4)
+	assert.Equal(t, "Welcome to the fake workspace", lines[0].Text)
+	assert.Equal(t, "Bob Example", lines[0].UserName)
+	assert.Equal(t, "Release planning thread", lines[1].Text)
+	assert.Equal(t, "I can take the changelog", lines[2].Text)
+	assert.Equal(t, "I will update the docs", lines[3].Text)
+	assert.Equal(t, "C001", lines[3].Channel)
+}
+
+func TestUnitExportDateRange(t *testing.T) {
+	api, srv, users := newAPI(t)
+	msgs := srv.Messages("C001")
+
+	out := filepath.Join(t.TempDir(), "general.jsonl")
+	res, err := Run(context.Background(), api, Options{
+		Channel: general,
+		Users:   users,
+		Oldest:  tsTime(msgs[1].Timestamp),
+		Output:  out,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Messages)
+}
+
+func TestUnitExportSlackRoundTrip(t *testing.T) {
+	api, srv, users := newAPI(t)
+	require.True(t, srv.AttachFile("C001", srv.Messages("C001")[0].Timestamp, "F001"))
+
+	out := t.TempDir()
+	res, err := Run(context.Background(), api, Options{Channel: general, Users: users, Format: FormatSlack, Output: out, Files: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Files)
+	assert.FileExists(t, filepath.Join(out, "general", "attachments", "F001-notes.txt"))
+
+	// a second channel is added to the same export
+	random := provider.Channel{ID: "C002", Name: "#random"}
+	_, err = Run(context.Background(), api, Options{Channel: random, Users: users, Format: FormatSlack, Output: out})
+	require.NoError(t, err)
+
+	c, err := archive.Open(context.Background(), out, zap.NewNop())
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx := context.Background()
+	chans, _, err := c.GetConversationsContext(ctx, &slack.GetConversationsParameters{})
+	require.NoError(t, err)
+	assert.Len(t, chans, 2)
+
+	history, err := c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C001"})
+	require.NoError(t, err)
+	require.Len(t, history.Messages, 2)
+	parent := history.Messages[0]
+	assert.Equal(t, "Release planning thread", parent.Text)
+
+	replies, _, _, err := c.GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{ChannelID: "C001", Timestamp: parent.Timestamp})
+	require.NoError(t, err)
+	assert.Len(t, replies, 3)
+
+	var buf bytes.Buffer
+	f, _, _, err := c.GetFileInfoContext(ctx, "F001", 0, 0)
+	require.NoError(t, err)
+	require.NoError(t, c.GetFileContext(ctx, f.URLPrivate, &buf))
+	assert.Equal(t, "Meeting notes: ship it.", buf.String())
+}
+
+func TestUnitExportHTML(t *testing.T) {
+	api, srv, users := newAPI(t)
+	srv.AddMessage("C001", "U001", "Thanks <@U002>, see <https://example.com|the plan> &lt;b&gt;now&lt;/b&gt;", "")
+
+	out := filepath.Join(t.TempDir(), "general.html")
+	_, err := Run(context.Background(), api, Options{
+		Channel: general,
+		Users:   users,
+		Format:  FormatHTML,
+		Output:  out,
+		Oldest:  time.Unix(1600000000, 0),
+	})
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile(out)
+	require.NoError(t, err)
+	page := string(raw)
+	assert.Contains(t, page, "<title>#general</title>")
+	assert.Contains(t, page, "Since 2020-09-13")
+	assert.Contains(t, page, `<span class="author">Carol Example</span>`)
+	assert.Contains(t, page, `<div class="thread">`)
+	assert.Contains(t, page, `Thanks @Bob Example, see <a href="https://example.com">the plan</a> &lt;b&gt;now&lt;/b&gt;`)
+	assert.Contains(t, page, ":thumbsup: 1")
+}
+
+func TestUnitParseFormat(t *testing.T) {
+	f, err := ParseFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSONL, f)
+
+	f, err = ParseFormat("HTML")
+	require.NoError(t, err)
+	assert.Equal(t, FormatHTML, f)
+
+	_, err = ParseFormat("pdf")
+	assert.EqualError(t, err, `unknown export format "pdf", use one of: jsonl, slack, html`)
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	"html"
+	"html/template"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+var pageTemplate = template.Must(template.New("export").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 60em; color: #1d1c1d; }
+h1 { font-size: 1.5em; margin-bottom: 0; }
+.range { color: #616061; margin-bottom: 2em; }
+.msg { padding: .4em 0; }
+.author { font-weight: bold; }
+.time { color: #616061; font-size: .85em; margin-left: .5em; }
+.text { white-space: pre-wrap; }
+.thread { border-left: 3px solid #ddd; margin: .2em 0 .6em 1em; padding-left: 1em; }
+.files, .reactions { font-size: .85em; color: #616061; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="range">{{.Range}}</div>
+{{range .Threads}}{{template "msg" .Parent}}{{if .Replies}}<div class="thread">
+{{range .Replies}}{{template "msg" .}}{{end}}</div>
+{{end}}{{end}}</body>
+</html>
+{{define "msg"}}<div class="msg" id="m{{.ID}}"><span class="author">{{.Author}}</span><span class="time">{{.Time}}</span>
+<div class="text">{{.Text}}</div>{{if .Files}}
+<div class="files">{{range .Files}}{{if .Href}}<a href="{{.Href}}">{{.Name}}</a>{{else}}{{.Name}}{{end}} {{end}}</div>{{end}}{{if .Reactions}}
+<div class="reactions">{{.Reactions}}</div>{{end}}
+</div>
+{{end}}`))
+
+type htmlPage struct {
+	Title   string
+	Range   string
+	Threads []htmlThread
+}
+
+type htmlThread struct {
+	Parent  htmlMessage
+	Replies []htmlMessage
+}
+
+type htmlMessage struct {
+	ID        string
+	Author    string
+	Time      string
+	Text      template.HTML
+	Files     []htmlFile
+	Reactions string
+}
+
+type htmlFile struct {
+	Name string
+	Href string
+}
+
+// writeHTML renders the export as one page without external resources, files
+// is the relative path of every downloaded file by ID.
+func writeHTML(w io.Writer, opts Options, threads []thread, files map[string]string) error {
+	page := htmlPage{Title: opts.Channel.Name, Range: "Full history"}
+	if page.Title == "" {
+		page.Title = opts.Channel.ID
+	}
+	switch {
+	case !opts.Oldest.IsZero() && !opts.Latest.IsZero():
+		page.Range = opts.Oldest.Format("2006-01-02") + " – " + opts.Latest.Format("2006-01-02")
+	case !opts.Oldest.IsZero():
+		page.Range = "Since " + opts.Oldest.Format("2006-01-02")
+	case !opts.Latest.IsZero():
+		page.Range = "Until " + opts.Latest.Format("2006-01-02")
+	}
+
+	for _, t := range threads {
+		ht := htmlThread{Parent: toHTMLMessage(t.Parent, opts.Users, files)}
+		for _, r := range t.Replies {
+			ht.Replies = append(ht.Replies, toHTMLMessage(r, opts.Users, files))
+		}
+		page.Threads = append(page.Threads, ht)
+	}
+	return pageTemplate.Execute(w, page)
+}
+
+func toHTMLMessage(m slack.Message, users map[string]slack.User, files map[string]string) htmlMessage {
+	author := m.Username
+	if m.User != "" {
+		author = userName(users, m.User)
+	}
+	if author == "" {
+		author = m.BotID
+	}
+
+	hm := htmlMessage{
+		ID:     strings.ReplaceAll(m.Timestamp, ".", ""),
+		Author: author,
+		Time:   tsTime(m.Timestamp).UTC().Format("2006-01-02 15:04 UTC"),
+		Text:   renderText(m.Text, users),
+	}
+	for _, f := range m.Files {
+		hm.Files = append(hm.Files, htmlFile{Name: f.Name, Href: files[f.ID]})
+	}
+	var reactions []string
+	for _, r := range m.Reactions {
+		reactions = append(reactions, ":"+r.Name+": "+strconv.Itoa(r.Count))
+	}
+	hm.Reactions = strings.Join(reactions, "  ")
+	return hm
+}
+
+// entityPattern matches Slack's <…> markup: mentions, channel links and URLs.
+var entityPattern = regexp.MustCompile(`<([^<>]+)>`)
+
+// renderText turns Slack markup into HTML, resolving user mentions to names.
+func renderText(text string, users map[string]slack.User) template.HTML {
+	var b strings.Builder
+	last := 0
+	for _, loc := range entityPattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(escape(text[last:loc[0]]))
+		last = loc[1]
+
+		target, label, _ := strings.Cut(text[loc[2]:loc[3]], "|")
+		switch {
+		case strings.HasPrefix(target, "@"):
+			b.WriteString(escape("@" + userName(users, target[1:])))
+		case strings.HasPrefix(target, "#"):
+			if label == "" {
+				label = target[1:]
+			}
+			b.WriteString(escape("#" + label))
+		case strings.HasPrefix(target, "!"):
+			if label == "" {
+				label = "@" + target[1:]
+			}
+			b.WriteString(escape(label))
+		case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"), strings.HasPrefix(target, "mailto:"):
+			if label == "" {
+				label = target
+			}
+			b.WriteString(`<a href="` + html.EscapeString(html.UnescapeString(target)) + `">` + escape(label) + `</a>`)
+		default:
+			b.WriteString(escape(text[loc[0]:loc[1]]))
+		}
+	}
+	b.WriteString(escape(text[last:]))
+	return template.HTML(b.String())
+}
+
+// escape HTML-escapes Slack text, which arrives with &, < and > already escaped.
+func escape(s string) string {
+	return html.EscapeString(html.UnescapeString(s))
+}
@@ -0,0 +1,182 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/slack-go/slack"
+)
+
+// record is a JSON Lines entry: the message as Slack returned it plus the
+// resolved name of its author.
+type record struct {
+	slack.Message
+	UserName string `json:"user_name,omitempty"`
+}
+
+func writeJSONL(w io.Writer, opts Options, threads []thread) error {
+	enc := json.NewEncoder(w)
+	for _, t := range threads {
+		for _, m := range append([]slack.Message{t.Parent}, t.Replies...) {
+			m.Channel = opts.Channel.ID
+			rec := record{Message: m}
+			if m.User != "" {
+				rec.UserName = userName(opts.Users, m.User)
+			}
+			if err := enc.Encode(rec); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// exportChannel is an entry of channels.json and friends in a Slack export.
+type exportChannel struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name,omitempty"`
+	Created   int64          `json:"created"`
+	IsPrivate bool           `json:"is_private,omitempty"`
+	Members   []string       `json:"members,omitempty"`
+	Topic     *exportPurpose `json:"topic,omitempty"`
+	Purpose   *exportPurpose `json:"purpose,omitempty"`
+}
+
+type exportPurpose struct {
+	Value string `json:"value"`
+}
+
+// writeSlackExport writes the layout of a Slack workspace export: users.json,
+// the channel in channels.json, groups.json, dms.json or mpims.json and one
+// file per day in the channel's folder. Several channels can be exported into
+// the same directory.
+func writeSlackExport(opts Options, threads []thread) error {
+	ch := opts.Channel
+	folder := filepath.Join(opts.Output, folderName(ch))
+	if err := os.MkdirAll(folder, 0o755); err != nil {
+		return err
+	}
+
+	users := make([]slack.User, 0, len(opts.Users))
+	for _, u := range opts.Users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	if err := writeJSON(filepath.Join(opts.Output, "users.json"), users); err != nil {
+		return err
+	}
+
+	if err := addToIndex(opts.Output, ch, threads); err != nil {
+		return err
+	}
+
+	days := make(map[string][]slack.Message)
+	for _, t := range threads {
+		parent := t.Parent
+		if len(t.Replies) > 0 {
+			parent.Replies = nil
+			for _, r := range t.Replies {
+				parent.Replies = append(parent.Replies, slack.Reply{User: r.User, Timestamp: r.Timestamp})
+			}
+		}
+		for _, m := range append([]slack.Message{parent}, t.Replies...) {
+			day := tsTime(m.Timestamp).UTC().Format("2006-01-02")
+			days[day] = append(days[day], m)
+		}
+	}
+	for day, msgs := range days {
+		sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].Timestamp < msgs[j].Timestamp })
+		if err := writeJSON(filepath.Join(folder, day+".json"), msgs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addToIndex(dir string, ch provider.Channel, threads []thread) error {
+	entry := exportChannel{
+		ID:        ch.ID,
+		IsPrivate: ch.IsPrivate,
+		Members:   ch.Members,
+	}
+	if len(threads) > 0 {
+		entry.Created = tsTime(threads[0].Parent.Timestamp).Unix()
+	}
+
+	index := "channels.json"
+	switch {
+	case ch.IsIM:
+		index = "dms.json"
+		if len(entry.Members) == 0 && ch.User != "" {
+			entry.Members = []string{ch.User}
+		}
+	case ch.IsMpIM:
+		index = "mpims.json"
+		entry.Name = folderName(ch)
+	default:
+		if ch.IsPrivate {
+			index = "groups.json"
+		}
+		entry.Name = folderName(ch)
+		entry.Topic = &exportPurpose{Value: ch.Topic}
+		entry.Purpose = &exportPurpose{Value: ch.Purpose}
+	}
+
+	path := filepath.Join(dir, index)
+	var entries []exportChannel
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	replaced := false
+	for i := range entries {
+		if entries[i].ID == entry.ID {
+			entries[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		entries = append(entries, entry)
+	}
+	return writeJSON(path, entries)
+}
+
+// folderName is the directory of a conversation in a Slack export: the name
+// for channels and group DMs, the ID for DMs.
+func folderName(ch provider.Channel) string {
+	if ch.IsIM {
+		return ch.ID
+	}
+	name := strings.TrimLeft(ch.Name, "#@")
+	if name == "" {
+		return ch.ID
+	}
+	return name
+}
+
+func writeJSON(path string, v any) error {
+	return writeFile(path, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	})
+}
+
+func tsTime(ts string) time.Time {
+	sec, _, _ := strings.Cut(ts, ".")
+	unix, _ := strconv.ParseInt(sec, 10, 64)
+	return time.Unix(unix, 0)
+}
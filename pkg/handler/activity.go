@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultActivitySince = "1d"
+	// activityMaxPages bounds how many pages of 100 results each search of
+	// activity_mentions reads.
+	activityMaxPages = 5
+)
+
+// Activity kinds, in the order a message found by several searches is
+// reported as.
+const (
+	activityMention      = "mention"
+	activityGroupMention = "group_mention"
+	activityThreadReply  = "thread_reply"
+)
+
+var durationExpression = regexp.MustCompile(`^\d+[dwm]$`)
+
+// Activity is a message that involves the user, Thread is the ts of the
+// thread it belongs to or its own ts when it isn't in a thread.
+type Activity struct {
+	Thread    string `json:"thread"`
+	Kind      string `json:"kind"`
+	MsgID     string `json:"msgID"`
+	UserID    string `json:"userID"`
+	UserName  string `json:"userName"`
+	RealName  string `json:"realName"`
+	Channel   string `json:"channelID"`
+	ThreadTs  string `json:"threadTs"`
+	Text      string `json:"text"`
+	Time      string `json:"time"`
+	Permalink string `json:"permalink"`
+	Replied   bool   `json:"replied"`
+}
+
+type activityParams struct {
+	oldest string
+	after  string
+	limit  int
+}
+
+type activitySearch struct {
+	kind  string
+	query string
+}
+
+// activityHit is a search match together with its converted row.
+type activityHit struct {
+	raw slack.SearchMessage
+	msg Message
+}
+
+// ActivityMentionsHandler finds the messages that mention the user or one of
+// their user groups, and the replies to threads they posted in, grouped by
+// thread with the most recent activity first.
+func (ch *ConversationsHandler) ActivityMentionsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ActivityMentionsHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	params, err := ch.parseParamsToolActivity(request)
+	if err != nil {
+		ch.logger.Error("Failed to parse activity params", zap.Error(err))
+		return nil, err
+	}
+
+	ar, err := ch.apiProvider.AuthResponse()
+	if err != nil {
+		ch.logger.Error("Slack AuthTest failed", zap.Error(err))
+		return nil, err
+	}
+	me := ar.UserID
+
+	searches := []activitySearch{{activityMention, fmt.Sprintf("<@%s>", me)}}
+	for _, id := range ch.userGroupsOf(ctx, me) {
+		searches = append(searches, activitySearch{activityGroupMention, fmt.Sprintf("<!subteam^%s>", id)})
+	}
+	searches = append(searches, activitySearch{activityThreadReply, fmt.Sprintf("is:thread with:<@%s>", me)})
+
+	seen := make(map[string]bool)
+	var items []Activity
+	for _, s := range searches {
+		hits, err := ch.searchActivity(ctx, s.query+" after:"+params.after, params.oldest)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range hits {
+			key := h.raw.Channel.ID + "/" + h.raw.Timestamp
+			if h.raw.User == me || seen[key] {
+				continue
+			}
+			if s.kind == activityThreadReply && (h.msg.ThreadTs == "" || h.msg.ThreadTs == h.msg.MsgID) {
+				// the parent of a thread is not a reply
+				continue
+			}
+			seen[key] = true
+			items = append(items, newActivity(s.kind, h))
+		}
+	}
+
+	own, err := ch.searchActivity(ctx, fmt.Sprintf("from:<@%s> after:%s", me, params.after), params.oldest)
+	if err != nil {
+		return nil, err
+	}
+	ch.markReplied(items, own)
+
+	items = groupActivity(items)
+	if len(items) > params.limit {
+		items = items[:params.limit]
+	}
+	ch.logger.Debug("Collected activity", zap.Int("count", len(items)))
+
+	csvBytes, err := gocsv.MarshalBytes(&items)
+	if err != nil {
+		ch.logger.Error("Failed to marshal activity to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// userGroupsOf returns the IDs of the user groups user belongs to, taken from
+// the user groups cache. Without the usergroups:read scope the cache has no groups
+// and client.userBoot is asked instead.
+func (ch *ConversationsHandler) userGroupsOf(ctx context.Context, user string) []string {
+	groups := ch.apiProvider.ProvideUserGroups(ctx).UserGroups
+	if len(groups) == 0 {
+		boot, err := ch.apiProvider.Slack().ClientUserBoot(ctx)
+		if err != nil {
+			ch.logger.Warn("Failed to list user groups, skipping group mentions", zap.Error(err))
+			return nil
+		}
+		return boot.Subteams.Self
+	}
+
+	var ids []string
+	for _, g := range groups {
+		if slices.Contains(g.Users, user) {
+			ids = append(ids, g.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// searchActivity runs query and returns the matches posted at or after
+// oldest, newest first.
+func (ch *ConversationsHandler) searchActivity(ctx context.Context, query, oldest string) ([]activityHit, error) {
+	var hits []activityHit
+	for page := 1; page <= activityMaxPages; page++ {
+		res, _, err := ch.apiProvider.Slack().SearchContext(ctx, query, slack.SearchParameters{
+			Sort:          "timestamp",
+			SortDirection: "desc",
+			Count:         100,
+			Page:          page,
+		})
+		if err != nil {
+			ch.logger.Error("Slack SearchContext failed", zap.String("query", query), zap.Error(err))
+			return nil, err
+		}
+		for _, m := range res.Matches {
+			if m.Timestamp < oldest {
+				continue
+			}
+			converted := ch.convertMessagesFromSearch(ctx, []slack.SearchMessage{m})
+			if len(converted) == 0 {
+				continue
+			}
+			hits = append(hits, activityHit{raw: m, msg: converted[0]})
+		}
+		if res.Pagination.Page >= res.Pagination.PageCount {
+			break
+		}
+	}
+	return hits, nil
+}
+
+func newActivity(kind string, h activityHit) Activity {
+	thread := h.msg.ThreadTs
+	if thread == "" {
+		thread = h.msg.MsgID
+	}
+	return Activity{
+		Thread:    thread,
+		Kind:      kind,
+		MsgID:     h.msg.MsgID,
+		UserID:    h.msg.UserID,
+		UserName:  h.msg.UserName,
+		RealName:  h.msg.RealName,
+		Channel:   h.raw.Channel.ID,
+		ThreadTs:  h.msg.ThreadTs,
+		Text:      h.msg.Text,
+		Time:      h.msg.Time,
+		Permalink: h.raw.Permalink,
+	}
+}
+
+// markReplied flags the items the user answered: they posted later in the
+// same thread, or later in the same DM.
+func (ch *ConversationsHandler) markReplied(items []Activity, own []activityHit) {
+	channels := ch.apiProvider.ProvideChannelsMaps().Channels
+	for i := range items {
+		it := &items[i]
+		c := channels[it.Channel]
+		direct := c.IsIM || c.IsMpIM
+		for _, o := range own {
+			if o.raw.Channel.ID != it.Channel || o.msg.MsgID <= it.MsgID {
+				continue
+			}
+			if o.msg.ThreadTs == it.Thread || (direct && it.ThreadTs == "" && o.msg.ThreadTs == "") {
+				it.Replied = true
+				break
+			}
+		}
+	}
+}
+
+// groupActivity orders items by thread, threads with the most recent activity
+// first and the messages of a thread oldest first.
+func groupActivity(items []Activity) []Activity {
+	type group struct {
+		latest string
+		items  []Activity
+	}
+	groups := make(map[string]*group)
+	var keys []string
+	for _, it := range items {
+		key := it.Channel + "/" + it.Thread
+		g, ok := groups[key]
+		if !ok {
+			g = &group{}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		g.items = append(g.items, it)
+		if it.MsgID > g.latest {
+			g.latest = it.MsgID
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return groups[keys[i]].latest > groups[keys[j]].latest })
+
+	res := make([]Activity, 0, len(items))
+	for _, key := range keys {
+		g := groups[key].items
+		sort.SliceStable(g, func(i, j int) bool { return g[i].MsgID < g[j].MsgID })
+		res = append(res, g...)
+	}
+	return res
+}
+
+func (ch *ConversationsHandler) parseParamsToolActivity(request mcp.CallToolRequest) (*activityParams, error) {
+	since := request.GetString("since", defaultActivitySince)
+	if since == "" {
+		since = defaultActivitySince
+	}
+
+	var from time.Time
+	if durationExpression.MatchString(since) {
+		_, oldest, _, err := limitByExpression(since, defaultActivitySince)
+		if err != nil {
+			return nil, err
+		}
+		var secs int64
+		if _, err := fmt.Sscanf(oldest, "%d.", &secs); err != nil {
+			return nil, err
+		}
+		from = time.Unix(secs, 0)
+	} else {
+		t, _, err := parseFlexibleDate(since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since %q: %w", since, err)
+		}
+		from = t
+	}
+
+	limit := request.GetInt("limit", 100)
+	if limit <= 0 {
+		return nil, errors.New("limit must be a positive integer")
+	}
+
+	return &activityParams{
+		oldest: fmt.Sprintf("%d.000000", from.Unix()),
+		// search dates are whole days and after: excludes the day itself
+		after: from.AddDate(0, 0, -1).Format("2006-01-02"),
+		limit: limit,
+	}, nil
+}
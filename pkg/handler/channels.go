@@ -4,11 +4,16 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"math"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/gocarina/gocsv"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
 	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
 	"github.com/korotovsky/slack-mcp-server/pkg/text"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -24,6 +29,20 @@ type Channel struct {
 	Cursor      string `json:"cursor"`
 }
 
+// ChannelEntry is a row of the channels resource, which describes channels in
+// more detail than channels_list. Connected teams are space separated.
+type ChannelEntry struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Topic          string `json:"topic"`
+	Purpose        string `json:"purpose"`
+	MemberCount    int    `json:"memberCount"`
+	IsArchived     bool   `json:"isArchived"`
+	IsShared       bool   `json:"isShared"`
+	IsExtShared    bool   `json:"isExtShared"`
+	ConnectedTeams string `json:"connectedTeams"`
+}
+
 type ChannelsHandler struct {
 	apiProvider *provider.ApiProvider
 	validTypes  map[string]bool
@@ -52,7 +71,7 @@ func (ch *ChannelsHandler) ChannelsResource(ctx context.Context, request mcp.Rea
 		return nil, err
 	}
 
-	var channelList []Channel
+	var channelList []ChannelEntry
 
 	if ready, err := ch.apiProvider.IsReady(); !ready {
 		ch.logger.Error("API provider not ready", zap.Error(err))
@@ -78,12 +97,16 @@ func (ch *ChannelsHandler) ChannelsResource(ctx context.Context, request mcp.Rea
 	ch.logger.Debug("Retrieved channels from provider", zap.Int("count", len(channels)))
 
 	for _, channel := range channels {
-		channelList = append(channelList, Channel{
-			ID:          channel.ID,
-			Name:        channel.Name,
-			Topic:       channel.Topic,
-			Purpose:     channel.Purpose,
-			MemberCount: channel.MemberCount,
+		channelList = append(channelList, ChannelEntry{
+			ID:             channel.ID,
+			Name:           channel.Name,
+			Topic:          channel.Topic,
+			Purpose:        channel.Purpose,
+			MemberCount:    channel.MemberCount,
+			IsArchived:     channel.IsArchived,
+			IsShared:       channel.IsShared,
+			IsExtShared:    channel.IsExtShared,
+			ConnectedTeams: strings.Join(channel.ConnectedTeamIDs, " "),
 		})
 	}
 
@@ -102,6 +125,27 @@ func (ch *ChannelsHandler) ChannelsResource(ctx context.Context, request mcp.Rea
 	}, nil
 }
 
+// cursorSeparator splits the sort key from the channel ID in channels_list
+// cursors.
+const cursorSeparator = "\x00"
+
+// channelsListParams are the channels_list filters, zero values don't filter.
+type channelsListParams struct {
+	types      []string
+	sort       string
+	name       string
+	nameRegex  *regexp.Regexp
+	text       string
+	memberOnly bool
+	archived   string
+	shared     string
+	team       string
+	minMembers int
+	maxMembers int
+	cursor     string
+	limit      int
+}
+
 func (ch *ChannelsHandler) ChannelsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	ch.logger.Debug("ChannelsHandler called")
 
@@ -110,64 +154,28 @@ func (ch *ChannelsHandler) ChannelsHandler(ctx context.Context, request mcp.Call
 		return nil, err
 	}
 
-	sortType := request.GetString("sort", "popularity")
-	types := request.GetString("channel_types", provider.PubChanType)
-	cursor := request.GetString("cursor", "")
-	limit := request.GetInt("limit", 0)
-
-	ch.logger.Debug("Request parameters",
-		zap.String("sort", sortType),
-		zap.String("channel_types", types),
-		zap.String("cursor", cursor),
-		zap.Int("limit", limit),
-	)
-
-	// MCP Inspector v0.14.0 has issues with Slice type
-	// introspection, so some type simplification makes sense here
-	channelTypes := []string{}
-	for _, t := range strings.Split(types, ",") {
-		t = strings.TrimSpace(t)
-		if ch.validTypes[t] {
-			channelTypes = append(channelTypes, t)
-		} else if t != "" {
-			ch.logger.Warn("Invalid channel type ignored", zap.String("type", t))
-		}
-	}
-
-	if len(channelTypes) == 0 {
-		ch.logger.Debug("No valid channel types provided, using defaults")
-		channelTypes = append(channelTypes, provider.PubChanType)
-		channelTypes = append(channelTypes, provider.PrivateChanType)
-	}
-
-	ch.logger.Debug("Validated channel types", zap.Strings("types", channelTypes))
-
-	if limit == 0 {
-		limit = 100
-		ch.logger.Debug("Limit not provided, using default", zap.Int("limit", limit))
-	}
-	if limit > 999 {
-		ch.logger.Warn("Limit exceeds maximum, capping to 999", zap.Int("requested", limit))
-		limit = 999
+	params, err := ch.parseParamsToolChannels(request)
+	if err != nil {
+		ch.logger.Error("Failed to parse channels_list params", zap.Error(err))
+		return nil, err
 	}
 
-	var (
-		nextcur     string
-		channelList []Channel
-	)
+	var channelList []Channel
 
 	allChannels := ch.apiProvider.ProvideChannelsMaps().Channels
 	ch.logger.Debug("Total channels available", zap.Int("count", len(allChannels)))
 
-	channels := filterChannelsByTypes(allChannels, channelTypes)
+	channels := filterChannelsByTypes(allChannels, params.types)
 	ch.logger.Debug("Channels after filtering by type", zap.Int("count", len(channels)))
 
-	var chans []provider.Channel
+	channels = filterChannels(channels, params)
+	ch.logger.Debug("Channels after filtering", zap.Int("count", len(channels)))
 
-	chans, nextcur = paginateChannels(
+	chans, nextcur := paginateChannels(
 		channels,
-		cursor,
-		limit,
+		ch.channelSortKey(ctx, params.sort),
+		params.cursor,
+		params.limit,
 	)
 
 	ch.logger.Debug("Pagination results",
@@ -185,16 +193,6 @@ func (ch *ChannelsHandler) ChannelsHandler(ctx context.Context, request mcp.Call
 		})
 	}
 
-	switch sortType {
-	case "popularity":
-		ch.logger.Debug("Sorting channels by popularity (member count)")
-		sort.Slice(channelList, func(i, j int) bool {
-			return channelList[i].MemberCount > channelList[j].MemberCount
-		})
-	default:
-		ch.logger.Debug("No sorting applied", zap.String("sort_type", sortType))
-	}
-
 	if len(channelList) > 0 && nextcur != "" {
 		channelList[len(channelList)-1].Cursor = nextcur
 		ch.logger.Debug("Added cursor to last channel", zap.String("cursor", nextcur))
@@ -209,6 +207,81 @@ func (ch *ChannelsHandler) ChannelsHandler(ctx context.Context, request mcp.Call
 	return mcp.NewToolResultText(string(csvBytes)), nil
 }
 
+func (ch *ChannelsHandler) parseParamsToolChannels(request mcp.CallToolRequest) (*channelsListParams, error) {
+	params := &channelsListParams{
+		sort:       request.GetString("sort", "popularity"),
+		name:       strings.ToLower(strings.TrimLeft(strings.TrimSpace(request.GetString("name", "")), "#@")),
+		text:       strings.ToLower(strings.TrimSpace(request.GetString("text", ""))),
+		memberOnly: request.GetBool("member_only", false),
+		archived:   request.GetString("archived", "exclude"),
+		shared:     request.GetString("shared", "any"),
+		team:       strings.ToUpper(strings.TrimSpace(request.GetString("team", ""))),
+		minMembers: request.GetInt("min_members", 0),
+		maxMembers: request.GetInt("max_members", 0),
+		cursor:     request.GetString("cursor", ""),
+		limit:      request.GetInt("limit", 0),
+	}
+	types := request.GetString("channel_types", provider.PubChanType)
+
+	ch.logger.Debug("Request parameters",
+		zap.String("sort", params.sort),
+		zap.String("channel_types", types),
+		zap.String("cursor", params.cursor),
+		zap.Int("limit", params.limit),
+	)
+
+	// MCP Inspector v0.14.0 has issues with Slice type
+	// introspection, so some type simplification makes sense here
+	for _, t := range strings.Split(types, ",") {
+		t = strings.TrimSpace(t)
+		if ch.validTypes[t] {
+			params.types = append(params.types, t)
+		} else if t != "" {
+			ch.logger.Warn("Invalid channel type ignored", zap.String("type", t))
+		}
+	}
+
+	if len(params.types) == 0 {
+		ch.logger.Debug("No valid channel types provided, using defaults")
+		params.types = append(params.types, provider.PubChanType)
+		params.types = append(params.types, provider.PrivateChanType)
+	}
+
+	ch.logger.Debug("Validated channel types", zap.Strings("types", params.types))
+
+	if params.limit == 0 {
+		params.limit = 100
+		ch.logger.Debug("Limit not provided, using default", zap.Int("limit", params.limit))
+	}
+	if params.limit > 999 {
+		ch.logger.Warn("Limit exceeds maximum, capping to 999", zap.Int("requested", params.limit))
+		params.limit = 999
+	}
+
+	if re := request.GetString("name_regex", ""); re != "" {
+		compiled, err := regexp.Compile("(?i)" + re)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name_regex: %w", err)
+		}
+		params.nameRegex = compiled
+	}
+	switch params.archived {
+	case "exclude", "include", "only":
+	default:
+		return nil, fmt.Errorf("invalid archived %q, allowed values are 'exclude', 'include' and 'only'", params.archived)
+	}
+	switch params.shared {
+	case "any", "shared", "external", "internal":
+	default:
+		return nil, fmt.Errorf("invalid shared %q, allowed values are 'any', 'shared', 'external' and 'internal'", params.shared)
+	}
+	if params.maxMembers > 0 && params.minMembers > params.maxMembers {
+		return nil, fmt.Errorf("min_members %d is greater than max_members %d", params.minMembers, params.maxMembers)
+	}
+
+	return params, nil
+}
+
 func filterChannelsByTypes(channels map[string]provider.Channel, types []string) []provider.Channel {
 	logger := zap.L()
 
@@ -255,19 +328,126 @@ func filterChannelsByTypes(channels map[string]provider.Channel, types []string)
 	return result
 }
 
-func paginateChannels(channels []provider.Channel, cursor string, limit int) ([]provider.Channel, string) {
+// filterChannels keeps the channels matching every filter in params. Names
+// are matched without their leading # or @.
+func filterChannels(channels []provider.Channel, params *channelsListParams) []provider.Channel {
+	var result []provider.Channel
+	for _, c := range channels {
+		name := strings.TrimLeft(c.Name, "#@")
+		if params.name != "" && !strings.Contains(strings.ToLower(name), params.name) {
+			continue
+		}
+		if params.nameRegex != nil && !params.nameRegex.MatchString(name) {
+			continue
+		}
+		if params.text != "" &&
+			!strings.Contains(strings.ToLower(c.Topic), params.text) &&
+			!strings.Contains(strings.ToLower(c.Purpose), params.text) {
+			continue
+		}
+		if params.memberOnly && !c.IsMember {
+			continue
+		}
+		if (params.archived == "exclude" && c.IsArchived) || (params.archived == "only" && !c.IsArchived) {
+			continue
+		}
+		switch {
+		case params.shared == "shared" && !c.IsShared,
+			params.shared == "external" && !c.IsExtShared,
+			params.shared == "internal" && c.IsShared:
+			continue
+		}
+		if params.team != "" && !slices.Contains(c.ConnectedTeamIDs, params.team) {
+			continue
+		}
+		if c.MemberCount < params.minMembers || (params.maxMembers > 0 && c.MemberCount > params.maxMembers) {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// channelSortKey returns the key channels are ordered by, ascending, for a
+// sort mode. Descending orders invert the number so they still sort as
+// strings, and unknown modes list channels by ID.
+func (ch *ChannelsHandler) channelSortKey(ctx context.Context, sortType string) func(provider.Channel) string {
+	desc := func(n int64) string {
+		return fmt.Sprintf("%020d", math.MaxInt64-n)
+	}
+
+	switch sortType {
+	case "popularity":
+		ch.logger.Debug("Sorting channels by popularity (member count)")
+		return func(c provider.Channel) string { return desc(int64(c.MemberCount)) }
+	case "name":
+		return func(c provider.Channel) string { return strings.ToLower(strings.TrimLeft(c.Name, "#@")) }
+	case "created":
+		return func(c provider.Channel) string { return desc(c.Created) }
+	case "activity":
+		latest := ch.latestActivity(ctx)
+		return func(c provider.Channel) string { return desc(latest[c.ID]) }
+	default:
+		ch.logger.Debug("No sorting applied", zap.String("sort_type", sortType))
+		return func(provider.Channel) string { return "" }
+	}
+}
+
+// latestActivity maps channel IDs to the time of their newest message in
+// microseconds, as client.counts reports it. It is empty when client.counts is
+// not available, e.g. for bot tokens, so every channel sorts as inactive.
+func (ch *ChannelsHandler) latestActivity(ctx context.Context) map[string]int64 {
+	latest := make(map[string]int64)
+	if ch.apiProvider.IsBotToken() {
+		return latest
+	}
+	counts, err := ch.apiProvider.Slack().ClientCounts(ctx)
+	if err != nil {
+		ch.logger.Warn("Slack ClientCounts failed, channels are not sorted by activity", zap.Error(err))
+		return latest
+	}
+	for _, snapshots := range [][]edge.ChannelSnapshot{counts.Channels, counts.MPIMs, counts.IMs} {
+		for _, s := range snapshots {
+			if t := time.Time(s.Latest); !t.IsZero() {
+				latest[s.ID] = t.UnixMicro()
+			}
+		}
+	}
+	return latest
+}
+
+// paginateChannels orders channels by key and ID and returns the page after
+// cursor. The cursor holds the key and ID of the last channel returned rather
+// than an offset, so pages neither skip nor repeat channels when the cache
+// changes between calls.
+func paginateChannels(channels []provider.Channel, key func(provider.Channel) string, cursor string, limit int) ([]provider.Channel, string) {
 	logger := zap.L()
 
+	keys := make(map[string]string, len(channels))
+	for _, c := range channels {
+		keys[c.ID] = key(c)
+	}
+	after := func(k, id string, c provider.Channel) bool {
+		if keys[c.ID] != k {
+			return keys[c.ID] > k
+		}
+		return c.ID > id
+	}
 	sort.Slice(channels, func(i, j int) bool {
-		return channels[i].ID < channels[j].ID
+		return after(keys[channels[i].ID], channels[i].ID, channels[j])
 	})
 
 	startIndex := 0
 	if cursor != "" {
 		if decoded, err := base64.StdEncoding.DecodeString(cursor); err == nil {
-			lastID := string(decoded)
+			lastKey, lastID, found := strings.Cut(string(decoded), cursorSeparator)
+			if !found {
+				// cursors of older versions only hold the ID
+				lastKey, lastID = "", lastKey
+			}
+			startIndex = len(channels)
 			for i, ch := range channels {
-				if ch.ID > lastID {
+				if after(lastKey, lastID, ch) {
 					startIndex = i
 					break
 				}
@@ -294,9 +474,10 @@ func paginateChannels(channels []provider.Channel, cursor string, limit int) ([]
 
 	var nextCursor string
 	if endIndex < len(channels) {
-		nextCursor = base64.StdEncoding.EncodeToString([]byte(channels[endIndex-1].ID))
+		last := channels[endIndex-1]
+		nextCursor = base64.StdEncoding.EncodeToString([]byte(keys[last.ID] + cursorSeparator + last.ID))
 		logger.Debug("Generated next cursor",
-			zap.String("last_id", channels[endIndex-1].ID),
+			zap.String("last_id", last.ID),
 			zap.String("next_cursor", nextCursor),
 		)
 	}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+var errChannelAdminDisabled = errors.New(
+	"channel admin tools are disabled by default. " +
+		"To enable them, set the SLACK_MCP_CHANNEL_ADMIN_TOOL environment variable to 'true'")
+
+// isChannelAdminEnabled checks if the channel management tools are enabled via
+// env var.
+func isChannelAdminEnabled() bool {
+	v := strings.ToLower(os.Getenv("SLACK_MCP_CHANNEL_ADMIN_TOOL"))
+	return v == "true" || v == "1" || v == "yes"
+}
+
+// ChannelsCreateHandler creates a public or private channel, optionally with
+// a topic and purpose.
+func (ch *ChannelsHandler) ChannelsCreateHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsCreateHandler called", zap.Any("params", request.Params))
+
+	if err := ch.checkChannelAdmin(); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimPrefix(strings.TrimSpace(request.GetString("name", "")), "#")
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	channel, err := ch.apiProvider.Slack().CreateConversationContext(ctx, slack.CreateConversationParams{
+		ChannelName: name,
+		IsPrivate:   request.GetBool("is_private", false),
+	})
+	if err != nil {
+		ch.logger.Error("Slack CreateConversationContext failed", zap.String("name", name), zap.Error(err))
+		return nil, err
+	}
+	ch.logger.Debug("Created channel", zap.String("channel", channel.ID))
+
+	if topic := request.GetString("topic", ""); topic != "" {
+		if channel, err = ch.apiProvider.Slack().SetTopicOfConversationContext(ctx, channel.ID, topic); err != nil {
+			ch.logger.Error("Slack SetTopicOfConversationContext failed", zap.Error(err))
+			return nil, fmt.Errorf("channel created but setting its topic failed: %w", err)
+		}
+	}
+	if purpose := request.GetString("purpose", ""); purpose != "" {
+		if channel, err = ch.apiProvider.Slack().SetPurposeOfConversationContext(ctx, channel.ID, purpose); err != nil {
+			ch.logger.Error("Slack SetPurposeOfConversationContext failed", zap.Error(err))
+			return nil, fmt.Errorf("channel created but setting its purpose failed: %w", err)
+		}
+	}
+
+	return ch.channelResult(channel)
+}
+
+// ChannelsArchiveHandler archives a channel and drops it from the cache, or
+// marks it archived there when archived channels are cached.
+func (ch *ChannelsHandler) ChannelsArchiveHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsArchiveHandler called", zap.Any("params", request.Params))
+
+	if err := ch.checkChannelAdmin(); err != nil {
+		return nil, err
+	}
+	channelID, err := ch.channelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ch.apiProvider.Slack().ArchiveConversationContext(ctx, channelID); err != nil {
+		ch.logger.Error("Slack ArchiveConversationContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, err
+	}
+	if !ch.apiProvider.IncludesArchivedChannels() {
+		ch.apiProvider.RemoveChannel(channelID)
+	} else if channel, err := ch.apiProvider.Slack().GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{
+		ChannelID:         channelID,
+		IncludeNumMembers: true,
+	}); err != nil {
+		ch.logger.Warn("Failed to refresh channel after archive", zap.String("channel", channelID), zap.Error(err))
+	} else {
+		ch.apiProvider.UpdateChannel(channel)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Archived channel %s", channelID)), nil
+}
+
+// ChannelsUnarchiveHandler restores an archived channel. Archived channels are
+// only cached with SLACK_MCP_CHANNELS_INCLUDE_ARCHIVED, so only then can they
+// be given by name.
+func (ch *ChannelsHandler) ChannelsUnarchiveHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsUnarchiveHandler called", zap.Any("params", request.Params))
+
+	if err := ch.checkChannelAdmin(); err != nil {
+		return nil, err
+	}
+	if name := strings.TrimSpace(request.GetString("channel_id", "")); strings.HasPrefix(name, "#") && !ch.apiProvider.IncludesArchivedChannels() {
+		return nil, fmt.Errorf("archived channel %q can't be found by name, pass its ID or set SLACK_MCP_CHANNELS_INCLUDE_ARCHIVED to 'true'", name)
+	}
+	channelID, err := ch.channelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ch.apiProvider.Slack().UnArchiveConversationContext(ctx, channelID); err != nil {
+		ch.logger.Error("Slack UnArchiveConversationContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, err
+	}
+	channel, err := ch.apiProvider.Slack().GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{
+		ChannelID:         channelID,
+		IncludeNumMembers: true,
+	})
+	if err != nil {
+		ch.logger.Error("Slack GetConversationInfoContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, fmt.Errorf("channel unarchived but reading it back failed: %w", err)
+	}
+
+	return ch.channelResult(channel)
+}
+
+// ChannelsRenameHandler renames a channel.
+func (ch *ChannelsHandler) ChannelsRenameHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsRenameHandler called", zap.Any("params", request.Params))
+
+	if err := ch.checkChannelAdmin(); err != nil {
+		return nil, err
+	}
+	channelID, err := ch.channelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimPrefix(strings.TrimSpace(request.GetString("name", "")), "#")
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	channel, err := ch.apiProvider.Slack().RenameConversationContext(ctx, channelID, name)
+	if err != nil {
+		ch.logger.Error("Slack RenameConversationContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, err
+	}
+
+	return ch.channelResult(channel)
+}
+
+// ChannelsSetTopicHandler sets the topic of a channel, an empty topic clears
+// it.
+func (ch *ChannelsHandler) ChannelsSetTopicHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsSetTopicHandler called", zap.Any("params", request.Params))
+
+	if err := ch.checkChannelAdmin(); err != nil {
+		return nil, err
+	}
+	channelID, err := ch.channelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := ch.apiProvider.Slack().SetTopicOfConversationContext(ctx, channelID, request.GetString("topic", ""))
+	if err != nil {
+		ch.logger.Error("Slack SetTopicOfConversationContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, err
+	}
+
+	return ch.channelResult(channel)
+}
+
+// ChannelsSetPurposeHandler sets the purpose of a channel, an empty purpose
+// clears it.
+func (ch *ChannelsHandler) ChannelsSetPurposeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsSetPurposeHandler called", zap.Any("params", request.Params))
+
+	if err := ch.checkChannelAdmin(); err != nil {
+		return nil, err
+	}
+	channelID, err := ch.channelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := ch.apiProvider.Slack().SetPurposeOfConversationContext(ctx, channelID, request.GetString("purpose", ""))
+	if err != nil {
+		ch.logger.Error("Slack SetPurposeOfConversationContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, err
+	}
+
+	return ch.channelResult(channel)
+}
+
+func (ch *ChannelsHandler) checkChannelAdmin() error {
+	if !isChannelAdminEnabled() {
+		return errChannelAdminDisabled
+	}
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// channelParam returns the ID of the channel_id parameter, names are resolved
+// through the channels cache.
+func (ch *ChannelsHandler) channelParam(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	channel := strings.TrimSpace(request.GetString("channel_id", ""))
+	if channel == "" {
+		return "", errors.New("channel_id is required")
+	}
+	channelID, err := resolveChannelID(ctx, ch.apiProvider, ch.logger, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return "", err
+	}
+	return channelID, nil
+}
+
+// channelResult stores a created or changed channel in the cache and returns
+// it as a CSV row like channels_list.
+func (ch *ChannelsHandler) channelResult(channel *slack.Channel) (*mcp.CallToolResult, error) {
+	ch.apiProvider.UpdateChannel(channel)
+	cached := ch.apiProvider.ProvideChannelsMaps().Channels[channel.ID]
+
+	channels := []Channel{{
+		ID:          cached.ID,
+		Name:        cached.Name,
+		Topic:       cached.Topic,
+		Purpose:     cached.Purpose,
+		MemberCount: cached.MemberCount,
+	}}
+	csvBytes, err := gocsv.MarshalBytes(&channels)
+	if err != nil {
+		ch.logger.Error("Failed to marshal channel to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
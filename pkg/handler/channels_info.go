@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// ChannelInfo describes one channel. Fields that could not be read, e.g.
+// pins without the pins:read scope, are left out.
+type ChannelInfo struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Topic         string            `json:"topic"`
+	Purpose       string            `json:"purpose"`
+	CreatorID     string            `json:"creatorID,omitempty"`
+	CreatorName   string            `json:"creatorName,omitempty"`
+	Created       string            `json:"created,omitempty"`
+	IsPrivate     bool              `json:"isPrivate"`
+	IsArchived    bool              `json:"isArchived"`
+	IsShared      bool              `json:"isShared"`
+	IsExtShared   bool              `json:"isExtShared"`
+	IsOrgShared   bool              `json:"isOrgShared"`
+	ExternalTeams []ChannelTeam     `json:"externalTeams,omitempty"`
+	PreviousNames []string          `json:"previousNames,omitempty"`
+	MemberCount   int               `json:"memberCount"`
+	LastActivity  string            `json:"lastActivity,omitempty"`
+	PinnedCount   *int              `json:"pinnedCount,omitempty"`
+	Bookmarks     []ChannelBookmark `json:"bookmarks,omitempty"`
+}
+
+// ChannelTeam is a workspace a channel is shared with through Slack Connect,
+// the name is empty when team.info doesn't know it.
+type ChannelTeam struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// ChannelBookmark is a link or file bookmarked in the channel header.
+type ChannelBookmark struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Link  string `json:"link"`
+	Type  string `json:"type"`
+}
+
+// ChannelsInfoHandler returns the metadata of a single channel. conversations.info
+// is required, the edge channel info, read state, pins, bookmarks and team
+// names are best effort.
+func (ch *ChannelsHandler) ChannelsInfoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsInfoHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	channelID, err := ch.channelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := ch.apiProvider.Slack().GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{
+		ChannelID:         channelID,
+		IncludeNumMembers: true,
+	})
+	if err != nil {
+		ch.logger.Error("Slack GetConversationInfoContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, err
+	}
+
+	info := ch.channelInfo(channel)
+	teams := append(append([]string(nil), channel.ConnectedTeamIDs...), channel.SharedTeamIDs...)
+	latest := ""
+	if channel.Latest != nil {
+		latest = channel.Latest.Timestamp
+	}
+
+	if !ch.apiProvider.IsBotToken() {
+		generic, err := ch.apiProvider.Slack().ConversationsGenericInfo(ctx, channelID)
+		if err != nil {
+			ch.logger.Warn("Slack ConversationsGenericInfo failed", zap.String("channel", channelID), zap.Error(err))
+		}
+		for _, g := range generic {
+			if g.ID != channelID {
+				continue
+			}
+			info.IsShared = info.IsShared || g.IsShared
+			info.IsExtShared = info.IsExtShared || g.IsExtShared
+			info.IsOrgShared = info.IsOrgShared || g.IsOrgShared
+			if len(info.PreviousNames) == 0 {
+				info.PreviousNames = g.PreviousNames
+			}
+			teams = append(append(teams, g.ConnectedTeamIDs...), g.SharedTeamIDs...)
+		}
+
+		if ts := ch.latestFromCounts(ctx, channelID); ts != "" {
+			latest = ts
+		}
+	}
+
+	info.ExternalTeams = ch.externalTeams(ctx, teams)
+	if latest != "" {
+		if info.LastActivity, err = text.TimestampToIsoRFC3339(latest); err != nil {
+			ch.logger.Warn("Failed to convert timestamp to RFC3339", zap.String("ts", latest), zap.Error(err))
+		}
+	}
+
+	pins, _, err := ch.apiProvider.Slack().ListPinsContext(ctx, channelID)
+	if err != nil {
+		ch.logger.Warn("Slack ListPinsContext failed", zap.String("channel", channelID), zap.Error(err))
+	} else {
+		count := len(pins)
+		info.PinnedCount = &count
+	}
+
+	bookmarks, err := ch.apiProvider.Slack().ListBookmarksContext(ctx, channelID)
+	if err != nil {
+		ch.logger.Warn("Slack ListBookmarksContext failed", zap.String("channel", channelID), zap.Error(err))
+	}
+	for _, b := range bookmarks {
+		info.Bookmarks = append(info.Bookmarks, ChannelBookmark{ID: b.ID, Title: b.Title, Link: b.Link, Type: b.Type})
+	}
+
+	jsonBytes, err := json.Marshal(info)
+	if err != nil {
+		ch.logger.Error("Failed to marshal channel info to JSON", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(info, string(jsonBytes)), nil
+}
+
+// channelInfo fills what conversations.info returns, the name is taken from
+// the cache so DMs read like in channels_list.
+func (ch *ChannelsHandler) channelInfo(channel *slack.Channel) ChannelInfo {
+	info := ChannelInfo{
+		ID:            channel.ID,
+		Name:          "#" + channel.Name,
+		Topic:         channel.Topic.Value,
+		Purpose:       channel.Purpose.Value,
+		CreatorID:     channel.Creator,
+		IsPrivate:     channel.IsPrivate,
+		IsArchived:    channel.IsArchived,
+		IsShared:      channel.IsShared,
+		IsExtShared:   channel.IsExtShared,
+		IsOrgShared:   channel.IsOrgShared,
+		PreviousNames: channel.PreviousNames,
+		MemberCount:   channel.NumMembers,
+	}
+	if cached, ok := ch.apiProvider.ProvideChannelsMaps().Channels[channel.ID]; ok {
+		info.Name = cached.Name
+		if info.MemberCount == 0 {
+			info.MemberCount = cached.MemberCount
+		}
+	}
+	if channel.Creator != "" {
+		info.CreatorName, _, _ = getUserInfo(channel.Creator, ch.apiProvider.ProvideUsersMap().Users)
+	}
+	if channel.Created > 0 {
+		info.Created = channel.Created.Time().UTC().Format(time.RFC3339)
+	}
+	return info
+}
+
+// latestFromCounts returns the ts of the newest message in a channel as the
+// web client sees it, empty when client.counts isn't available.
+func (ch *ChannelsHandler) latestFromCounts(ctx context.Context, channelID string) string {
+	counts, err := ch.apiProvider.Slack().ClientCounts(ctx)
+	if err != nil {
+		ch.logger.Warn("Slack ClientCounts failed", zap.Error(err))
+		return ""
+	}
+	for _, snapshots := range [][]edge.ChannelSnapshot{counts.Channels, counts.MPIMs, counts.IMs} {
+		for _, s := range snapshots {
+			if s.ID == channelID && !time.Time(s.Latest).IsZero() {
+				return s.Latest.SlackString()
+			}
+		}
+	}
+	return ""
+}
+
+// externalTeams resolves the names of the teams other than ours a channel is
+// connected to.
+func (ch *ChannelsHandler) externalTeams(ctx context.Context, ids []string) []ChannelTeam {
+	own := ""
+	if ar, err := ch.apiProvider.AuthResponse(); err == nil {
+		own = ar.TeamID
+	}
+
+	var teams []ChannelTeam
+	seen := map[string]bool{own: true}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		team := ChannelTeam{ID: id}
+		if ti, err := ch.apiProvider.Slack().GetOtherTeamInfoContext(ctx, id); err != nil {
+			ch.logger.Warn("Slack GetOtherTeamInfoContext failed", zap.String("team", id), zap.Error(err))
+		} else {
+			team.Name = ti.Name
+		}
+		teams = append(teams, team)
+	}
+	return teams
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMembersLimit = 100
+	maxMembersLimit     = 1000
+)
+
+// Member is a user in a channel, names come from the users cache and are
+// empty for users it doesn't know, e.g. from other organizations.
+type Member struct {
+	UserID   string `json:"userID"`
+	UserName string `json:"userName"`
+	RealName string `json:"realName"`
+	Cursor   string `json:"cursor"`
+}
+
+// ChannelsMembersHandler lists the members of a channel, a page at a time.
+func (ch *ChannelsHandler) ChannelsMembersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsMembersHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	channelID, err := ch.channelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	limit := request.GetInt("limit", defaultMembersLimit)
+	if limit <= 0 {
+		limit = defaultMembersLimit
+	}
+	if limit > maxMembersLimit {
+		limit = maxMembersLimit
+	}
+
+	ids, next, err := ch.apiProvider.Slack().GetUsersInConversationContext(ctx, &slack.GetUsersInConversationParameters{
+		ChannelID: channelID,
+		Cursor:    request.GetString("cursor", ""),
+		Limit:     limit,
+	})
+	if err != nil {
+		ch.logger.Error("Slack GetUsersInConversationContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, err
+	}
+	ch.logger.Debug("Fetched channel members", zap.Int("count", len(ids)), zap.Bool("has_more", next != ""))
+
+	users := ch.apiProvider.ProvideUsersMap().Users
+	members := make([]Member, 0, len(ids))
+	for _, id := range ids {
+		userName, realName, _ := getUserInfo(id, users)
+		members = append(members, Member{UserID: id, UserName: userName, RealName: realName})
+	}
+	if len(members) > 0 {
+		members[len(members)-1].Cursor = next
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&members)
+	if err != nil {
+		ch.logger.Error("Failed to marshal members to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// ChannelsJoinHandler joins the authenticated user to a public channel.
+func (ch *ChannelsHandler) ChannelsJoinHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsJoinHandler called", zap.Any("params", request.Params))
+
+	if err := ch.checkChannelAdmin(); err != nil {
+		return nil, err
+	}
+	channelID, err := ch.channelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, warning, _, err := ch.apiProvider.Slack().JoinConversationContext(ctx, channelID)
+	if err != nil {
+		ch.logger.Error("Slack JoinConversationContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, err
+	}
+	if warning != "" {
+		ch.logger.Debug("Joined channel with a warning", zap.String("channel", channelID), zap.String("warning", warning))
+	}
+
+	return ch.channelResult(channel)
+}
+
+// ChannelsLeaveHandler removes the authenticated user from a channel. Private
+// channels are dropped from the cache as they can't be read anymore, public
+// ones are re-read so the cache knows the user left.
+func (ch *ChannelsHandler) ChannelsLeaveHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsLeaveHandler called", zap.Any("params", request.Params))
+
+	if err := ch.checkChannelAdmin(); err != nil {
+		return nil, err
+	}
+	channelID, err := ch.channelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	notInChannel, err := ch.apiProvider.Slack().LeaveConversationContext(ctx, channelID)
+	if err != nil {
+		ch.logger.Error("Slack LeaveConversationContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, err
+	}
+	if notInChannel {
+		return mcp.NewToolResultText(fmt.Sprintf("Not a member of channel %s, nothing to leave", channelID)), nil
+	}
+	if c, ok := ch.apiProvider.ProvideChannelsMaps().Channels[channelID]; ok && (c.IsPrivate || c.IsMpIM) {
+		ch.apiProvider.RemoveChannel(channelID)
+	} else if ok {
+		// public channels stay listed, refresh their membership
+		channel, err := ch.apiProvider.Slack().GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{
+			ChannelID:         channelID,
+			IncludeNumMembers: true,
+		})
+		if err != nil {
+			ch.logger.Warn("Failed to refresh channel after leave", zap.String("channel", channelID), zap.Error(err))
+		} else {
+			ch.apiProvider.UpdateChannel(channel)
+		}
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Left channel %s", channelID)), nil
+}
+
+// ChannelsInviteHandler invites users to a channel.
+func (ch *ChannelsHandler) ChannelsInviteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsInviteHandler called", zap.Any("params", request.Params))
+
+	if err := ch.checkChannelAdmin(); err != nil {
+		return nil, err
+	}
+	channelID, err := ch.channelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	var users []string
+	for _, raw := range strings.Split(request.GetString("users", ""), ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		id, err := ch.resolveUserID(raw)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, id)
+	}
+	if len(users) == 0 {
+		return nil, errors.New("users is required")
+	}
+
+	channel, err := ch.apiProvider.Slack().InviteUsersToConversationContext(ctx, channelID, users...)
+	if err != nil {
+		ch.logger.Error("Slack InviteUsersToConversationContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, err
+	}
+
+	return ch.channelResult(channel)
+}
+
+// ChannelsKickHandler removes a user from a channel.
+func (ch *ChannelsHandler) ChannelsKickHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ChannelsKickHandler called", zap.Any("params", request.Params))
+
+	if err := ch.checkChannelAdmin(); err != nil {
+		return nil, err
+	}
+	channelID, err := ch.channelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	raw := request.GetString("user", "")
+	if strings.TrimSpace(raw) == "" {
+		return nil, errors.New("user is required")
+	}
+	user, err := ch.resolveUserID(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ch.apiProvider.Slack().KickUserFromConversationContext(ctx, channelID, user); err != nil {
+		ch.logger.Error("Slack KickUserFromConversationContext failed", zap.String("channel", channelID), zap.Error(err))
+		return nil, err
+	}
+
+	// kick returns no channel, read the new member count back
+	channel, err := ch.apiProvider.Slack().GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{
+		ChannelID:         channelID,
+		IncludeNumMembers: true,
+	})
+	if err != nil {
+		ch.logger.Warn("Failed to refresh channel after kick", zap.String("channel", channelID), zap.Error(err))
+	} else {
+		ch.apiProvider.UpdateChannel(channel)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Removed user %s from channel %s", user, channelID)), nil
+}
+
+func (ch *ChannelsHandler) resolveUserID(raw string) (string, error) {
+	return resolveUserID(ch.apiProvider, raw)
+}
+
+// resolveUserID accepts a user ID, which is passed through as users of other
+// organizations are not cached, or a @username.
+func resolveUserID(ap *provider.ApiProvider, raw string) (string, error) {
+	raw = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(raw), "<@"), ">")
+	if isSlackUserIDPrefix(raw) && strings.ToUpper(raw) == raw {
+		return raw, nil
+	}
+	name := strings.TrimPrefix(raw, "@")
+	if id, ok := ap.ProvideUsersMap().UsersInv[name]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("user %q not found", raw)
+}
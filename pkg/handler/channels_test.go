@@ -11,11 +11,13 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/korotovsky/slack-mcp-server/pkg/test/util"
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 type testEnv struct {
@@ -161,3 +163,84 @@ func TestIntegrationPrivateChannelsList(t *testing.T) {
 
 	runChannelTest(t, env, "private_channel", expectedChannels)
 }
+
+func TestUnitPaginateChannelsStableCursor(t *testing.T) {
+	channels := []provider.Channel{
+		{ID: "C1", Name: "#alpha", MemberCount: 5},
+		{ID: "C2", Name: "#bravo", MemberCount: 9},
+		{ID: "C3", Name: "#charlie", MemberCount: 5},
+		{ID: "C4", Name: "#delta", MemberCount: 1},
+	}
+	byPopularity := (&ChannelsHandler{logger: zap.NewNop()}).channelSortKey(context.Background(), "popularity")
+
+	page, cursor := paginateChannels(append([]provider.Channel(nil), channels...), byPopularity, "", 2)
+	require.Len(t, page, 2)
+	assert.Equal(t, "C2", page[0].ID)
+	assert.Equal(t, "C1", page[1].ID)
+	require.NotEmpty(t, cursor)
+
+	// channels added before and removed after the cursor don't shift the next page
+	changed := append([]provider.Channel{{ID: "C0", Name: "#zulu", MemberCount: 50}}, channels[2:]...)
+	page, cursor = paginateChannels(changed, byPopularity, cursor, 2)
+	require.Len(t, page, 2)
+	assert.Equal(t, "C3", page[0].ID)
+	assert.Equal(t, "C4", page[1].ID)
+	assert.Empty(t, cursor)
+
+	byName := (&ChannelsHandler{logger: zap.NewNop()}).channelSortKey(context.Background(), "name")
+	page, _ = paginateChannels(append([]provider.Channel(nil), channels...), byName, "", 10)
+	assert.Equal(t, []string{"C1", "C2", "C3", "C4"}, []string{page[0].ID, page[1].ID, page[2].ID, page[3].ID})
+}
+
+func TestUnitFilterChannels(t *testing.T) {
+	channels := []provider.Channel{
+		{ID: "C1", Name: "#inc-1234", Topic: "Checkout down", MemberCount: 4, IsMember: true},
+		{ID: "C2", Name: "#eng-backend", Purpose: "Backend team", MemberCount: 30, IsShared: true, IsExtShared: true, ConnectedTeamIDs: []string{"T001", "T002"}},
+		{ID: "C3", Name: "#old-project", MemberCount: 2, IsArchived: true},
+	}
+	ids := func(params *channelsListParams) []string {
+		var res []string
+		for _, c := range filterChannels(channels, params) {
+			res = append(res, c.ID)
+		}
+		return res
+	}
+	base := func() *channelsListParams {
+		return &channelsListParams{archived: "exclude", shared: "any"}
+	}
+
+	p := base()
+	assert.Equal(t, []string{"C1", "C2"}, ids(p))
+	p.archived = "only"
+	assert.Equal(t, []string{"C3"}, ids(p))
+
+	p = base()
+	p.name = "eng"
+	assert.Equal(t, []string{"C2"}, ids(p))
+
+	p = base()
+	p.nameRegex = regexp.MustCompile(`(?i)^INC-\d+$`)
+	assert.Equal(t, []string{"C1"}, ids(p))
+
+	p = base()
+	p.text = "checkout"
+	assert.Equal(t, []string{"C1"}, ids(p))
+
+	p = base()
+	p.memberOnly = true
+	assert.Equal(t, []string{"C1"}, ids(p))
+
+	p = base()
+	p.shared = "external"
+	assert.Equal(t, []string{"C2"}, ids(p))
+	p.shared = "internal"
+	assert.Equal(t, []string{"C1"}, ids(p))
+
+	p = base()
+	p.team = "T002"
+	assert.Equal(t, []string{"C2"}, ids(p))
+
+	p = base()
+	p.minMembers, p.maxMembers = 5, 100
+	assert.Equal(t, []string{"C2"}, ids(p))
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"net/url"
 	"os"
 	"regexp"
 	"strconv"
@@ -26,7 +25,15 @@ import (
 const (
 	defaultConversationsNumericLimit    = 50
 	defaultConversationsExpressionLimit = "1d"
-	maxFileSizeBytes                    = 5 * 1024 * 1024 // 5MB limit
+	defaultExpandMaxThreads             = 10
+	maxExpandMaxThreads                 = 50
+	// maxExpandedReplies is how many replies of each thread expand_threads
+	// returns, longer threads are marked as truncated.
+	maxExpandedReplies = 100
+	// expandThreadsConcurrency bounds the reply fetches in flight, the rate
+	// limiter still paces them to the method's tier.
+	expandThreadsConcurrency = 4
+	maxFileSizeBytes         = 5 * 1024 * 1024 // 5MB limit
 )
 
 var validFilterKeys = map[string]struct{}{
@@ -54,7 +61,12 @@ type Message struct {
 	FileCount     int    `json:"fileCount,omitempty"`
 	AttachmentIDs string `json:"attachmentIDs,omitempty"`
 	HasMedia      bool   `json:"hasMedia,omitempty"`
-	Cursor        string `json:"cursor"`
+	ReplyCount    int    `json:"replyCount,omitempty"`
+	// Truncated is set on a thread parent when expand_threads didn't return
+	// all of its replies.
+	Truncated bool   `json:"truncated,omitempty"`
+	Permalink string `json:"permalink"`
+	Cursor    string `json:"cursor"`
 }
 
 type User struct {
@@ -82,6 +94,11 @@ type conversationParams struct {
 	activity bool
 }
 
+type expandParams struct {
+	enabled    bool
+	maxThreads int
+}
+
 type searchParams struct {
 	query string
 	limit int
@@ -263,7 +280,7 @@ func (ch *ConversationsHandler) ConversationsAddMessageHandler(ctx context.Conte
 	}
 	ch.logger.Debug("Fetched conversation history", zap.Int("message_count", len(history.Messages)))
 
-	messages := ch.convertMessagesFromHistory(history.Messages, historyParams.ChannelID, false)
+	messages := ch.convertMessagesFromHistory(ctx, history.Messages, historyParams.ChannelID, false)
 	return marshalMessagesToCSV(messages)
 }
 
@@ -521,7 +538,15 @@ func (ch *ConversationsHandler) ConversationsHistoryHandler(ctx context.Context,
 
 	ch.logger.Debug("Fetched conversation history", zap.Int("message_count", len(history.Messages)))
 
-	messages := ch.convertMessagesFromHistory(history.Messages, params.channel, params.activity)
+	var messages []Message
+	if expand := ch.parseParamsToolExpand(request); expand.enabled {
+		messages, err = ch.expandThreads(ctx, params.channel, history.Messages, expand.maxThreads, params.activity)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		messages = ch.convertMessagesFromHistory(ctx, history.Messages, params.channel, params.activity)
+	}
 
 	if len(messages) > 0 && history.HasMore {
 		messages[len(messages)-1].Cursor = history.ResponseMetaData.NextCursor
@@ -540,6 +565,16 @@ func (ch *ConversationsHandler) ConversationsRepliesHandler(ctx context.Context,
 	}
 	threadTs := request.GetString("thread_ts", "")
 	if threadTs == "" {
+		// a permalink in channel_id points at the thread too
+		threadTs = request.GetString("channel_id", "")
+	}
+	if ref, ok := parsePermalink(threadTs); ok {
+		threadTs = ref.threadTs
+		if threadTs == "" {
+			threadTs = ref.ts
+		}
+	}
+	if threadTs == "" || !strings.Contains(threadTs, ".") {
 		ch.logger.Error("thread_ts not provided for replies", zap.String("thread_ts", threadTs))
 		return nil, errors.New("thread_ts must be a string")
 	}
@@ -560,7 +595,7 @@ func (ch *ConversationsHandler) ConversationsRepliesHandler(ctx context.Context,
 	}
 	ch.logger.Debug("Fetched conversation replies", zap.Int("count", len(replies)))
 
-	messages := ch.convertMessagesFromHistory(replies, params.channel, params.activity)
+	messages := ch.convertMessagesFromHistory(ctx, replies, params.channel, params.activity)
 	if len(messages) > 0 && hasMore {
 		messages[len(messages)-1].Cursor = nextCursor
 	}
@@ -591,7 +626,7 @@ func (ch *ConversationsHandler) ConversationsSearchHandler(ctx context.Context,
 	}
 	ch.logger.Debug("Search completed", zap.Int("matches", len(messagesRes.Matches)))
 
-	messages := ch.convertMessagesFromSearch(messagesRes.Matches)
+	messages := ch.convertMessagesFromSearch(ctx, messagesRes.Matches)
 	if len(messages) > 0 && messagesRes.Pagination.Page < messagesRes.Pagination.PageCount {
 		nextCursor := fmt.Sprintf("page:%d", messagesRes.Pagination.Page+1)
 		messages[len(messages)-1].Cursor = base64.StdEncoding.EncodeToString([]byte(nextCursor))
@@ -617,7 +652,7 @@ func isChannelAllowedForConfig(channel, config string) bool {
 			}
 		}
 	}
-	return !isNegated
+	return isNegated
 }
 
 func isChannelAllowed(channel string) bool {
@@ -625,26 +660,32 @@ func isChannelAllowed(channel string) bool {
 }
 
 func (ch *ConversationsHandler) resolveChannelID(ctx context.Context, channel string) (string, error) {
+	return resolveChannelID(ctx, ch.apiProvider, ch.logger, channel)
+}
+
+// resolveChannelID maps a #channel or @user name to its ID, refreshing the
+// channels cache once when the name is unknown. IDs are returned as is.
+func resolveChannelID(ctx context.Context, ap *provider.ApiProvider, logger *zap.Logger, channel string) (string, error) {
 	if !strings.HasPrefix(channel, "#") && !strings.HasPrefix(channel, "@") {
 		return channel, nil
 	}
 
 	// First attempt: try to resolve from current cache
-	channelsMaps := ch.apiProvider.ProvideChannelsMaps()
+	channelsMaps := ap.ProvideChannelsMaps()
 	chn, ok := channelsMaps.ChannelsInv[channel]
 	if ok {
 		return channelsMaps.Channels[chn].ID, nil
 	}
 
 	// Channel not found - try refreshing cache and retry once
-	ch.logger.Debug("Channel not found in cache, attempting refresh",
+	logger.Debug("Channel not found in cache, attempting refresh",
 		zap.String("channel", channel))
 
-	refreshErr := ch.apiProvider.ForceRefreshChannels(ctx)
+	refreshErr := ap.ForceRefreshChannels(ctx)
 	wasRateLimited := errors.Is(refreshErr, provider.ErrRefreshRateLimited)
 
 	if refreshErr != nil && !wasRateLimited {
-		ch.logger.Error("Failed to refresh channels cache",
+		logger.Error("Failed to refresh channels cache",
 			zap.String("channel", channel),
 			zap.Error(refreshErr))
 		return "", fmt.Errorf("channel %q not found and cache refresh failed: %w", channel, refreshErr)
@@ -652,29 +693,30 @@ func (ch *ConversationsHandler) resolveChannelID(ctx context.Context, channel st
 
 	// If rate-limited, cache wasn't refreshed - no point in a second lookup
 	if wasRateLimited {
-		ch.logger.Warn("Channel not found; cache refresh was rate-limited",
+		logger.Warn("Channel not found; cache refresh was rate-limited",
 			zap.String("channel", channel))
 		return "", fmt.Errorf("channel %q not found (cache refresh was rate-limited, try again later)", channel)
 	}
 
 	// Second attempt after successful refresh
-	channelsMaps = ch.apiProvider.ProvideChannelsMaps()
+	channelsMaps = ap.ProvideChannelsMaps()
 	chn, ok = channelsMaps.ChannelsInv[channel]
 	if !ok {
-		ch.logger.Error("Channel not found even after cache refresh",
+		logger.Error("Channel not found even after cache refresh",
 			zap.String("channel", channel))
 		return "", fmt.Errorf("channel %q not found", channel)
 	}
 
-	ch.logger.Debug("Channel found after cache refresh",
+	logger.Debug("Channel found after cache refresh",
 		zap.String("channel", channel),
 		zap.String("channel_id", channelsMaps.Channels[chn].ID))
 
 	return channelsMaps.Channels[chn].ID, nil
 }
 
-func (ch *ConversationsHandler) convertMessagesFromHistory(slackMessages []slack.Message, channel string, includeActivity bool) []Message {
+func (ch *ConversationsHandler) convertMessagesFromHistory(ctx context.Context, slackMessages []slack.Message, channel string, includeActivity bool) []Message {
 	usersMap := ch.apiProvider.ProvideUsersMap()
+	userGroups := ch.apiProvider.ProvideUserGroups(ctx).UserGroups
 	var messages []Message
 	warn := false
 
@@ -726,7 +768,7 @@ func (ch *ConversationsHandler) convertMessagesFromHistory(slackMessages []slack
 			UserID:        msg.User,
 			UserName:      userName,
 			RealName:      realName,
-			Text:          text.ProcessText(msgText),
+			Text:          text.ProcessText(resolveSubteamMentions(msgText, userGroups)),
 			Channel:       channel,
 			ThreadTs:      msg.ThreadTimestamp,
 			Time:          timestamp,
@@ -735,6 +777,8 @@ func (ch *ConversationsHandler) convertMessagesFromHistory(slackMessages []slack
 			FileCount:     fileCount,
 			AttachmentIDs: attachmentIDsStr,
 			HasMedia:      hasMedia,
+			ReplyCount:    msg.ReplyCount,
+			Permalink:     ch.apiProvider.Permalink(channel, msg.Timestamp, msg.ThreadTimestamp),
 		})
 	}
 
@@ -749,8 +793,9 @@ func (ch *ConversationsHandler) convertMessagesFromHistory(slackMessages []slack
 	return messages
 }
 
-func (ch *ConversationsHandler) convertMessagesFromSearch(slackMessages []slack.SearchMessage) []Message {
+func (ch *ConversationsHandler) convertMessagesFromSearch(ctx context.Context, slackMessages []slack.SearchMessage) []Message {
 	usersMap := ch.apiProvider.ProvideUsersMap()
+	userGroups := ch.apiProvider.ProvideUserGroups(ctx).UserGroups
 	var messages []Message
 	warn := false
 
@@ -763,7 +808,10 @@ func (ch *ConversationsHandler) convertMessagesFromSearch(slackMessages []slack.
 			warn = true
 		}
 
-		threadTs, _ := extractThreadTS(msg.Permalink)
+		var threadTs string
+		if ref, ok := parsePermalink(msg.Permalink); ok {
+			threadTs = ref.threadTs
+		}
 
 		timestamp, err := text.TimestampToIsoRFC3339(msg.Timestamp)
 		if err != nil {
@@ -780,12 +828,13 @@ func (ch *ConversationsHandler) convertMessagesFromSearch(slackMessages []slack.
 			UserID:    msg.User,
 			UserName:  userName,
 			RealName:  realName,
-			Text:      text.ProcessText(msgText),
+			Text:      text.ProcessText(resolveSubteamMentions(msgText, userGroups)),
 			Channel:   fmt.Sprintf("#%s", msg.Channel.Name),
 			ThreadTs:  threadTs,
 			Time:      timestamp,
 			Reactions: "",
 			HasMedia:  hasMedia,
+			Permalink: msg.Permalink,
 		})
 	}
 
@@ -806,6 +855,9 @@ func (ch *ConversationsHandler) parseParamsToolConversations(ctx context.Context
 		ch.logger.Error("channel_id missing in conversations params")
 		return nil, errors.New("channel_id must be a string")
 	}
+	if ref, ok := parsePermalink(channel); ok {
+		channel = ref.channel
+	}
 
 	limit := request.GetString("limit", "")
 	cursor := request.GetString("cursor", "")
@@ -865,6 +917,20 @@ func (ch *ConversationsHandler) parseParamsToolConversations(ctx context.Context
 	}, nil
 }
 
+func (ch *ConversationsHandler) parseParamsToolExpand(request mcp.CallToolRequest) expandParams {
+	maxThreads := request.GetInt("max_threads", defaultExpandMaxThreads)
+	if maxThreads <= 0 {
+		maxThreads = defaultExpandMaxThreads
+	}
+	if maxThreads > maxExpandMaxThreads {
+		maxThreads = maxExpandMaxThreads
+	}
+	return expandParams{
+		enabled:    request.GetBool("expand_threads", false),
+		maxThreads: maxThreads,
+	}
+}
+
 func (ch *ConversationsHandler) parseParamsToolAddMessage(ctx context.Context, request mcp.CallToolRequest) (*addMessageParams, error) {
 	toolConfig := os.Getenv("SLACK_MCP_ADD_MESSAGE_TOOL")
 	if toolConfig == "" {
@@ -1208,15 +1274,14 @@ func limitByExpression(limit, defaultLimit string) (slackLimit int, oldest, late
 	return 100, oldest, latest, nil
 }
 
-func extractThreadTS(rawurl string) (string, error) {
-	u, err := url.Parse(rawurl)
-	if err != nil {
-		return "", err
-	}
-	return u.Query().Get("thread_ts"), nil
+func parseFlexibleDate(dateStr string) (time.Time, string, error) {
+	return parseFlexibleDateAt(dateStr, time.Now().UTC())
 }
 
-func parseFlexibleDate(dateStr string) (time.Time, string, error) {
+// parseFlexibleDateAt is parseFlexibleDate with relative dates such as
+// "tomorrow" taken from the calendar day of now in its location. The result
+// is always midnight UTC of the parsed day.
+func parseFlexibleDateAt(dateStr string, now time.Time) (time.Time, string, error) {
 	dateStr = strings.TrimSpace(dateStr)
 	standardFormats := []string{
 		"2006-01-02",      // YYYY-MM-DD
@@ -1308,7 +1373,6 @@ func parseFlexibleDate(dateStr string) (time.Time, string, error) {
 	}
 
 	lower := strings.ToLower(dateStr)
-	now := time.Now().UTC()
 	switch lower {
 	case "today":
 		t := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
@@ -611,3 +611,49 @@ func TestUnitIsSlackUserIDPrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestUnitIsChannelAllowedForConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   bool
+	}{
+		{"enabled for all", "true", true},
+		{"listed", "C001,C002", true},
+		{"not listed", "C002,C003", false},
+		{"negated", "!C001", false},
+		{"negated other", "!C002", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := isChannelAllowedForConfig("C001", tt.config)
+			if got != tt.want {
+				t.Errorf("isChannelAllowedForConfig(%q, %q) = %v, want %v", "C001", tt.config, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitParsePermalink(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want messageRef
+		ok   bool
+	}{
+		{"message", "https://example.slack.com/archives/C0123ABCD/p1700000060000100", messageRef{channel: "C0123ABCD", ts: "1700000060.000100"}, true},
+		{"reply", "https://example.slack.com/archives/C0123ABCD/p1700000120000200?thread_ts=1700000060.000100&cid=C0123ABCD", messageRef{channel: "C0123ABCD", ts: "1700000120.000200", threadTs: "1700000060.000100"}, true},
+		{"parent linked as thread", "https://example.slack.com/archives/C0123ABCD/p1700000060000100?thread_ts=1700000060.000100", messageRef{channel: "C0123ABCD", ts: "1700000060.000100"}, true},
+		{"channel link", "https://example.slack.com/archives/C0123ABCD", messageRef{}, false},
+		{"channel name", "#general", messageRef{}, false},
+		{"channel and ts", "C0123ABCD:1700000060.000100", messageRef{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parsePermalink(tt.raw)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parsePermalink(%q) = %+v, %v, want %+v, %v", tt.raw, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/export"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+// exportDir returns the directory conversations_export writes to, the tool is
+// disabled while it is not configured.
+func exportDir() string {
+	return os.Getenv("SLACK_MCP_EXPORT_DIR")
+}
+
+// ConversationsExportHandler writes the full history of a channel, threads
+// included, to a file in SLACK_MCP_EXPORT_DIR.
+func (ch *ConversationsHandler) ConversationsExportHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsExportHandler called", zap.Any("params", request.Params))
+
+	dir := exportDir()
+	if dir == "" {
+		return nil, errors.New(
+			"conversations_export tool is disabled by default. " +
+				"To enable it, set the SLACK_MCP_EXPORT_DIR environment variable to the directory exports should be written to")
+	}
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	opts, err := ch.parseParamsToolExport(ctx, request)
+	if err != nil {
+		ch.logger.Error("Failed to parse export params", zap.Error(err))
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	if opts.Format == export.FormatSlack {
+		// every channel goes into the same export so they can be opened together
+		opts.Output = filepath.Join(dir, "slack-export")
+	} else {
+		name := strings.TrimLeft(opts.Channel.Name, "#@")
+		if name == "" {
+			name = opts.Channel.ID
+		}
+		opts.Output = filepath.Join(dir, fmt.Sprintf("%s-%s%s", name, time.Now().UTC().Format("20060102-150405"), opts.Format.Ext()))
+	}
+
+	res, err := export.Run(ctx, ch.apiProvider.Slack(), opts)
+	if err != nil {
+		ch.logger.Error("Export failed", zap.String("channel", opts.Channel.ID), zap.Error(err))
+		return nil, err
+	}
+
+	ch.logger.Info("Exported conversation",
+		zap.String("channel", opts.Channel.ID),
+		zap.String("output", res.Output),
+		zap.Int("messages", res.Messages),
+	)
+	return mcp.NewToolResultText(fmt.Sprintf(
+		"Exported %d messages (%d threads with %d replies, %d files) from %s to %s",
+		res.Messages, res.Threads, res.Replies, res.Files, opts.Channel.ID, res.Output,
+	)), nil
+}
+
+func (ch *ConversationsHandler) parseParamsToolExport(ctx context.Context, request mcp.CallToolRequest) (export.Options, error) {
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return export.Options{}, errors.New("channel_id must be a string")
+	}
+	channelID, err := ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		return export.Options{}, err
+	}
+
+	format, err := export.ParseFormat(request.GetString("format", ""))
+	if err != nil {
+		return export.Options{}, err
+	}
+
+	opts := export.Options{
+		Channel: provider.Channel{ID: channelID},
+		Users:   ch.apiProvider.ProvideUsersMap().Users,
+		Format:  format,
+		Files:   request.GetBool("include_files", false),
+	}
+	if c, ok := ch.apiProvider.ProvideChannelsMaps().Channels[channelID]; ok {
+		opts.Channel = c
+	}
+
+	if from := request.GetString("date_from", ""); from != "" {
+		t, _, err := parseFlexibleDate(from)
+		if err != nil {
+			return export.Options{}, fmt.Errorf("invalid 'date_from': %v", err)
+		}
+		opts.Oldest = t
+	}
+	if to := request.GetString("date_to", ""); to != "" {
+		t, _, err := parseFlexibleDate(to)
+		if err != nil {
+			return export.Options{}, fmt.Errorf("invalid 'date_to': %v", err)
+		}
+		// the whole last day is included
+		opts.Latest = t.AddDate(0, 0, 1)
+	}
+	if !opts.Oldest.IsZero() && !opts.Latest.IsZero() && !opts.Oldest.Before(opts.Latest) {
+		return export.Options{}, errors.New("'date_from' must not be after 'date_to'")
+	}
+	return opts, nil
+}
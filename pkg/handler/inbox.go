@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInboxLimit       = 50
+	defaultInboxMaxMessages = 10
+)
+
+var errMarkReadDisabled = errors.New(
+	"conversations_mark_read tool is disabled by default. " +
+		"To enable it, set the SLACK_MCP_MARK_READ_TOOL environment variable to 'true'")
+
+// Unread is a conversation with unread messages or mentions.
+type Unread struct {
+	ChannelID    string `json:"channelID"`
+	ChannelName  string `json:"channelName"`
+	Type         string `json:"type"`
+	MentionCount int    `json:"mentionCount"`
+	LastRead     string `json:"lastRead"`
+	Latest       string `json:"latest"`
+}
+
+type inboxParams struct {
+	limit           int
+	includeMessages bool
+	maxMessages     int
+}
+
+type markReadParams struct {
+	channel string
+	ts      string
+}
+
+// InboxUnreadsHandler lists the conversations that need attention: DMs and
+// group DMs first, then channels with mentions, then other unread channels.
+func (ch *ConversationsHandler) InboxUnreadsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("InboxUnreadsHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	params := ch.parseParamsToolInbox(request)
+
+	counts, err := ch.apiProvider.Slack().ClientCounts(ctx)
+	if err != nil {
+		ch.logger.Error("Slack ClientCounts failed", zap.Error(err))
+		return nil, err
+	}
+
+	unreads := ch.collectUnreads(counts)
+	if len(unreads) > params.limit {
+		unreads = unreads[:params.limit]
+	}
+	ch.logger.Debug("Collected unread conversations", zap.Int("count", len(unreads)))
+
+	csvBytes, err := gocsv.MarshalBytes(&unreads)
+	if err != nil {
+		ch.logger.Error("Failed to marshal unreads to CSV", zap.Error(err))
+		return nil, err
+	}
+	if !params.includeMessages {
+		return mcp.NewToolResultText(string(csvBytes)), nil
+	}
+
+	var messages []Message
+	for _, u := range unreads {
+		history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+			ChannelID: u.ChannelID,
+			Oldest:    u.LastRead,
+			Limit:     params.maxMessages,
+		})
+		if err != nil {
+			ch.logger.Error("GetConversationHistoryContext failed", zap.String("channel", u.ChannelID), zap.Error(err))
+			return nil, err
+		}
+		messages = append(messages, ch.convertMessagesFromHistory(ctx, history.Messages, u.ChannelID, false)...)
+	}
+	messagesCSV, err := gocsv.MarshalBytes(&messages)
+	if err != nil {
+		ch.logger.Error("Failed to marshal messages to CSV", zap.Error(err))
+		return nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(string(csvBytes)),
+			mcp.NewTextContent(string(messagesCSV)),
+		},
+	}, nil
+}
+
+// isMarkReadEnabled checks if conversations_mark_read is enabled via env var.
+func isMarkReadEnabled() bool {
+	v := strings.ToLower(os.Getenv("SLACK_MCP_MARK_READ_TOOL"))
+	return v == "true" || v == "1" || v == "yes"
+}
+
+// ConversationsMarkReadHandler moves the read cursor of a conversation to a
+// message, or to the newest one.
+func (ch *ConversationsHandler) ConversationsMarkReadHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsMarkReadHandler called", zap.Any("params", request.Params))
+
+	if !isMarkReadEnabled() {
+		return nil, errMarkReadDisabled
+	}
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	params, err := ch.parseParamsToolMarkRead(ctx, request)
+	if err != nil {
+		ch.logger.Error("Failed to parse mark-read params", zap.Error(err))
+		return nil, err
+	}
+
+	if params.ts == "" {
+		history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+			ChannelID: params.channel,
+			Limit:     1,
+		})
+		if err != nil {
+			ch.logger.Error("GetConversationHistoryContext failed", zap.Error(err))
+			return nil, err
+		}
+		if len(history.Messages) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("Channel %s has no messages, nothing to mark as read", params.channel)), nil
+		}
+		params.ts = history.Messages[0].Timestamp
+	}
+
+	ch.logger.Debug("Marking conversation as read", zap.String("channel", params.channel), zap.String("ts", params.ts))
+	if err := ch.apiProvider.Slack().MarkConversationContext(ctx, params.channel, params.ts); err != nil {
+		ch.logger.Error("Slack MarkConversationContext failed", zap.Error(err))
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Marked channel %s as read up to message %s", params.channel, params.ts)), nil
+}
+
+// collectUnreads picks the conversations with unreads or mentions from counts
+// and sorts them by priority, the most mentioned and most recent first.
+func (ch *ConversationsHandler) collectUnreads(counts edge.ClientCountsResponse) []Unread {
+	channels := ch.apiProvider.ProvideChannelsMaps().Channels
+
+	unreads := []Unread{}
+	add := func(snapshots []edge.ChannelSnapshot, typ string) {
+		for _, s := range snapshots {
+			if !s.HasUnreads && s.MentionCount == 0 {
+				continue
+			}
+			u := Unread{
+				ChannelID:    s.ID,
+				ChannelName:  channels[s.ID].Name,
+				Type:         typ,
+				MentionCount: s.MentionCount,
+				Latest:       s.Latest.SlackString(),
+			}
+			if !time.Time(s.LastRead).IsZero() {
+				u.LastRead = s.LastRead.SlackString()
+			}
+			unreads = append(unreads, u)
+		}
+	}
+	add(counts.IMs, "im")
+	add(counts.MPIMs, "mpim")
+	add(counts.Channels, "channel")
+
+	rank := func(u Unread) int {
+		switch {
+		case u.Type != "channel":
+			return 0
+		case u.MentionCount > 0:
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(unreads, func(i, j int) bool {
+		a, b := unreads[i], unreads[j]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		if a.MentionCount != b.MentionCount {
+			return a.MentionCount > b.MentionCount
+		}
+		return a.Latest > b.Latest
+	})
+	return unreads
+}
+
+func (ch *ConversationsHandler) parseParamsToolInbox(request mcp.CallToolRequest) *inboxParams {
+	limit := request.GetInt("limit", defaultInboxLimit)
+	if limit <= 0 {
+		limit = defaultInboxLimit
+	}
+	maxMessages := request.GetInt("max_messages", defaultInboxMaxMessages)
+	if maxMessages <= 0 {
+		maxMessages = defaultInboxMaxMessages
+	}
+	if maxMessages > 100 {
+		maxMessages = 100
+	}
+
+	return &inboxParams{
+		limit:           limit,
+		includeMessages: request.GetBool("include_messages", false),
+		maxMessages:     maxMessages,
+	}
+}
+
+func (ch *ConversationsHandler) parseParamsToolMarkRead(ctx context.Context, request mcp.CallToolRequest) (*markReadParams, error) {
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return nil, errors.New("channel_id is required")
+	}
+	channel, err := ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+
+	ts := request.GetString("ts", "")
+	if ts != "" && !strings.Contains(ts, ".") {
+		return nil, errors.New("ts must be a valid timestamp in format 1234567890.123456")
+	}
+
+	return &markReadParams{
+		channel: channel,
+		ts:      ts,
+	}, nil
+}
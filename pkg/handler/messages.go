@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// maxMessagesGet bounds how many messages one messages_get call resolves.
+const maxMessagesGet = 20
+
+var permalinkPath = regexp.MustCompile(`^/archives/([A-Z0-9]+)/p(\d{7,})$`)
+
+// messageRef points at a message, threadTs is set when it is known to be a
+// reply.
+type messageRef struct {
+	channel  string
+	ts       string
+	threadTs string
+}
+
+// MessagesGetHandler returns the messages behind permalinks or channel and ts
+// pairs. A message in a thread comes with its thread: the parent followed by
+// the replies.
+func (ch *ConversationsHandler) MessagesGetHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("MessagesGetHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	refs, err := ch.parseParamsToolMessagesGet(ctx, request)
+	if err != nil {
+		ch.logger.Error("Failed to parse messages_get params", zap.Error(err))
+		return nil, err
+	}
+
+	var messages []Message
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if seen[ref.channel+"/"+ref.ts] {
+			continue
+		}
+		msgs, truncated, err := ch.fetchMessage(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		rows := ch.convertMessagesFromHistory(ctx, msgs, ref.channel, true)
+		if len(rows) > 0 && truncated {
+			rows[0].Truncated = true
+		}
+		for _, r := range rows {
+			key := ref.channel + "/" + r.MsgID
+			if !seen[key] {
+				seen[key] = true
+				messages = append(messages, r)
+			}
+		}
+	}
+	ch.logger.Debug("Fetched messages", zap.Int("count", len(messages)))
+
+	return marshalMessagesToCSV(messages)
+}
+
+// fetchMessage returns the message ref points at, or its whole thread with
+// up to maxExpandedReplies replies. truncated is set when replies are left
+// out.
+func (ch *ConversationsHandler) fetchMessage(ctx context.Context, ref messageRef) ([]slack.Message, bool, error) {
+	if ref.threadTs == "" {
+		history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+			ChannelID: ref.channel,
+			Oldest:    ref.ts,
+			Latest:    ref.ts,
+			Inclusive: true,
+			Limit:     1,
+		})
+		if err != nil {
+			ch.logger.Error("GetConversationHistoryContext failed", zap.String("channel", ref.channel), zap.Error(err))
+			return nil, false, err
+		}
+		if len(history.Messages) > 0 && history.Messages[0].Timestamp == ref.ts {
+			msg := history.Messages[0]
+			if !isThreadParent(&msg) {
+				return history.Messages[:1], false, nil
+			}
+		}
+		// a thread parent, or a reply linked without its thread_ts: replies
+		// of any message of a thread list the thread
+		ref.threadTs = ref.ts
+	}
+
+	replies, hasMore, _, err := ch.apiProvider.Slack().GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+		ChannelID: ref.channel,
+		Timestamp: ref.threadTs,
+		Limit:     maxExpandedReplies,
+	})
+	if err != nil {
+		ch.logger.Error("GetConversationRepliesContext failed", zap.String("channel", ref.channel), zap.String("thread_ts", ref.threadTs), zap.Error(err))
+		return nil, false, fmt.Errorf("message %s not found in channel %s: %w", ref.ts, ref.channel, err)
+	}
+	if len(replies) > 0 {
+		if parent := replies[0].ThreadTimestamp; parent != "" && parent != ref.threadTs {
+			ref.threadTs = parent
+			return ch.fetchMessage(ctx, ref)
+		}
+	}
+	if !hasMore || containsMessage(replies, ref.ts) {
+		return replies, hasMore, nil
+	}
+
+	// the reply is past the first page, list it after what was fetched
+	reply, _, _, err := ch.apiProvider.Slack().GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+		ChannelID: ref.channel,
+		Timestamp: ref.threadTs,
+		Oldest:    ref.ts,
+		Latest:    ref.ts,
+		Inclusive: true,
+		Limit:     2,
+	})
+	if err != nil {
+		ch.logger.Error("GetConversationRepliesContext failed", zap.String("channel", ref.channel), zap.String("ts", ref.ts), zap.Error(err))
+		return nil, false, err
+	}
+	for _, m := range reply {
+		if m.Timestamp == ref.ts {
+			replies = append(replies, m)
+		}
+	}
+	return replies, true, nil
+}
+
+func containsMessage(msgs []slack.Message, ts string) bool {
+	for _, m := range msgs {
+		if m.Timestamp == ts {
+			return true
+		}
+	}
+	return false
+}
+
+func (ch *ConversationsHandler) parseParamsToolMessagesGet(ctx context.Context, request mcp.CallToolRequest) ([]messageRef, error) {
+	raw := strings.FieldsFunc(request.GetString("messages", ""), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\t'
+	})
+	if len(raw) == 0 {
+		return nil, errors.New("messages must list at least one permalink or channel_id:ts pair")
+	}
+	if len(raw) > maxMessagesGet {
+		return nil, fmt.Errorf("messages must list at most %d entries", maxMessagesGet)
+	}
+
+	refs := make([]messageRef, 0, len(raw))
+	for _, item := range raw {
+		ref, ok := parsePermalink(item)
+		if !ok {
+			channel, ts, found := strings.Cut(item, ":")
+			if !found || channel == "" || !strings.Contains(ts, ".") {
+				return nil, fmt.Errorf("invalid message %q: expected a permalink or channel_id:ts such as #general:1234567890.123456", item)
+			}
+			ref = messageRef{channel: channel, ts: ts}
+		}
+		channel, err := ch.resolveChannelID(ctx, ref.channel)
+		if err != nil {
+			ch.logger.Error("Channel not found", zap.String("channel", ref.channel), zap.Error(err))
+			return nil, err
+		}
+		ref.channel = channel
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// parsePermalink reads the channel and ts of a message link such as
+// https://example.slack.com/archives/C0123/p1700000000000100?thread_ts=1699999999.000100
+func parsePermalink(raw string) (messageRef, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return messageRef{}, false
+	}
+	m := permalinkPath.FindStringSubmatch(u.Path)
+	if m == nil {
+		return messageRef{}, false
+	}
+	ts := m[2][:len(m[2])-6] + "." + m[2][len(m[2])-6:]
+	threadTs := u.Query().Get("thread_ts")
+	if threadTs == ts {
+		threadTs = ""
+	}
+	return messageRef{channel: m[1], ts: ts, threadTs: threadTs}, true
+}
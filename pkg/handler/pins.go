@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// PinsListHandler returns the messages pinned to a channel in the same CSV
+// format as conversations_history. Pinned files are left out.
+func (ch *ConversationsHandler) PinsListHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("PinsListHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	channel, err := ch.pinsChannelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	items, _, err := ch.apiProvider.Slack().ListPinsContext(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Slack ListPinsContext failed", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+
+	var msgs []slack.Message
+	for _, item := range items {
+		if item.Message != nil {
+			msgs = append(msgs, *item.Message)
+		}
+	}
+	return marshalMessagesToCSV(ch.convertMessagesFromHistory(ctx, msgs, channel, true))
+}
+
+// PinsAddHandler pins a message to its channel.
+func (ch *ConversationsHandler) PinsAddHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("PinsAddHandler called", zap.Any("params", request.Params))
+
+	params, err := ch.parseParamsToolPin(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if err := ch.apiProvider.Slack().AddPinContext(ctx, params.channel, slack.ItemRef{Channel: params.channel, Timestamp: params.timestamp}); err != nil {
+		ch.logger.Error("Slack AddPinContext failed", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Pinned message %s in channel %s", params.timestamp, params.channel)), nil
+}
+
+// PinsRemoveHandler unpins a message.
+func (ch *ConversationsHandler) PinsRemoveHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("PinsRemoveHandler called", zap.Any("params", request.Params))
+
+	params, err := ch.parseParamsToolPin(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if err := ch.apiProvider.Slack().RemovePinContext(ctx, params.channel, slack.ItemRef{Channel: params.channel, Timestamp: params.timestamp}); err != nil {
+		ch.logger.Error("Slack RemovePinContext failed", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Unpinned message %s in channel %s", params.timestamp, params.channel)), nil
+}
+
+// BookmarksListHandler returns the bookmarks in a channel header as CSV.
+func (ch *ConversationsHandler) BookmarksListHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("BookmarksListHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	channel, err := ch.pinsChannelParam(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks, err := ch.apiProvider.Slack().ListBookmarksContext(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Slack ListBookmarksContext failed", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+
+	items := make([]ChannelBookmark, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		items = append(items, ChannelBookmark{ID: b.ID, Title: b.Title, Link: b.Link, Type: b.Type})
+	}
+	csvBytes, err := gocsv.MarshalBytes(&items)
+	if err != nil {
+		ch.logger.Error("Failed to marshal bookmarks to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// BookmarksAddHandler adds a link bookmark to a channel header.
+func (ch *ConversationsHandler) BookmarksAddHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("BookmarksAddHandler called", zap.Any("params", request.Params))
+
+	channel, err := ch.parseParamsToolPinChannel(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	title := strings.TrimSpace(request.GetString("title", ""))
+	if title == "" {
+		return nil, errors.New("title is required")
+	}
+	link := strings.TrimSpace(request.GetString("link", ""))
+	if link == "" {
+		return nil, errors.New("link is required")
+	}
+
+	bookmark, err := ch.apiProvider.Slack().AddBookmarkContext(ctx, channel, slack.AddBookmarkParameters{
+		Title: title,
+		Type:  "link",
+		Link:  link,
+		Emoji: request.GetString("emoji", ""),
+	})
+	if err != nil {
+		ch.logger.Error("Slack AddBookmarkContext failed", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Added bookmark %s %q to channel %s", bookmark.ID, bookmark.Title, channel)), nil
+}
+
+// BookmarksRemoveHandler removes a bookmark from a channel header.
+func (ch *ConversationsHandler) BookmarksRemoveHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("BookmarksRemoveHandler called", zap.Any("params", request.Params))
+
+	channel, err := ch.parseParamsToolPinChannel(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	bookmarkID := strings.TrimSpace(request.GetString("bookmark_id", ""))
+	if bookmarkID == "" {
+		return nil, errors.New("bookmark_id is required")
+	}
+
+	if err := ch.apiProvider.Slack().RemoveBookmarkContext(ctx, channel, bookmarkID); err != nil {
+		ch.logger.Error("Slack RemoveBookmarkContext failed", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Removed bookmark %s from channel %s", bookmarkID, channel)), nil
+}
+
+type pinParams struct {
+	channel   string
+	timestamp string
+}
+
+func (ch *ConversationsHandler) pinsChannelParam(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return "", errors.New("channel_id is required")
+	}
+	channel, err := ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return "", err
+	}
+	return channel, nil
+}
+
+// parseParamsToolPinChannel resolves the channel of a pin or bookmark write
+// and checks it against SLACK_MCP_PIN_TOOL.
+func (ch *ConversationsHandler) parseParamsToolPinChannel(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	toolConfig := os.Getenv("SLACK_MCP_PIN_TOOL")
+	if toolConfig == "" {
+		ch.logger.Error("Pins tool disabled by default")
+		return "", errors.New(
+			"by default, the pins and bookmarks write tools are disabled to guard Slack workspaces against accidental changes. " +
+				"To enable them, set the SLACK_MCP_PIN_TOOL environment variable to true, 1, or comma separated list of channels " +
+				"to limit where the MCP can manage pins and bookmarks, e.g. 'SLACK_MCP_PIN_TOOL=C1234567890,D0987654321', 'SLACK_MCP_PIN_TOOL=!C1234567890' " +
+				"to enable all except one or 'SLACK_MCP_PIN_TOOL=true' for all channels and DMs",
+		)
+	}
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return "", err
+	}
+
+	channel, err := ch.pinsChannelParam(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	if !isChannelAllowedForConfig(channel, toolConfig) {
+		ch.logger.Warn("Pins tool not allowed for channel", zap.String("channel", channel), zap.String("policy", toolConfig))
+		return "", fmt.Errorf("pins and bookmarks tools are not allowed for channel %q, applied policy: %s", channel, toolConfig)
+	}
+	return channel, nil
+}
+
+func (ch *ConversationsHandler) parseParamsToolPin(ctx context.Context, request mcp.CallToolRequest) (*pinParams, error) {
+	channel, err := ch.parseParamsToolPinChannel(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	timestamp := request.GetString("timestamp", "")
+	if timestamp == "" {
+		return nil, errors.New("timestamp is required")
+	}
+	return &pinParams{channel: channel, timestamp: timestamp}, nil
+}
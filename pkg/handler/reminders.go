@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// defaultReminderHour is used when a reminder only names a day, e.g.
+// "tomorrow", which is what Slack does as well.
+const defaultReminderHour = 9
+
+var errReminderWriteDisabled = errors.New(
+	"reminder write tools are disabled by default. " +
+		"To enable them, set the SLACK_MCP_REMINDER_TOOL environment variable to 'true'")
+
+var (
+	reminderRelative  = regexp.MustCompile(`^in\s+(.+)$`)
+	reminderDuration  = regexp.MustCompile(`(\d+)\s*([a-z]+)`)
+	reminderTimeOfDay = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+// ReminderItem is a reminder as returned by the reminders tools, times are
+// in the timezone of the reminded user.
+type ReminderItem struct {
+	ID        string `csv:"ID"`
+	UserID    string `csv:"UserID"`
+	UserName  string `csv:"UserName"`
+	Text      string `csv:"Text"`
+	Time      string `csv:"Time"`
+	Recurring bool   `csv:"Recurring"`
+	Completed string `csv:"Completed"`
+}
+
+type RemindersHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewRemindersHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *RemindersHandler {
+	return &RemindersHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// isReminderWriteEnabled checks if the reminder write tools are enabled via
+// env var.
+func isReminderWriteEnabled() bool {
+	v := strings.ToLower(os.Getenv("SLACK_MCP_REMINDER_TOOL"))
+	return v == "true" || v == "1" || v == "yes"
+}
+
+// RemindersAddHandler creates a reminder for the authenticated user or
+// someone else. Times are parsed in the timezone of the reminded user,
+// phrases starting with "every" are left to Slack as recurring reminders.
+func (rh *RemindersHandler) RemindersAddHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rh.logger.Debug("RemindersAddHandler called", zap.Any("params", request.Params))
+
+	if !isReminderWriteEnabled() {
+		return nil, errReminderWriteDisabled
+	}
+	if ready, err := rh.apiProvider.IsReady(); !ready {
+		rh.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	text := strings.TrimSpace(request.GetString("text", ""))
+	if text == "" {
+		return nil, errors.New("text is required")
+	}
+
+	userID := selfUserID(rh.apiProvider)
+	if raw := request.GetString("user", ""); raw != "" {
+		id, err := resolveUserID(rh.apiProvider, raw)
+		if err != nil {
+			return nil, err
+		}
+		userID = id
+	}
+
+	when, _, err := parseReminderTime(request.GetString("time", ""), time.Now().In(rh.userLocation(userID)))
+	if err != nil {
+		return nil, err
+	}
+
+	reminder, err := rh.apiProvider.Slack().AddUserReminderContext(ctx, userID, text, when)
+	if err != nil {
+		rh.logger.Error("Slack AddUserReminderContext failed", zap.String("user", userID), zap.Error(err))
+		return nil, err
+	}
+	return rh.marshalReminders([]*slack.Reminder{reminder})
+}
+
+// RemindersListHandler lists the reminders created by or for the
+// authenticated user, soonest first.
+func (rh *RemindersHandler) RemindersListHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rh.logger.Debug("RemindersListHandler called", zap.Any("params", request.Params))
+
+	if ready, err := rh.apiProvider.IsReady(); !ready {
+		rh.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	reminders, err := rh.apiProvider.Slack().ListRemindersContext(ctx)
+	if err != nil {
+		rh.logger.Error("Slack ListRemindersContext failed", zap.Error(err))
+		return nil, err
+	}
+
+	includeCompleted := request.GetBool("include_completed", false)
+	filtered := reminders[:0:0]
+	for _, r := range reminders {
+		if r.CompleteTS == 0 || includeCompleted {
+			filtered = append(filtered, r)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Time < filtered[j].Time
+	})
+	return rh.marshalReminders(filtered)
+}
+
+// RemindersCompleteHandler marks a reminder as complete.
+func (rh *RemindersHandler) RemindersCompleteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rh.logger.Debug("RemindersCompleteHandler called", zap.Any("params", request.Params))
+
+	id, err := rh.reminderParam(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := rh.apiProvider.Slack().CompleteReminderContext(ctx, id); err != nil {
+		rh.logger.Error("Slack CompleteReminderContext failed", zap.String("reminder", id), zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Completed reminder %s", id)), nil
+}
+
+// RemindersDeleteHandler deletes a reminder.
+func (rh *RemindersHandler) RemindersDeleteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rh.logger.Debug("RemindersDeleteHandler called", zap.Any("params", request.Params))
+
+	id, err := rh.reminderParam(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := rh.apiProvider.Slack().DeleteReminderContext(ctx, id); err != nil {
+		rh.logger.Error("Slack DeleteReminderContext failed", zap.String("reminder", id), zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Deleted reminder %s", id)), nil
+}
+
+func (rh *RemindersHandler) reminderParam(request mcp.CallToolRequest) (string, error) {
+	if !isReminderWriteEnabled() {
+		return "", errReminderWriteDisabled
+	}
+	if ready, err := rh.apiProvider.IsReady(); !ready {
+		rh.logger.Error("API provider not ready", zap.Error(err))
+		return "", err
+	}
+	id := strings.TrimSpace(request.GetString("reminder_id", ""))
+	if id == "" {
+		return "", errors.New("reminder_id is required")
+	}
+	return id, nil
+}
+
+// selfUserID returns the ID of the authenticated user, empty when auth.test
+// hasn't run yet.
+func selfUserID(ap *provider.ApiProvider) string {
+	if ar, err := ap.AuthResponse(); err == nil {
+		return ar.UserID
+	}
+	return ""
+}
+
+// userLocation returns the timezone of a user from the users cache.
+func (rh *RemindersHandler) userLocation(userID string) *time.Location {
+	return userLocation(rh.apiProvider.ProvideUsersMap().Users[userID])
+}
+
+func (rh *RemindersHandler) marshalReminders(reminders []*slack.Reminder) (*mcp.CallToolResult, error) {
+	usersMap := rh.apiProvider.ProvideUsersMap().Users
+	items := make([]ReminderItem, 0, len(reminders))
+	for _, r := range reminders {
+		loc := rh.userLocation(r.User)
+		item := ReminderItem{
+			ID:        r.ID,
+			UserID:    r.User,
+			Text:      r.Text,
+			Recurring: r.Recurring,
+		}
+		item.UserName, _, _ = getUserInfo(r.User, usersMap)
+		if r.Time > 0 {
+			item.Time = time.Unix(int64(r.Time), 0).In(loc).Format(time.RFC3339)
+		}
+		if r.CompleteTS > 0 {
+			item.Completed = time.Unix(int64(r.CompleteTS), 0).In(loc).Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&items)
+	if err != nil {
+		rh.logger.Error("Failed to marshal reminders to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// parseReminderTime turns the time of a reminder into the value reminders.add
+// takes. It understands Unix timestamps, RFC 3339, relative durations such as
+// "in 2h" or "in 1 day 3 hours", and a day as accepted by parseFlexibleDateAt
+// or a weekday name, optionally followed by a time of day ("tomorrow at
+// 9:30am", "friday 17:00", "3pm"). Days and times of day are taken in the
+// location of now. Phrases starting with "every" are passed through for Slack
+// to parse as a recurring reminder, in which case the returned time is zero.
+func parseReminderTime(value string, now time.Time) (string, time.Time, error) {
+	value = strings.TrimSpace(value)
+	lower := strings.ToLower(value)
+	if lower == "" {
+		return "", time.Time{}, errors.New("time is required")
+	}
+	if strings.HasPrefix(lower, "every ") {
+		return value, time.Time{}, nil
+	}
+
+	t, err := parseReminderAbsolute(lower, now)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if !t.After(now) {
+		return "", time.Time{}, fmt.Errorf("time %q is in the past", value)
+	}
+	return strconv.FormatInt(t.Unix(), 10), t, nil
+}
+
+func parseReminderAbsolute(lower string, now time.Time) (time.Time, error) {
+	if secs, err := strconv.ParseInt(lower, 10, 64); err == nil {
+		return time.Unix(secs, 0).In(now.Location()), nil
+	}
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(lower)); err == nil {
+		return t, nil
+	}
+	if m := reminderRelative.FindStringSubmatch(lower); m != nil {
+		d, err := parseReminderDuration(m[1])
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+
+	day, clock := lower, ""
+	if before, after, found := strings.Cut(lower, " at "); found {
+		day, clock = strings.TrimSpace(before), strings.TrimSpace(after)
+	} else if after, found := strings.CutPrefix(lower, "at "); found {
+		day, clock = "", strings.TrimSpace(after)
+	} else {
+		fields := strings.Fields(lower)
+		for i := range fields {
+			if _, _, ok := parseTimeOfDay(strings.Join(fields[i:], " ")); ok {
+				day, clock = strings.Join(fields[:i], " "), strings.Join(fields[i:], " ")
+				break
+			}
+		}
+	}
+	// "at 9" is clear enough to be read on a 24-hour clock
+	if n, err := strconv.Atoi(clock); err == nil && n >= 0 && n <= 23 {
+		clock += ":00"
+	}
+
+	hour, minute := defaultReminderHour, 0
+	if clock != "" {
+		var ok bool
+		if hour, minute, ok = parseTimeOfDay(clock); !ok {
+			return time.Time{}, fmt.Errorf("unable to parse time of day: %s", clock)
+		}
+	}
+
+	if day == "" {
+		t := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+
+	date, err := parseReminderDay(day, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, now.Location()), nil
+}
+
+// parseReminderDay parses a day for a reminder, weekday names mean the next
+// such day after today.
+func parseReminderDay(day string, now time.Time) (time.Time, error) {
+	name := strings.TrimPrefix(day, "next ")
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		full := strings.ToLower(wd.String())
+		if name == full || name == full[:3] {
+			days := (int(wd) - int(now.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return now.AddDate(0, 0, days), nil
+		}
+	}
+	t, _, err := parseFlexibleDateAt(day, now)
+	return t, err
+}
+
+// parseTimeOfDay parses "9am", "9:30 pm", "17:00", "noon" and "midnight".
+// Times without am/pm are read on a 24-hour clock and need the minutes.
+func parseTimeOfDay(s string) (hour, minute int, ok bool) {
+	switch s {
+	case "noon":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+	m := reminderTimeOfDay.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, false
+	}
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if minute > 59 {
+		return 0, 0, false
+	}
+	if m[3] == "" {
+		return hour, minute, m[2] != "" && hour <= 23
+	}
+	if hour < 1 || hour > 12 {
+		return 0, 0, false
+	}
+	hour %= 12
+	if m[3] == "pm" {
+		hour += 12
+	}
+	return hour, minute, true
+}
+
+// parseReminderDuration parses "2h", "90 minutes", "1h30m" or "1 day 2 hours".
+func parseReminderDuration(s string) (time.Duration, error) {
+	var total time.Duration
+	matches := reminderDuration.FindAllStringSubmatchIndex(s, -1)
+	rest := s
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		n, _ := strconv.Atoi(s[m[2]:m[3]])
+		unit, ok := reminderUnit(s[m[4]:m[5]])
+		if !ok {
+			return 0, fmt.Errorf("unable to parse duration: %s", s)
+		}
+		total += time.Duration(n) * unit
+		rest = rest[:m[0]] + rest[m[1]:]
+	}
+	rest = strings.NewReplacer(",", "", "and", "").Replace(rest)
+	if len(matches) == 0 || strings.TrimSpace(rest) != "" {
+		return 0, fmt.Errorf("unable to parse duration: %s", s)
+	}
+	return total, nil
+}
+
+func reminderUnit(unit string) (time.Duration, bool) {
+	switch unit {
+	case "m", "min", "mins", "minute", "minutes":
+		return time.Minute, true
+	case "h", "hr", "hrs", "hour", "hours":
+		return time.Hour, true
+	case "d", "day", "days":
+		return 24 * time.Hour, true
+	case "w", "week", "weeks":
+		return 7 * 24 * time.Hour, true
+	}
+	return 0, false
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitParseReminderTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// a Sunday afternoon
+	now := time.Date(2026, 10, 18, 14, 0, 0, 0, berlin)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"in 2h", "2026-10-18T16:00:00+02:00"},
+		{"in 90 minutes", "2026-10-18T15:30:00+02:00"},
+		{"in 1h30m", "2026-10-18T15:30:00+02:00"},
+		{"in 1 day and 3 hours", "2026-10-19T17:00:00+02:00"},
+		{"3pm", "2026-10-18T15:00:00+02:00"},
+		{"9am", "2026-10-19T09:00:00+02:00"},
+		{"at 9", "2026-10-19T09:00:00+02:00"},
+		{"tomorrow", "2026-10-19T09:00:00+02:00"},
+		{"Tomorrow at 9:30 pm", "2026-10-19T21:30:00+02:00"},
+		{"friday 17:00", "2026-10-23T17:00:00+02:00"},
+		{"next sunday at noon", "2026-10-25T12:00:00+01:00"},
+		{"2026-11-02 at 10:30am", "2026-11-02T10:30:00+01:00"},
+		{"2026-10-20T08:00:00Z", "2026-10-20T08:00:00Z"},
+		{"1792800000", "2026-10-24T02:00:00+02:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			value, got, err := parseReminderTime(tt.input, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Format(time.RFC3339))
+			assert.Equal(t, strconv.FormatInt(got.Unix(), 10), value)
+		})
+	}
+
+	value, got, err := parseReminderTime("every weekday at 9am", now)
+	require.NoError(t, err)
+	assert.Equal(t, "every weekday at 9am", value)
+	assert.True(t, got.IsZero())
+
+	for _, input := range []string{"", "1792000000", "yesterday at 9am", "in 2 fortnights", "someday", "tomorrow at 13pm", "tomorrow at 9:75"} {
+		_, _, err := parseReminderTime(input, now)
+		assert.Error(t, err, input)
+	}
+}
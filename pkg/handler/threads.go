@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+type expandedThread struct {
+	replies []slack.Message
+	hasMore bool
+}
+
+// expandThreads converts a page of history with the replies of up to
+// maxThreads thread parents placed right after each parent. Replies are
+// fetched concurrently; parents whose replies are not all listed are marked
+// as truncated.
+func (ch *ConversationsHandler) expandThreads(ctx context.Context, channel string, history []slack.Message, maxThreads int, includeActivity bool) ([]Message, error) {
+	threads := make(map[string]*expandedThread)
+	var parents []string
+	for _, m := range history {
+		if isThreadParent(&m) && len(parents) < maxThreads {
+			parents = append(parents, m.Timestamp)
+		}
+	}
+
+	results := make([]expandedThread, len(parents))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(expandThreadsConcurrency)
+	for i, ts := range parents {
+		g.Go(func() error {
+			msgs, hasMore, _, err := ch.apiProvider.Slack().GetConversationRepliesContext(gctx, &slack.GetConversationRepliesParameters{
+				ChannelID: channel,
+				Timestamp: ts,
+				Limit:     maxExpandedReplies,
+			})
+			if err != nil {
+				ch.logger.Error("GetConversationRepliesContext failed", zap.String("thread_ts", ts), zap.Error(err))
+				return err
+			}
+			results[i].hasMore = hasMore
+			for _, r := range msgs {
+				if r.Timestamp != ts {
+					results[i].replies = append(results[i].replies, r)
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	for i, ts := range parents {
+		threads[ts] = &results[i]
+	}
+	ch.logger.Debug("Expanded threads", zap.Int("threads", len(parents)))
+
+	// replies broadcast to the channel are listed in their thread only
+	broadcast := make(map[string]bool)
+	for _, t := range threads {
+		for _, r := range t.replies {
+			broadcast[r.Timestamp] = true
+		}
+	}
+
+	var messages []Message
+	for _, m := range history {
+		if broadcast[m.Timestamp] && m.Timestamp != m.ThreadTimestamp {
+			continue
+		}
+		rows := ch.convertMessagesFromHistory(ctx, []slack.Message{m}, channel, includeActivity)
+		if len(rows) == 0 {
+			continue
+		}
+		if isThreadParent(&m) {
+			t, ok := threads[m.Timestamp]
+			rows[0].Truncated = !ok || t.hasMore
+			if ok {
+				rows = append(rows, ch.convertMessagesFromHistory(ctx, t.replies, channel, includeActivity)...)
+			}
+		}
+		messages = append(messages, rows...)
+	}
+	return messages, nil
+}
+
+func isThreadParent(m *slack.Message) bool {
+	return m.ReplyCount > 0 && (m.ThreadTimestamp == "" || m.ThreadTimestamp == m.Timestamp)
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+var (
+	errUserGroupWriteDisabled = errors.New(
+		"user group write tools are disabled by default. " +
+			"To enable them, set the SLACK_MCP_USERGROUP_TOOL environment variable to 'true'")
+
+	subteamMentionRegex = regexp.MustCompile(`<!subteam\^([A-Z0-9]+)(?:\|([^>]*))?>`)
+)
+
+// UserGroupItem is a user group (subteam) as listed by usergroups_list.
+type UserGroupItem struct {
+	ID          string `csv:"ID"`
+	Handle      string `csv:"Handle"`
+	Name        string `csv:"Name"`
+	Description string `csv:"Description"`
+	MemberCount int    `csv:"MemberCount"`
+}
+
+type UserGroupsHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewUserGroupsHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *UserGroupsHandler {
+	return &UserGroupsHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// isUserGroupWriteEnabled checks if the user group write tool is enabled via
+// env var.
+func isUserGroupWriteEnabled() bool {
+	v := strings.ToLower(os.Getenv("SLACK_MCP_USERGROUP_TOOL"))
+	return v == "true" || v == "1" || v == "yes"
+}
+
+// UserGroupsListHandler lists the user groups of the workspace from the user
+// groups cache, sorted by handle.
+func (uh *UserGroupsHandler) UserGroupsListHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("UserGroupsListHandler called", zap.Any("params", request.Params))
+
+	if ready, err := uh.apiProvider.IsReady(); !ready {
+		uh.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	query := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(request.GetString("query", "")), "@"))
+	items := []UserGroupItem{}
+	for _, g := range uh.apiProvider.ProvideUserGroups(ctx).UserGroups {
+		if query != "" &&
+			!strings.Contains(strings.ToLower(g.Handle), query) &&
+			!strings.Contains(strings.ToLower(g.Name), query) &&
+			!strings.Contains(strings.ToLower(g.Description), query) {
+			continue
+		}
+		count := g.UserCount
+		if count == 0 {
+			count = len(g.Users)
+		}
+		items = append(items, UserGroupItem{
+			ID:          g.ID,
+			Handle:      "@" + g.Handle,
+			Name:        g.Name,
+			Description: g.Description,
+			MemberCount: count,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Handle < items[j].Handle })
+
+	csvBytes, err := gocsv.MarshalBytes(&items)
+	if err != nil {
+		uh.logger.Error("Failed to marshal user groups to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// UserGroupsMembersHandler lists the members of a user group. Members are
+// read from Slack, names come from the users cache.
+func (uh *UserGroupsHandler) UserGroupsMembersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("UserGroupsMembersHandler called", zap.Any("params", request.Params))
+
+	if ready, err := uh.apiProvider.IsReady(); !ready {
+		uh.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	groupID, err := resolveUserGroupID(ctx, uh.apiProvider, request.GetString("usergroup", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := uh.apiProvider.Slack().GetUserGroupMembersContext(ctx, groupID)
+	if err != nil {
+		uh.logger.Error("Slack GetUserGroupMembersContext failed", zap.String("usergroup", groupID), zap.Error(err))
+		return nil, err
+	}
+
+	users := uh.apiProvider.ProvideUsersMap().Users
+	members := make([]User, 0, len(ids))
+	for _, id := range ids {
+		userName, realName, _ := getUserInfo(id, users)
+		members = append(members, User{UserID: id, UserName: userName, RealName: realName})
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&members)
+	if err != nil {
+		uh.logger.Error("Failed to marshal user group members to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// UserGroupsUpdateMembersHandler adds and removes members of a user group.
+// Slack replaces the whole member list, so the current members are read
+// first.
+func (uh *UserGroupsHandler) UserGroupsUpdateMembersHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("UserGroupsUpdateMembersHandler called", zap.Any("params", request.Params))
+
+	if !isUserGroupWriteEnabled() {
+		return nil, errUserGroupWriteDisabled
+	}
+	if ready, err := uh.apiProvider.IsReady(); !ready {
+		uh.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	groupID, err := resolveUserGroupID(ctx, uh.apiProvider, request.GetString("usergroup", ""))
+	if err != nil {
+		return nil, err
+	}
+	add, err := uh.userIDs(request.GetString("add", ""))
+	if err != nil {
+		return nil, err
+	}
+	remove, err := uh.userIDs(request.GetString("remove", ""))
+	if err != nil {
+		return nil, err
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, errors.New("add or remove is required")
+	}
+
+	current, err := uh.apiProvider.Slack().GetUserGroupMembersContext(ctx, groupID)
+	if err != nil {
+		uh.logger.Error("Slack GetUserGroupMembersContext failed", zap.String("usergroup", groupID), zap.Error(err))
+		return nil, err
+	}
+	var members []string
+	for _, id := range current {
+		if !slices.Contains(remove, id) {
+			members = append(members, id)
+		}
+	}
+	for _, id := range add {
+		if !slices.Contains(members, id) {
+			members = append(members, id)
+		}
+	}
+	if len(members) == 0 {
+		return nil, errors.New("a user group can't be left without members")
+	}
+
+	group, err := uh.apiProvider.Slack().UpdateUserGroupMembersContext(ctx, groupID, strings.Join(members, ","))
+	if err != nil {
+		uh.logger.Error("Slack UpdateUserGroupMembersContext failed", zap.String("usergroup", groupID), zap.Error(err))
+		return nil, err
+	}
+	group.Users = members
+	group.UserCount = len(members)
+	uh.apiProvider.UpdateUserGroup(group)
+
+	return mcp.NewToolResultText(fmt.Sprintf("Updated user group @%s, it has %d members now", group.Handle, len(members))), nil
+}
+
+func (uh *UserGroupsHandler) userIDs(list string) ([]string, error) {
+	var ids []string
+	for _, raw := range strings.Split(list, ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		id, err := resolveUserID(uh.apiProvider, raw)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// resolveUserGroupID accepts a user group ID, a handle with or without @ or
+// a subteam mention as it appears in message text.
+func resolveUserGroupID(ctx context.Context, ap *provider.ApiProvider, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", errors.New("usergroup is required")
+	}
+	if m := subteamMentionRegex.FindStringSubmatch(raw); m != nil {
+		return m[1], nil
+	}
+	groups := ap.ProvideUserGroups(ctx)
+	if _, ok := groups.UserGroups[raw]; ok {
+		return raw, nil
+	}
+	if id, ok := groups.UserGroupsInv[strings.TrimPrefix(raw, "@")]; ok {
+		return id, nil
+	}
+	if strings.HasPrefix(raw, "S") && strings.ToUpper(raw) == raw {
+		return raw, nil
+	}
+	return "", fmt.Errorf("user group %q not found", raw)
+}
+
+// resolveSubteamMentions replaces <!subteam^S123|@handle> mentions in message
+// text with the handle of the group, taken from the user groups cache and falling
+// back to the label Slack put in the mention.
+func resolveSubteamMentions(text string, groups map[string]slack.UserGroup) string {
+	return subteamMentionRegex.ReplaceAllStringFunc(text, func(mention string) string {
+		m := subteamMentionRegex.FindStringSubmatch(mention)
+		if g, ok := groups[m[1]]; ok && g.Handle != "" {
+			return "@" + g.Handle
+		}
+		if m[2] != "" {
+			return "@" + strings.TrimPrefix(m[2], "@")
+		}
+		return "@" + m[1]
+	})
+}
@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const maxPresenceUsers = 50
+
+var errStatusWriteDisabled = errors.New(
+	"status tools are disabled by default. " +
+		"To enable them, set the SLACK_MCP_STATUS_TOOL environment variable to 'true'")
+
+// UserPresence tells whether a user can be reached right now. Times are in
+// the user's own timezone.
+type UserPresence struct {
+	UserID           string `csv:"UserID"`
+	UserName         string `csv:"UserName"`
+	RealName         string `csv:"RealName"`
+	Presence         string `csv:"Presence"`
+	StatusText       string `csv:"StatusText"`
+	StatusEmoji      string `csv:"StatusEmoji"`
+	StatusExpiration string `csv:"StatusExpiration"`
+	InDND            bool   `csv:"InDND"`
+	DNDStart         string `csv:"DNDStart"`
+	DNDEnd           string `csv:"DNDEnd"`
+	SnoozeEnd        string `csv:"SnoozeEnd"`
+	LocalTime        string `csv:"LocalTime"`
+	TZ               string `csv:"TZ"`
+}
+
+type UsersHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewUsersHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *UsersHandler {
+	return &UsersHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// isStatusWriteEnabled checks if the status and DND write tools are enabled
+// via env var.
+func isStatusWriteEnabled() bool {
+	v := strings.ToLower(os.Getenv("SLACK_MCP_STATUS_TOOL"))
+	return v == "true" || v == "1" || v == "yes"
+}
+
+// UsersGetPresenceHandler returns presence, custom status, Do Not Disturb
+// window and local time of one or more users. Profiles are read live rather
+// than from the users cache as statuses change often; presence and DND are
+// best effort.
+func (uh *UsersHandler) UsersGetPresenceHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("UsersGetPresenceHandler called", zap.Any("params", request.Params))
+
+	if ready, err := uh.apiProvider.IsReady(); !ready {
+		uh.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	var ids []string
+	for _, raw := range strings.Split(request.GetString("users", ""), ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		id, err := resolveUserID(uh.apiProvider, raw)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		ids = []string{selfUserID(uh.apiProvider)}
+	}
+	if len(ids) > maxPresenceUsers {
+		return nil, fmt.Errorf("at most %d users can be looked up at once", maxPresenceUsers)
+	}
+
+	users, err := uh.apiProvider.Slack().GetUsersInfo(ids...)
+	if err != nil {
+		uh.logger.Error("Slack GetUsersInfo failed", zap.Strings("users", ids), zap.Error(err))
+		return nil, err
+	}
+
+	dnd := uh.dndStatuses(ctx, ids)
+	now := time.Now()
+	rows := make([]UserPresence, 0, len(*users))
+	for _, u := range *users {
+		row := uh.userPresence(u, dnd[u.ID], now)
+		if p, err := uh.apiProvider.Slack().GetUserPresenceContext(ctx, u.ID); err != nil {
+			uh.logger.Warn("Slack GetUserPresenceContext failed", zap.String("user", u.ID), zap.Error(err))
+		} else {
+			row.Presence = p.Presence
+		}
+		rows = append(rows, row)
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&rows)
+	if err != nil {
+		uh.logger.Error("Failed to marshal presence to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// UsersSetStatusHandler sets or clears the custom status of the
+// authenticated user.
+func (uh *UsersHandler) UsersSetStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("UsersSetStatusHandler called", zap.Any("params", request.Params))
+
+	if !isStatusWriteEnabled() {
+		return nil, errStatusWriteDisabled
+	}
+	if ready, err := uh.apiProvider.IsReady(); !ready {
+		uh.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	text := strings.TrimSpace(request.GetString("status_text", ""))
+	emoji := strings.Trim(strings.TrimSpace(request.GetString("status_emoji", "")), ":")
+	if emoji != "" {
+		emoji = ":" + emoji + ":"
+	}
+
+	var expiration time.Time
+	if raw := request.GetString("expiration", ""); raw != "" {
+		if text == "" && emoji == "" {
+			return nil, errors.New("expiration needs a status_text or status_emoji")
+		}
+		self := selfUserID(uh.apiProvider)
+		now := time.Now().In(userLocation(uh.apiProvider.ProvideUsersMap().Users[self]))
+		_, t, err := parseReminderTime(raw, now)
+		if err != nil {
+			return nil, err
+		}
+		if t.IsZero() {
+			return nil, errors.New("expiration must be a single point in time, not a recurring phrase")
+		}
+		expiration = t
+	}
+
+	var unix int64
+	if !expiration.IsZero() {
+		unix = expiration.Unix()
+	}
+	if err := uh.apiProvider.Slack().SetUserCustomStatusContext(ctx, text, emoji, unix); err != nil {
+		uh.logger.Error("Slack SetUserCustomStatusContext failed", zap.Error(err))
+		return nil, err
+	}
+
+	switch {
+	case text == "" && emoji == "":
+		return mcp.NewToolResultText("Cleared status"), nil
+	case expiration.IsZero():
+		return mcp.NewToolResultText(fmt.Sprintf("Set status to %s %s", emoji, text)), nil
+	default:
+		return mcp.NewToolResultText(fmt.Sprintf("Set status to %s %s until %s", emoji, text, expiration.Format(time.RFC3339))), nil
+	}
+}
+
+// DNDSetSnoozeHandler pauses notifications of the authenticated user for a
+// number of minutes, or ends the snooze when minutes is 0.
+func (uh *UsersHandler) DNDSetSnoozeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("DNDSetSnoozeHandler called", zap.Any("params", request.Params))
+
+	if !isStatusWriteEnabled() {
+		return nil, errStatusWriteDisabled
+	}
+	if ready, err := uh.apiProvider.IsReady(); !ready {
+		uh.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	minutes := request.GetInt("minutes", -1)
+	if minutes < 0 || minutes > 24*60 {
+		return nil, errors.New("minutes must be an integer between 0 and 1440")
+	}
+
+	if minutes == 0 {
+		if _, err := uh.apiProvider.Slack().EndSnoozeContext(ctx); err != nil {
+			uh.logger.Error("Slack EndSnoozeContext failed", zap.Error(err))
+			return nil, err
+		}
+		return mcp.NewToolResultText("Ended snooze, notifications are on again"), nil
+	}
+
+	status, err := uh.apiProvider.Slack().SetSnoozeContext(ctx, minutes)
+	if err != nil {
+		uh.logger.Error("Slack SetSnoozeContext failed", zap.Int("minutes", minutes), zap.Error(err))
+		return nil, err
+	}
+	loc := userLocation(uh.apiProvider.ProvideUsersMap().Users[selfUserID(uh.apiProvider)])
+	until := time.Unix(int64(status.SnoozeEndTime), 0).In(loc).Format(time.RFC3339)
+	return mcp.NewToolResultText(fmt.Sprintf("Snoozed notifications until %s", until)), nil
+}
+
+// dndStatuses reads the DND schedules of users. When dnd.teamInfo is not
+// available the authenticated user's schedule is taken from client.userBoot.
+func (uh *UsersHandler) dndStatuses(ctx context.Context, ids []string) map[string]slack.DNDStatus {
+	dnd, err := uh.apiProvider.Slack().GetDNDTeamInfoContext(ctx, ids)
+	if err == nil {
+		return dnd
+	}
+	uh.logger.Warn("Slack GetDNDTeamInfoContext failed", zap.Error(err))
+
+	self := selfUserID(uh.apiProvider)
+	if uh.apiProvider.IsBotToken() || !slices.Contains(ids, self) {
+		return nil
+	}
+	boot, err := uh.apiProvider.Slack().ClientUserBoot(ctx)
+	if err != nil {
+		uh.logger.Warn("Slack ClientUserBoot failed", zap.Error(err))
+		return nil
+	}
+	return map[string]slack.DNDStatus{self: {
+		Enabled:            boot.DND.DNDEnabled,
+		NextStartTimestamp: int(boot.DND.NextDNDStartTs),
+		NextEndTimestamp:   int(boot.DND.NextDNDEndTs),
+		SnoozeInfo:         slack.SnoozeInfo{SnoozeEnabled: boot.DND.SnoozeEnabled},
+	}}
+}
+
+func (uh *UsersHandler) userPresence(u slack.User, dnd slack.DNDStatus, now time.Time) UserPresence {
+	loc := userLocation(u)
+	format := func(ts int) string {
+		if ts <= 0 {
+			return ""
+		}
+		return time.Unix(int64(ts), 0).In(loc).Format(time.RFC3339)
+	}
+
+	row := UserPresence{
+		UserID:    u.ID,
+		UserName:  u.Name,
+		RealName:  u.RealName,
+		LocalTime: now.In(loc).Format(time.RFC3339),
+		TZ:        u.TZ,
+	}
+
+	// Slack clears expired statuses lazily
+	exp := u.Profile.StatusExpiration
+	if exp == 0 || int64(exp) > now.Unix() {
+		row.StatusText = u.Profile.StatusText
+		row.StatusEmoji = u.Profile.StatusEmoji
+		row.StatusExpiration = format(exp)
+	}
+
+	if dnd.Enabled {
+		row.DNDStart = format(dnd.NextStartTimestamp)
+		row.DNDEnd = format(dnd.NextEndTimestamp)
+		start, end := int64(dnd.NextStartTimestamp), int64(dnd.NextEndTimestamp)
+		row.InDND = start > 0 && start <= now.Unix() && now.Unix() < end
+	}
+	if dnd.SnoozeEnabled {
+		row.SnoozeEnd = format(dnd.SnoozeEndTime)
+		row.InDND = row.InDND || dnd.SnoozeEndTime == 0 || int64(dnd.SnoozeEndTime) > now.Unix()
+	}
+	return row
+}
+
+// userLocation returns the timezone of a user. When the zone database doesn't
+// know it the fixed offset Slack reports is used, UTC when both are missing.
+func userLocation(u slack.User) *time.Location {
+	if u.TZ != "" {
+		if loc, err := time.LoadLocation(u.TZ); err == nil {
+			return loc
+		}
+	}
+	if u.TZOffset != 0 {
+		return time.FixedZone(u.TZLabel, u.TZOffset)
+	}
+	return time.UTC
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// UserProfileInfo is the full profile of one user. Local time and UTC offset
+// are computed from the user's timezone at the time of the call.
+type UserProfileInfo struct {
+	UserID       string         `json:"userID"`
+	UserName     string         `json:"userName"`
+	RealName     string         `json:"realName"`
+	DisplayName  string         `json:"displayName,omitempty"`
+	Email        string         `json:"email,omitempty"`
+	Title        string         `json:"title,omitempty"`
+	Phone        string         `json:"phone,omitempty"`
+	Pronouns     string         `json:"pronouns,omitempty"`
+	Manager      *User          `json:"manager,omitempty"`
+	StatusText   string         `json:"statusText,omitempty"`
+	StatusEmoji  string         `json:"statusEmoji,omitempty"`
+	TZ           string         `json:"tz,omitempty"`
+	TZLabel      string         `json:"tzLabel,omitempty"`
+	UTCOffset    string         `json:"utcOffset"`
+	LocalTime    string         `json:"localTime"`
+	IsBot        bool           `json:"isBot"`
+	IsAdmin      bool           `json:"isAdmin"`
+	IsDeleted    bool           `json:"isDeleted"`
+	CustomFields []ProfileField `json:"customFields,omitempty"`
+}
+
+// ProfileField is a custom profile field defined by the workspace, e.g. team
+// or location. Fields referencing people carry their names in Users.
+type ProfileField struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+	Alt   string `json:"alt,omitempty"`
+	Users []User `json:"users,omitempty"`
+
+	order int
+}
+
+// UsersGetProfileHandler returns the full profile of a user. users.info is
+// required, the profile with custom fields and the workspace field
+// definitions are best effort.
+func (uh *UsersHandler) UsersGetProfileHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	uh.logger.Debug("UsersGetProfileHandler called", zap.Any("params", request.Params))
+
+	if ready, err := uh.apiProvider.IsReady(); !ready {
+		uh.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	userID := selfUserID(uh.apiProvider)
+	if raw := request.GetString("user", ""); strings.TrimSpace(raw) != "" {
+		id, err := resolveUserID(uh.apiProvider, raw)
+		if err != nil {
+			return nil, err
+		}
+		userID = id
+	}
+
+	users, err := uh.apiProvider.Slack().GetUsersInfo(userID)
+	if err != nil {
+		uh.logger.Error("Slack GetUsersInfo failed", zap.String("user", userID), zap.Error(err))
+		return nil, err
+	}
+	if len(*users) == 0 {
+		return nil, fmt.Errorf("user %q not found", userID)
+	}
+	u := (*users)[0]
+
+	profile, err := uh.apiProvider.Slack().GetUserProfileContext(ctx, userID)
+	if err != nil {
+		uh.logger.Warn("Slack GetUserProfileContext failed", zap.String("user", userID), zap.Error(err))
+		profile = &edge.UserProfile{UserProfile: u.Profile}
+	}
+
+	var definitions []slack.TeamProfileField
+	if tp, err := uh.apiProvider.Slack().GetTeamProfileContext(ctx); err != nil {
+		uh.logger.Warn("Slack GetTeamProfileContext failed", zap.Error(err))
+	} else {
+		definitions = tp.Fields
+	}
+
+	info := uh.userProfile(u, profile, definitions, time.Now())
+	jsonBytes, err := json.Marshal(info)
+	if err != nil {
+		uh.logger.Error("Failed to marshal user profile to JSON", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(info, string(jsonBytes)), nil
+}
+
+func (uh *UsersHandler) userProfile(u slack.User, profile *edge.UserProfile, definitions []slack.TeamProfileField, now time.Time) UserProfileInfo {
+	local := now.In(userLocation(u))
+	_, offset := local.Zone()
+	info := UserProfileInfo{
+		UserID:      u.ID,
+		UserName:    u.Name,
+		RealName:    firstNonEmpty(profile.RealName, u.RealName),
+		DisplayName: profile.DisplayName,
+		Email:       profile.Email,
+		Title:       profile.Title,
+		Phone:       profile.Phone,
+		Pronouns:    profile.Pronouns,
+		StatusText:  profile.StatusText,
+		StatusEmoji: profile.StatusEmoji,
+		TZ:          u.TZ,
+		TZLabel:     u.TZLabel,
+		UTCOffset:   formatUTCOffset(offset),
+		LocalTime:   local.Format(time.RFC3339),
+		IsBot:       u.IsBot,
+		IsAdmin:     u.IsAdmin,
+		IsDeleted:   u.Deleted,
+	}
+
+	defs := make(map[string]slack.TeamProfileField, len(definitions))
+	for _, d := range definitions {
+		defs[d.ID] = d
+	}
+	usersMap := uh.apiProvider.ProvideUsersMap().Users
+	for id, f := range profile.FieldsMap() {
+		def, known := defs[id]
+		if f.Value == "" || def.IsHidden {
+			continue
+		}
+		field := ProfileField{ID: id, Label: firstNonEmpty(def.Label, f.Label, id), Value: f.Value, Alt: f.Alt}
+		if def.Type == "user" {
+			for _, ref := range strings.Split(f.Value, ",") {
+				ref = strings.TrimSpace(ref)
+				userName, realName, _ := getUserInfo(ref, usersMap)
+				field.Users = append(field.Users, User{UserID: ref, UserName: userName, RealName: realName})
+			}
+			if info.Manager == nil && len(field.Users) > 0 && isManagerLabel(field.Label) {
+				info.Manager = &field.Users[0]
+			}
+		}
+		// fields the workspace no longer defines go last
+		field.order = def.Ordering
+		if !known {
+			field.order = len(definitions)
+		}
+		info.CustomFields = append(info.CustomFields, field)
+	}
+	sort.Slice(info.CustomFields, func(i, j int) bool {
+		a, b := info.CustomFields[i], info.CustomFields[j]
+		if a.order != b.order {
+			return a.order < b.order
+		}
+		return a.ID < b.ID
+	})
+	return info
+}
+
+// isManagerLabel tells whether a people field names the user's manager, Slack
+// suggests "Manager" or "Reports to" for it.
+func isManagerLabel(label string) bool {
+	label = strings.ToLower(label)
+	return strings.Contains(label, "manager") || strings.Contains(label, "reports to")
+}
+
+// formatUTCOffset formats an offset in seconds as +hh:mm.
+func formatUTCOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -18,9 +18,11 @@ func (t tier) Limiter() *rate.Limiter {
 }
 
 var (
-	// tier1 = tier{t: 1 * time.Minute, b: 2}
+	Tier1      = tier{t: 1 * time.Minute, b: 2}
 	Tier2      = tier{t: 3 * time.Second, b: 3}
 	Tier2boost = tier{t: 300 * time.Millisecond, b: 5}
 	Tier3      = tier{t: 1200 * time.Millisecond, b: 4}
-	// tier4      = tier{t: 60 * time.Millisecond, b: 5}
+	Tier4      = tier{t: 600 * time.Millisecond, b: 5}
+	// TierPost covers chat.postMessage, which Slack limits to about one message per second.
+	TierPost = tier{t: 1 * time.Second, b: 3}
 )
@@ -0,0 +1,149 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// methodTiers maps Slack Web API methods to their documented rate limit tiers,
+// see https://api.slack.com/apis/rate-limits. Methods not listed use DefaultTier.
+var methodTiers = map[string]tier{
+	"auth.test": Tier4,
+
+	"users.list":   Tier2,
+	"users.info":   Tier4,
+	"users/search": Tier2boost,
+
+	"users.getPresence": Tier3,
+	"users.profile.set": Tier3,
+	"users.profile.get": Tier4,
+	"dnd.teamInfo":      Tier3,
+	"dnd.setSnooze":     Tier2,
+	"dnd.endSnooze":     Tier2,
+
+	"usergroups.list":         Tier2,
+	"usergroups.users.list":   Tier2,
+	"usergroups.users.update": Tier2,
+
+	"conversations.list":       Tier2,
+	"conversations.info":       Tier3,
+	"conversations.history":    Tier3,
+	"conversations.replies":    Tier3,
+	"conversations.mark":       Tier3,
+	"conversations.create":     Tier2,
+	"conversations.archive":    Tier2,
+	"conversations.unarchive":  Tier2,
+	"conversations.rename":     Tier2,
+	"conversations.setTopic":   Tier2,
+	"conversations.setPurpose": Tier2,
+	"conversations.members":    Tier4,
+	"conversations.join":       Tier3,
+	"conversations.leave":      Tier3,
+	"conversations.invite":     Tier3,
+	"conversations.kick":       Tier3,
+	"chat.postMessage":         TierPost,
+	"pins.list":                Tier2,
+	"pins.add":                 Tier2,
+	"pins.remove":              Tier2,
+	"bookmarks.list":           Tier3,
+	"bookmarks.add":            Tier2,
+	"bookmarks.remove":         Tier2,
+	"reminders.add":            Tier2,
+	"reminders.list":           Tier2,
+	"reminders.complete":       Tier2,
+	"reminders.delete":         Tier2,
+	"team.info":                Tier3,
+	"team.profile.get":         Tier3,
+	"reactions.add":            Tier3,
+	"reactions.remove":         Tier2,
+	"search.messages":          Tier2,
+
+	"files.info":     Tier4,
+	"files.list":     Tier3,
+	"files.download": Tier4,
+
+	"canvases.create":          Tier2,
+	"canvases.edit":            Tier3,
+	"canvases.sections.lookup": Tier3,
+
+	"slackLists.items.list":   Tier3,
+	"slackLists.items.info":   Tier3,
+	"slackLists.items.create": Tier3,
+	"slackLists.items.update": Tier3,
+	"slackLists.items.delete": Tier3,
+
+	"client.userBoot": Tier2,
+	"client.counts":   Tier2,
+
+	"conversations.genericInfo": Tier3,
+}
+
+// DefaultTier applies to methods without a documented tier in methodTiers.
+var DefaultTier = Tier3
+
+// Registry hands out one limiter per Slack method, so calls to the same
+// method share a budget regardless of which tool made them.
+type Registry struct {
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func NewRegistry(logger *zap.Logger) *Registry {
+	return &Registry{
+		logger:   logger,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// TierFor returns the tier a Slack method is limited by.
+func TierFor(method string) tier {
+	if t, ok := methodTiers[method]; ok {
+		return t
+	}
+	return DefaultTier
+}
+
+// Limiter returns the shared limiter for a Slack method.
+func (r *Registry) Limiter(method string) *rate.Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l, ok := r.limiters[method]
+	if !ok {
+		l = TierFor(method).Limiter()
+		r.limiters[method] = l
+	}
+	return l
+}
+
+// Wait blocks until a call to method is allowed or ctx is done.
+func (r *Registry) Wait(ctx context.Context, method string) error {
+	return r.Limiter(method).Wait(ctx)
+}
+
+// Do waits for the method's limiter and calls fn. Rate limited responses are
+// retried by the retry transport of the HTTP client only, retrying them here
+// as well would multiply the requests sent to a method that is already over
+// its limit. A slack.RateLimitedError returned by fn is handed to the caller.
+func (r *Registry) Do(ctx context.Context, method string, fn func() error) error {
+	if err := r.Wait(ctx, method); err != nil {
+		return err
+	}
+
+	err := fn()
+	var rle *slack.RateLimitedError
+	if errors.As(err, &rle) {
+		r.logger.Warn("Slack API still rate limited after retries",
+			zap.String("method", method),
+			zap.Duration("retry_after", rle.RetryAfter),
+		)
+	}
+	return err
+}
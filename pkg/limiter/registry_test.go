@@ -0,0 +1,92 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/retry"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+func newTestRegistry() *Registry {
+	return NewRegistry(zap.NewNop())
+}
+
+func TestUnitTierFor(t *testing.T) {
+	assert.Equal(t, Tier3, TierFor("conversations.history"))
+	assert.Equal(t, Tier2, TierFor("search.messages"))
+	assert.Equal(t, TierPost, TierFor("chat.postMessage"))
+	assert.Equal(t, DefaultTier, TierFor("unknown.method"))
+}
+
+func TestUnitRegistrySharesLimiterPerMethod(t *testing.T) {
+	r := newTestRegistry()
+	assert.Same(t, r.Limiter("conversations.history"), r.Limiter("conversations.history"))
+	assert.NotSame(t, r.Limiter("conversations.history"), r.Limiter("conversations.replies"))
+}
+
+func TestUnitRegistryDoLeavesRateLimitedToTransport(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	policy := retry.Policy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Minute}
+	httpClient := &http.Client{Transport: retry.NewTransport(http.DefaultTransport, policy, zap.NewNop())}
+	api := slack.New("xoxp-test", slack.OptionHTTPClient(httpClient), slack.OptionAPIURL(srv.URL+"/"))
+
+	r := newTestRegistry()
+	r.limiters["conversations.history"] = rate.NewLimiter(rate.Inf, 0)
+	err := r.Do(context.Background(), "conversations.history", func() error {
+		_, err := api.GetConversationHistoryContext(context.Background(), &slack.GetConversationHistoryParameters{ChannelID: "C1"})
+		return err
+	})
+	var rle *slack.RateLimitedError
+	require.True(t, errors.As(err, &rle), err)
+	// the first attempt and the transport's retries, nothing on top
+	assert.Equal(t, int32(1+policy.MaxRetries), calls.Load())
+}
+
+func TestUnitRegistryDoDoesNotRetryOtherErrors(t *testing.T) {
+	r := newTestRegistry()
+	r.limiters["m"] = rate.NewLimiter(rate.Inf, 0)
+
+	calls := 0
+	err := r.Do(context.Background(), "m", func() error {
+		calls++
+		return errors.New("channel_not_found")
+	})
+	assert.EqualError(t, err, "channel_not_found")
+	assert.Equal(t, 1, calls)
+}
+
+func TestUnitRegistryDoHonoursContext(t *testing.T) {
+	r := newTestRegistry()
+	r.limiters["m"] = rate.NewLimiter(rate.Every(time.Hour), 1)
+	require.NoError(t, r.Do(context.Background(), "m", func() error { return nil }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	start := time.Now()
+	err := r.Do(ctx, "m", func() error {
+		calls++
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Zero(t, calls)
+	assert.Less(t, time.Since(start), time.Second)
+}
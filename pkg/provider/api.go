@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -21,7 +23,6 @@ import (
 	"github.com/rusq/slackdump/v3/auth"
 	"github.com/slack-go/slack"
 	"go.uber.org/zap"
-	"golang.org/x/time/rate"
 )
 
 const usersNotReadyMsg = "users cache is not ready yet, sync process is still running... please wait"
@@ -82,6 +83,26 @@ func getCacheTTL() time.Duration {
 	return defaultCacheTTL
 }
 
+// getAPIURL returns the Slack Web API root from SLACK_MCP_API_URL, or "" to
+// use the workspace URL. It lets the server talk to a local stand-in for Slack.
+func getAPIURL() string {
+	apiURL := strings.TrimSpace(os.Getenv("SLACK_MCP_API_URL"))
+	if apiURL != "" && !strings.HasSuffix(apiURL, "/") {
+		apiURL += "/"
+	}
+	return apiURL
+}
+
+// newListsClient builds the Lists API client, pointed at SLACK_MCP_API_URL
+// when it is set.
+func newListsClient(token string, httpClient *http.Client, limits *limiter.Registry) *lists.Client {
+	opts := []lists.Option{lists.OptionRateLimits(limits)}
+	if apiURL := getAPIURL(); apiURL != "" {
+		opts = append(opts, lists.OptionAPIURL(apiURL))
+	}
+	return lists.NewClient(token, httpClient, opts...)
+}
+
 // getMinRefreshInterval returns the minimum interval between forced refreshes from
 // SLACK_MCP_MIN_REFRESH_INTERVAL env var or default (30s).
 // Supports formats: "30s", "1m", "60" (seconds), "0" (disable rate limiting)
@@ -116,6 +137,13 @@ type UsersCache struct {
 	UsersInv map[string]string     `json:"users_inv"`
 }
 
+// UserGroupsCache holds user groups by ID and by handle, empty without the
+// usergroups:read scope.
+type UserGroupsCache struct {
+	UserGroups    map[string]slack.UserGroup `json:"user_groups"`
+	UserGroupsInv map[string]string          `json:"user_groups_inv"`
+}
+
 type ChannelsCache struct {
 	Channels    map[string]Channel `json:"channels"`
 	ChannelsInv map[string]string  `json:"channels_inv"`
@@ -132,6 +160,13 @@ type Channel struct {
 	IsPrivate   bool     `json:"private"`
 	User        string   `json:"user,omitempty"`    // User ID for IM channels
 	Members     []string `json:"members,omitempty"` // Member IDs for the channel
+	IsMember    bool     `json:"member,omitempty"`  // The authenticated user is in the channel
+	IsArchived  bool     `json:"archived,omitempty"`
+	IsShared    bool     `json:"shared,omitempty"`
+	IsExtShared bool     `json:"extShared,omitempty"`
+	// Teams a Slack Connect or multi-workspace channel is connected to, ours included
+	ConnectedTeamIDs []string `json:"connectedTeamIDs,omitempty"`
+	Created          int64    `json:"created,omitempty"` // Unix time the channel was created
 }
 
 type SlackAPI interface {
@@ -140,6 +175,7 @@ type SlackAPI interface {
 	AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error)
 	GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) ([]slack.User, error)
 	GetUsersInfo(users ...string) (*[]slack.User, error)
+	GetUserGroupsContext(ctx context.Context, options ...slack.GetUserGroupsOption) ([]slack.UserGroup, error)
 	PostMessageContext(ctx context.Context, channel string, options ...slack.MsgOption) (string, string, error)
 	MarkConversationContext(ctx context.Context, channel, ts string) error
 	AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error
@@ -157,6 +193,54 @@ type SlackAPI interface {
 
 	// Used to get channels list from both Slack and Enterprise Grid versions
 	GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error)
+	GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error)
+
+	// Used to manage channels
+	CreateConversationContext(ctx context.Context, params slack.CreateConversationParams) (*slack.Channel, error)
+	ArchiveConversationContext(ctx context.Context, channelID string) error
+	UnArchiveConversationContext(ctx context.Context, channelID string) error
+	RenameConversationContext(ctx context.Context, channelID, channelName string) (*slack.Channel, error)
+	SetTopicOfConversationContext(ctx context.Context, channelID, topic string) (*slack.Channel, error)
+	SetPurposeOfConversationContext(ctx context.Context, channelID, purpose string) (*slack.Channel, error)
+
+	// Used to manage channel membership
+	GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error)
+	JoinConversationContext(ctx context.Context, channelID string) (*slack.Channel, string, []string, error)
+	LeaveConversationContext(ctx context.Context, channelID string) (bool, error)
+	InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error)
+	KickUserFromConversationContext(ctx context.Context, channelID string, user string) error
+
+	// Used to describe channels
+	ListPinsContext(ctx context.Context, channel string) ([]slack.Item, *slack.Paging, error)
+	ListBookmarksContext(ctx context.Context, channelID string) ([]slack.Bookmark, error)
+	GetOtherTeamInfoContext(ctx context.Context, team string) (*slack.TeamInfo, error)
+
+	// Used to manage pins and bookmarks
+	AddPinContext(ctx context.Context, channel string, item slack.ItemRef) error
+	RemovePinContext(ctx context.Context, channel string, item slack.ItemRef) error
+	AddBookmarkContext(ctx context.Context, channelID string, params slack.AddBookmarkParameters) (slack.Bookmark, error)
+	RemoveBookmarkContext(ctx context.Context, channelID, bookmarkID string) error
+
+	// Used to manage reminders
+	AddUserReminderContext(ctx context.Context, userID, text, time string) (*slack.Reminder, error)
+	ListRemindersContext(ctx context.Context) ([]*slack.Reminder, error)
+	CompleteReminderContext(ctx context.Context, reminderID string) error
+	DeleteReminderContext(ctx context.Context, reminderID string) error
+
+	// Used to read and set presence, status and DND
+	GetUserPresenceContext(ctx context.Context, user string) (*slack.UserPresence, error)
+	GetDNDTeamInfoContext(ctx context.Context, users []string) (map[string]slack.DNDStatus, error)
+	SetUserCustomStatusContext(ctx context.Context, statusText, statusEmoji string, statusExpiration int64) error
+	SetSnoozeContext(ctx context.Context, minutes int) (*slack.DNDStatus, error)
+	EndSnoozeContext(ctx context.Context) (*slack.DNDStatus, error)
+
+	// Used to manage user groups
+	GetUserGroupMembersContext(ctx context.Context, userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error)
+	UpdateUserGroupMembersContext(ctx context.Context, userGroup string, members string, options ...slack.UpdateUserGroupMembersOption) (slack.UserGroup, error)
+
+	// Used to read user profiles
+	GetUserProfileContext(ctx context.Context, userID string) (*edge.UserProfile, error)
+	GetTeamProfileContext(ctx context.Context) (*slack.TeamProfile, error)
 
 	// Canvas API methods
 	CreateCanvasContext(ctx context.Context, title string, documentContent slack.DocumentContent) (string, error)
@@ -165,6 +249,8 @@ type SlackAPI interface {
 
 	// Edge API methods
 	ClientUserBoot(ctx context.Context) (*edge.ClientUserBootResponse, error)
+	ClientCounts(ctx context.Context) (edge.ClientCountsResponse, error)
+	ConversationsGenericInfo(ctx context.Context, channelIDs ...string) ([]slack.Channel, error)
 	UsersSearch(ctx context.Context, query string, count int) ([]slack.User, error)
 }
 
@@ -187,7 +273,14 @@ type ApiProvider struct {
 	listsClient *lists.Client
 	logger      *zap.Logger
 
-	rateLimiter        *rate.Limiter
+	// archive is set when the provider serves a Slack export or slackdump
+	// archive instead of a live workspace.
+	archive bool
+
+	// messageCache is set when SLACK_MCP_MESSAGE_CACHE keeps history and
+	// replies in a local message store, it also wraps client.
+	messageCache *cachedClient
+
 	cacheTTL           time.Duration
 	minRefreshInterval time.Duration
 
@@ -198,19 +291,26 @@ type ApiProvider struct {
 	lastForcedUsersRefresh time.Time
 	usersMu                sync.RWMutex // protects usersReady, lastForcedUsersRefresh
 
+	// User groups: listed on first use, nil until then
+	userGroupsSnapshot atomic.Pointer[UserGroupsCache]
+	userGroupsMu       sync.Mutex // serializes loading and updates of the snapshot
+
 	// Channels cache: atomic pointer to immutable snapshot (no copy on read)
 	channelsSnapshot          atomic.Pointer[ChannelsCache]
 	channelsCachePath         string
 	channelsReady             bool
 	lastForcedChannelsRefresh time.Time
-	channelsMu                sync.RWMutex // protects channelsReady, lastForcedChannelsRefresh
+	channelsMu                sync.RWMutex // protects channelsReady, lastForcedChannelsRefresh and snapshot updates
 }
 
 func NewMCPSlackClient(authProvider auth.Provider, logger *zap.Logger) (*MCPSlackClient, error) {
 	httpClient := httptransport.ProvideHTTPClient(authProvider.Cookies(), logger)
 
+	apiURL := getAPIURL()
 	slackOpts := []slack.Option{slack.OptionHTTPClient(httpClient)}
-	if os.Getenv("SLACK_MCP_GOVSLACK") == "true" {
+	if apiURL != "" {
+		slackOpts = append(slackOpts, slack.OptionAPIURL(apiURL))
+	} else if os.Getenv("SLACK_MCP_GOVSLACK") == "true" {
 		slackOpts = append(slackOpts, slack.OptionAPIURL("https://slack-gov.com/api/"))
 	}
 	slackClient := slack.New(authProvider.SlackToken(), slackOpts...)
@@ -230,14 +330,19 @@ func NewMCPSlackClient(authProvider auth.Provider, logger *zap.Logger) (*MCPSlac
 		BotID:        authResp.BotID,
 	}
 
+	edgeOpts := []edge.Option{edge.OptionHTTPClient(httpClient)}
+	if apiURL == "" {
+		apiURL = authResp.URL + "api/"
+	} else {
+		edgeOpts = append(edgeOpts, edge.OptionAPIURL(apiURL))
+	}
+
 	slackClient = slack.New(authProvider.SlackToken(),
 		slack.OptionHTTPClient(httpClient),
-		slack.OptionAPIURL(authResp.URL+"api/"),
+		slack.OptionAPIURL(apiURL),
 	)
 
-	edgeClient, err := edge.NewWithInfo(authResponse, authProvider,
-		edge.OptionHTTPClient(httpClient),
-	)
+	edgeClient, err := edge.NewWithInfo(authResponse, authProvider, edgeOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -295,10 +400,175 @@ func (c *MCPSlackClient) GetUsersInfo(users ...string) (*[]slack.User, error) {
 	return c.slackClient.GetUsersInfo(users...)
 }
 
+func (c *MCPSlackClient) GetUserGroupsContext(ctx context.Context, options ...slack.GetUserGroupsOption) ([]slack.UserGroup, error) {
+	return c.slackClient.GetUserGroupsContext(ctx, options...)
+}
+
+func (c *MCPSlackClient) GetUserGroupMembersContext(ctx context.Context, userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error) {
+	return c.slackClient.GetUserGroupMembersContext(ctx, userGroup, options...)
+}
+
+func (c *MCPSlackClient) UpdateUserGroupMembersContext(ctx context.Context, userGroup string, members string, options ...slack.UpdateUserGroupMembersOption) (slack.UserGroup, error) {
+	return c.slackClient.UpdateUserGroupMembersContext(ctx, userGroup, members, options...)
+}
+
 func (c *MCPSlackClient) MarkConversationContext(ctx context.Context, channel, ts string) error {
 	return c.slackClient.MarkConversationContext(ctx, channel, ts)
 }
 
+func (c *MCPSlackClient) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	return c.slackClient.GetConversationInfoContext(ctx, input)
+}
+
+func (c *MCPSlackClient) CreateConversationContext(ctx context.Context, params slack.CreateConversationParams) (*slack.Channel, error) {
+	return c.slackClient.CreateConversationContext(ctx, params)
+}
+
+func (c *MCPSlackClient) ArchiveConversationContext(ctx context.Context, channelID string) error {
+	return c.slackClient.ArchiveConversationContext(ctx, channelID)
+}
+
+func (c *MCPSlackClient) UnArchiveConversationContext(ctx context.Context, channelID string) error {
+	return c.slackClient.UnArchiveConversationContext(ctx, channelID)
+}
+
+func (c *MCPSlackClient) RenameConversationContext(ctx context.Context, channelID, channelName string) (*slack.Channel, error) {
+	return c.slackClient.RenameConversationContext(ctx, channelID, channelName)
+}
+
+func (c *MCPSlackClient) SetTopicOfConversationContext(ctx context.Context, channelID, topic string) (*slack.Channel, error) {
+	return c.slackClient.SetTopicOfConversationContext(ctx, channelID, topic)
+}
+
+func (c *MCPSlackClient) SetPurposeOfConversationContext(ctx context.Context, channelID, purpose string) (*slack.Channel, error) {
+	return c.slackClient.SetPurposeOfConversationContext(ctx, channelID, purpose)
+}
+
+// GetUsersInConversationContext lists members with conversations.members for
+// OAuth tokens. Browser tokens go through the edge users/list API, which
+// returns every member at once, so pages are cut from that list and the cursor is an offset.
+func (c *MCPSlackClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
+	if c.isOAuth {
+		return c.slackClient.GetUsersInConversationContext(ctx, params)
+	}
+
+	users, err := c.edgeClient.UsersList(ctx, params.ChannelID)
+	if err != nil {
+		return nil, "", err
+	}
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	start := 0
+	if params.Cursor != "" {
+		if start, err = strconv.Atoi(params.Cursor); err != nil || start < 0 || start > len(ids) {
+			return nil, "", fmt.Errorf("invalid cursor %q", params.Cursor)
+		}
+	}
+	end := len(ids)
+	if params.Limit > 0 && start+params.Limit < end {
+		end = start + params.Limit
+	}
+	next := ""
+	if end < len(ids) {
+		next = strconv.Itoa(end)
+	}
+	return ids[start:end], next, nil
+}
+
+func (c *MCPSlackClient) JoinConversationContext(ctx context.Context, channelID string) (*slack.Channel, string, []string, error) {
+	return c.slackClient.JoinConversationContext(ctx, channelID)
+}
+
+func (c *MCPSlackClient) LeaveConversationContext(ctx context.Context, channelID string) (bool, error) {
+	return c.slackClient.LeaveConversationContext(ctx, channelID)
+}
+
+func (c *MCPSlackClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error) {
+	return c.slackClient.InviteUsersToConversationContext(ctx, channelID, users...)
+}
+
+func (c *MCPSlackClient) KickUserFromConversationContext(ctx context.Context, channelID string, user string) error {
+	return c.slackClient.KickUserFromConversationContext(ctx, channelID, user)
+}
+
+func (c *MCPSlackClient) ListPinsContext(ctx context.Context, channel string) ([]slack.Item, *slack.Paging, error) {
+	return c.slackClient.ListPinsContext(ctx, channel)
+}
+
+func (c *MCPSlackClient) ListBookmarksContext(ctx context.Context, channelID string) ([]slack.Bookmark, error) {
+	return c.slackClient.ListBookmarksContext(ctx, channelID)
+}
+
+func (c *MCPSlackClient) AddPinContext(ctx context.Context, channel string, item slack.ItemRef) error {
+	return c.slackClient.AddPinContext(ctx, channel, item)
+}
+
+func (c *MCPSlackClient) RemovePinContext(ctx context.Context, channel string, item slack.ItemRef) error {
+	return c.slackClient.RemovePinContext(ctx, channel, item)
+}
+
+func (c *MCPSlackClient) AddBookmarkContext(ctx context.Context, channelID string, params slack.AddBookmarkParameters) (slack.Bookmark, error) {
+	return c.slackClient.AddBookmarkContext(ctx, channelID, params)
+}
+
+func (c *MCPSlackClient) RemoveBookmarkContext(ctx context.Context, channelID, bookmarkID string) error {
+	return c.slackClient.RemoveBookmarkContext(ctx, channelID, bookmarkID)
+}
+
+func (c *MCPSlackClient) AddUserReminderContext(ctx context.Context, userID, text, time string) (*slack.Reminder, error) {
+	return c.slackClient.AddUserReminderContext(ctx, userID, text, time)
+}
+
+func (c *MCPSlackClient) ListRemindersContext(ctx context.Context) ([]*slack.Reminder, error) {
+	return c.slackClient.ListRemindersContext(ctx)
+}
+
+// CompleteReminderContext goes through the edge client, slack-go has no
+// reminders.complete.
+func (c *MCPSlackClient) CompleteReminderContext(ctx context.Context, reminderID string) error {
+	return c.edgeClient.RemindersComplete(ctx, reminderID)
+}
+
+func (c *MCPSlackClient) DeleteReminderContext(ctx context.Context, reminderID string) error {
+	return c.slackClient.DeleteReminderContext(ctx, reminderID)
+}
+
+func (c *MCPSlackClient) GetUserPresenceContext(ctx context.Context, user string) (*slack.UserPresence, error) {
+	return c.slackClient.GetUserPresenceContext(ctx, user)
+}
+
+func (c *MCPSlackClient) GetDNDTeamInfoContext(ctx context.Context, users []string) (map[string]slack.DNDStatus, error) {
+	return c.slackClient.GetDNDTeamInfoContext(ctx, users)
+}
+
+func (c *MCPSlackClient) SetUserCustomStatusContext(ctx context.Context, statusText, statusEmoji string, statusExpiration int64) error {
+	return c.slackClient.SetUserCustomStatusContext(ctx, statusText, statusEmoji, statusExpiration)
+}
+
+func (c *MCPSlackClient) SetSnoozeContext(ctx context.Context, minutes int) (*slack.DNDStatus, error) {
+	return c.slackClient.SetSnoozeContext(ctx, minutes)
+}
+
+func (c *MCPSlackClient) EndSnoozeContext(ctx context.Context) (*slack.DNDStatus, error) {
+	return c.slackClient.EndSnoozeContext(ctx)
+}
+
+// GetUserProfileContext goes through the edge client, slack-go drops the
+// pronouns of a profile.
+func (c *MCPSlackClient) GetUserProfileContext(ctx context.Context, userID string) (*edge.UserProfile, error) {
+	return c.edgeClient.UsersProfileGet(ctx, userID)
+}
+
+func (c *MCPSlackClient) GetTeamProfileContext(ctx context.Context) (*slack.TeamProfile, error) {
+	return c.slackClient.GetTeamProfileContext(ctx)
+}
+
+func (c *MCPSlackClient) GetOtherTeamInfoContext(ctx context.Context, team string) (*slack.TeamInfo, error) {
+	return c.slackClient.GetOtherTeamInfoContext(ctx, team)
+}
+
 func (c *MCPSlackClient) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
 	// Please see https://github.com/korotovsky/slack-mcp-server/issues/73
 	// It seems that `conversations.list` works with `xoxp` tokens within Enterprise Grid setups
@@ -327,7 +597,7 @@ func (c *MCPSlackClient) GetConversationsContext(ctx context.Context, params *sl
 							IsIM:               ec.IsIM,
 							IsMpIM:             ec.IsMpIM,
 							IsPrivate:          ec.IsPrivate,
-							Created:            slack.JSONTime(ec.Created.Time().UnixMilli()),
+							Created:            slack.JSONTime(ec.Created.Time().Unix()),
 							Unlinked:           ec.Unlinked,
 							NameNormalized:     ec.NameNormalized,
 							IsShared:           ec.IsShared,
@@ -335,6 +605,8 @@ func (c *MCPSlackClient) GetConversationsContext(ctx context.Context, params *sl
 							IsOrgShared:        ec.IsOrgShared,
 							IsPendingExtShared: ec.IsPendingExtShared,
 							NumMembers:         ec.NumMembers,
+							ConnectedTeamIDs:   ec.ConnectedTeamIDs,
+							SharedTeamIDs:      ec.SharedTeamIDs,
 						},
 						Name:       ec.Name,
 						IsArchived: ec.IsArchived,
@@ -408,6 +680,59 @@ func (c *MCPSlackClient) ClientUserBoot(ctx context.Context) (*edge.ClientUserBo
 	return c.edgeClient.ClientUserBoot(ctx)
 }
 
+func (c *MCPSlackClient) ClientCounts(ctx context.Context) (edge.ClientCountsResponse, error) {
+	return c.edgeClient.ClientCounts(ctx)
+}
+
+// ConversationsGenericInfo returns what the web client knows about channels,
+// which for Slack Connect channels includes the teams they are shared with.
+func (c *MCPSlackClient) ConversationsGenericInfo(ctx context.Context, channelIDs ...string) ([]slack.Channel, error) {
+	edgeChannels, err := c.edgeClient.ConversationsGenericInfo(ctx, channelIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make([]slack.Channel, 0, len(edgeChannels))
+	for _, ec := range edgeChannels {
+		channels = append(channels, slack.Channel{
+			IsGeneral: ec.IsGeneral,
+			GroupConversation: slack.GroupConversation{
+				Conversation: slack.Conversation{
+					ID:                 ec.ID,
+					IsIM:               ec.IsIM,
+					IsMpIM:             ec.IsMpIM,
+					IsPrivate:          ec.IsPrivate,
+					Created:            slack.JSONTime(ec.Created),
+					LastRead:           ec.LastRead,
+					NameNormalized:     ec.NameNormalized,
+					IsShared:           ec.IsShared,
+					IsExtShared:        ec.IsExtShared,
+					IsOrgShared:        ec.IsOrgShared,
+					IsPendingExtShared: ec.IsPendingExtShared,
+					NumMembers:         ec.NumMembers,
+					ConnectedTeamIDs:   ec.ConnectedTeamIDs,
+					SharedTeamIDs:      ec.SharedTeamIDs,
+					InternalTeamIDs:    ec.InternalTeamIDs,
+					ContextTeamID:      ec.ContextTeamID,
+					ConversationHostID: ec.ConversationHostID,
+					PreviousNames:      ec.PreviousNames,
+				},
+				Name:       ec.Name,
+				Creator:    ec.Creator,
+				IsArchived: ec.IsArchived,
+				Members:    ec.Members,
+				Topic: slack.Topic{
+					Value: ec.Topic.Value,
+				},
+				Purpose: slack.Purpose{
+					Value: ec.Purpose.Value,
+				},
+			},
+		})
+	}
+	return channels, nil
+}
+
 func (c *MCPSlackClient) UsersSearch(ctx context.Context, query string, count int) ([]slack.User, error) {
 	return c.edgeClient.UsersSearch(ctx, query, count)
 }
@@ -529,14 +854,14 @@ func newWithXOXP(transport string, authProvider auth.ValueAuth, logger *zap.Logg
 	}
 
 	httpClient := httptransport.ProvideHTTPClient(authProvider.Cookies(), logger)
+	limits := limiter.NewRegistry(logger)
 
 	ap := &ApiProvider{
 		transport:   transport,
-		client:      client,
-		listsClient: lists.NewClient(authProvider.SlackToken(), httpClient),
+		client:      newRateLimitedClient(client, limits),
+		listsClient: newListsClient(authProvider.SlackToken(), httpClient, limits),
 		logger:      logger,
 
-		rateLimiter:        limiter.Tier2.Limiter(),
 		cacheTTL:           getCacheTTL(),
 		minRefreshInterval: getMinRefreshInterval(),
 
@@ -552,6 +877,10 @@ func newWithXOXP(transport string, authProvider auth.ValueAuth, logger *zap.Logg
 		Channels:    make(map[string]Channel),
 		ChannelsInv: make(map[string]string),
 	})
+	if mc := newMessageCache(ap.client, client, logger); mc != nil {
+		mc.directory = ap
+		ap.client, ap.messageCache = mc, mc
+	}
 	return ap
 }
 
@@ -589,14 +918,14 @@ func newWithXOXC(transport string, authProvider auth.ValueAuth, logger *zap.Logg
 	}
 
 	httpClient := httptransport.ProvideHTTPClient(authProvider.Cookies(), logger)
+	limits := limiter.NewRegistry(logger)
 
 	ap := &ApiProvider{
 		transport:   transport,
-		client:      client,
-		listsClient: lists.NewClient(authProvider.SlackToken(), httpClient),
+		client:      newRateLimitedClient(client, limits),
+		listsClient: newListsClient(authProvider.SlackToken(), httpClient, limits),
 		logger:      logger,
 
-		rateLimiter:        limiter.Tier2.Limiter(),
 		cacheTTL:           getCacheTTL(),
 		minRefreshInterval: getMinRefreshInterval(),
 
@@ -612,6 +941,10 @@ func newWithXOXC(transport string, authProvider auth.ValueAuth, logger *zap.Logg
 		Channels:    make(map[string]Channel),
 		ChannelsInv: make(map[string]string),
 	})
+	if mc := newMessageCache(ap.client, client, logger); mc != nil {
+		mc.directory = ap
+		ap.client, ap.messageCache = mc, mc
+	}
 	return ap
 }
 
@@ -745,7 +1078,9 @@ func (ap *ApiProvider) refreshUsersInternal(ctx context.Context, force bool) err
 		ap.usersSnapshot.Store(finalSnapshot)
 	}
 
-	if data, err := json.MarshalIndent(list, "", "  "); err != nil {
+	if ap.usersCachePath == "" {
+		// nothing to persist, e.g. when serving an archive
+	} else if data, err := json.MarshalIndent(list, "", "  "); err != nil {
 		ap.logger.Error("Failed to marshal users for cache", zap.Error(err))
 	} else {
 		if err := os.WriteFile(ap.usersCachePath, data, 0644); err != nil {
@@ -759,11 +1094,67 @@ func (ap *ApiProvider) refreshUsersInternal(ctx context.Context, force bool) err
 		}
 	}
 
+	// user groups are listed again on next use
+	ap.userGroupsSnapshot.Store(nil)
 	ap.usersReady = true
 
 	return nil
 }
 
+// ProvideUserGroups returns the user groups of the workspace. They are listed
+// on first use rather than with the users, so a start from the users cache
+// file makes no call for them. Without access to user groups an empty set is
+// kept, other failures are retried on the next call.
+func (ap *ApiProvider) ProvideUserGroups(ctx context.Context) *UserGroupsCache {
+	if groups := ap.userGroupsSnapshot.Load(); groups != nil {
+		return groups
+	}
+
+	ap.userGroupsMu.Lock()
+	defer ap.userGroupsMu.Unlock()
+
+	if groups := ap.userGroupsSnapshot.Load(); groups != nil {
+		return groups
+	}
+	groups := &UserGroupsCache{
+		UserGroups:    make(map[string]slack.UserGroup),
+		UserGroupsInv: make(map[string]string),
+	}
+	list, err := ap.client.GetUserGroupsContext(ctx,
+		slack.GetUserGroupsOptionIncludeUsers(true),
+		slack.GetUserGroupsOptionIncludeCount(true),
+	)
+	if err != nil {
+		ap.logger.Warn("Failed to fetch user groups, usergroups:read scope may be missing", zap.Error(err))
+		if !isUserGroupsUnavailable(err) {
+			// e.g. rate limited or cancelled, try again on the next call
+			return groups
+		}
+	} else {
+		for _, g := range list {
+			groups.UserGroups[g.ID] = g
+			groups.UserGroupsInv[g.Handle] = g.ID
+		}
+		ap.logger.Info("Loaded user groups", zap.Int("count", len(list)))
+	}
+	ap.userGroupsSnapshot.Store(groups)
+	return groups
+}
+
+// isUserGroupsUnavailable reports whether err means the token can never list
+// user groups, so there is no point in asking again.
+func isUserGroupsUnavailable(err error) bool {
+	var slackErr slack.SlackErrorResponse
+	if !errors.As(err, &slackErr) {
+		return false
+	}
+	switch slackErr.Err {
+	case "missing_scope", "not_allowed_token_type", "paid_teams_only":
+		return true
+	}
+	return false
+}
+
 func (ap *ApiProvider) RefreshChannels(ctx context.Context) error {
 	return ap.refreshChannelsInternal(ctx, false)
 }
@@ -828,7 +1219,11 @@ func (ap *ApiProvider) refreshChannelsInternal(ctx context.Context, force bool)
 						Channels:    make(map[string]Channel, len(cachedChannels)),
 						ChannelsInv: make(map[string]string, len(cachedChannels)),
 					}
+					includeArchived := includeArchivedChannels()
 					for _, c := range cachedChannels {
+						if c.IsArchived && !includeArchived {
+							continue
+						}
 						// For IM channels, re-generate the name and purpose using current users cache
 						if c.IsIM {
 							// Re-map the channel to get updated user name if available
@@ -838,6 +1233,12 @@ func (ap *ApiProvider) refreshChannelsInternal(ctx context.Context, force bool)
 								c.IsIM, c.IsMpIM, c.IsPrivate,
 								usersMap,
 							)
+							remappedChannel.IsMember = c.IsMember
+							remappedChannel.IsArchived = c.IsArchived
+							remappedChannel.IsShared = c.IsShared
+							remappedChannel.IsExtShared = c.IsExtShared
+							remappedChannel.ConnectedTeamIDs = c.ConnectedTeamIDs
+							remappedChannel.Created = c.Created
 							newSnapshot.Channels[c.ID] = remappedChannel
 							newSnapshot.ChannelsInv[remappedChannel.Name] = c.ID
 						} else {
@@ -859,7 +1260,9 @@ func (ap *ApiProvider) refreshChannelsInternal(ctx context.Context, force bool)
 	// Fetch fresh data from Slack API
 	channels := ap.GetChannels(ctx, AllChanTypes)
 
-	if data, err := json.MarshalIndent(channels, "", "  "); err != nil {
+	if ap.channelsCachePath == "" {
+		// nothing to persist, e.g. when serving an archive
+	} else if data, err := json.MarshalIndent(channels, "", "  "); err != nil {
 		ap.logger.Error("Failed to marshal channels for cache", zap.Error(err))
 	} else {
 		if err := os.WriteFile(ap.channelsCachePath, data, 0644); err != nil {
@@ -918,7 +1321,7 @@ func (ap *ApiProvider) GetChannelsType(ctx context.Context, channelType string)
 	params := &slack.GetConversationsParameters{
 		Types:           []string{channelType},
 		Limit:           999,
-		ExcludeArchived: true,
+		ExcludeArchived: !includeArchivedChannels(),
 	}
 
 	var (
@@ -930,11 +1333,6 @@ func (ap *ApiProvider) GetChannelsType(ctx context.Context, channelType string)
 	)
 
 	for {
-		if err := ap.rateLimiter.Wait(ctx); err != nil {
-			ap.logger.Error("Rate limiter wait failed", zap.Error(err))
-			return nil
-		}
-
 		channels, nextcur, err = ap.client.GetConversationsContext(ctx, params)
 		ap.logger.Debug("Fetched channels for ",
 			zap.String("channelType", channelType),
@@ -960,7 +1358,7 @@ func (ap *ApiProvider) GetChannelsType(ctx context.Context, channelType string)
 				channel.IsPrivate,
 				ap.ProvideUsersMap().Users,
 			)
-			chans = append(chans, ch)
+			chans = append(chans, withChannelDetails(ch, channel))
 		}
 
 		if nextcur == "" {
@@ -1026,6 +1424,103 @@ func (ap *ApiProvider) ProvideChannelsMaps() *ChannelsCache {
 	return ap.channelsSnapshot.Load()
 }
 
+// UpdateUserGroup puts a user group whose members just changed into the user
+// groups cache. Until the groups are listed there is nothing to update.
+func (ap *ApiProvider) UpdateUserGroup(group slack.UserGroup) {
+	ap.userGroupsMu.Lock()
+	defer ap.userGroupsMu.Unlock()
+
+	current := ap.userGroupsSnapshot.Load()
+	if current == nil {
+		return
+	}
+	next := &UserGroupsCache{
+		UserGroups:    make(map[string]slack.UserGroup, len(current.UserGroups)+1),
+		UserGroupsInv: make(map[string]string, len(current.UserGroupsInv)+1),
+	}
+	for k, v := range current.UserGroups {
+		next.UserGroups[k] = v
+	}
+	for k, v := range current.UserGroupsInv {
+		next.UserGroupsInv[k] = v
+	}
+	if old, ok := next.UserGroups[group.ID]; ok {
+		delete(next.UserGroupsInv, old.Handle)
+	}
+	next.UserGroups[group.ID] = group
+	next.UserGroupsInv[group.Handle] = group.ID
+	ap.userGroupsSnapshot.Store(next)
+}
+
+// UpdateChannel puts a channel that was just created or changed into the
+// channels cache, so tools can use it before the next refresh. Member details
+// the response left out are kept from the cached channel.
+func (ap *ApiProvider) UpdateChannel(channel *slack.Channel) {
+	ap.updateChannelsSnapshot(func(snapshot *ChannelsCache) {
+		c := mapChannel(
+			channel.ID,
+			channel.Name,
+			channel.NameNormalized,
+			channel.Topic.Value,
+			channel.Purpose.Value,
+			channel.User,
+			channel.Members,
+			channel.NumMembers,
+			channel.IsIM,
+			channel.IsMpIM,
+			channel.IsPrivate,
+			ap.ProvideUsersMap().Users,
+		)
+		c = withChannelDetails(c, *channel)
+		if old, ok := snapshot.Channels[c.ID]; ok {
+			delete(snapshot.ChannelsInv, old.Name)
+			if c.MemberCount == 0 {
+				c.MemberCount = old.MemberCount
+			}
+			if c.Members == nil && len(old.Members) == c.MemberCount {
+				c.Members = old.Members
+			}
+			if c.Created == 0 {
+				c.Created = old.Created
+			}
+		}
+		snapshot.Channels[c.ID] = c
+		snapshot.ChannelsInv[c.Name] = c.ID
+	})
+}
+
+// RemoveChannel drops an archived channel from the channels cache, which only
+// lists active channels.
+func (ap *ApiProvider) RemoveChannel(id string) {
+	ap.updateChannelsSnapshot(func(snapshot *ChannelsCache) {
+		if old, ok := snapshot.Channels[id]; ok {
+			delete(snapshot.ChannelsInv, old.Name)
+			delete(snapshot.Channels, id)
+		}
+	})
+}
+
+// updateChannelsSnapshot applies update to a copy of the channels snapshot and
+// stores it, readers keep the snapshot they loaded.
+func (ap *ApiProvider) updateChannelsSnapshot(update func(snapshot *ChannelsCache)) {
+	ap.channelsMu.Lock()
+	defer ap.channelsMu.Unlock()
+
+	current := ap.channelsSnapshot.Load()
+	next := &ChannelsCache{
+		Channels:    make(map[string]Channel, len(current.Channels)+1),
+		ChannelsInv: make(map[string]string, len(current.ChannelsInv)+1),
+	}
+	for k, v := range current.Channels {
+		next.Channels[k] = v
+	}
+	for k, v := range current.ChannelsInv {
+		next.ChannelsInv[k] = v
+	}
+	update(next)
+	ap.channelsSnapshot.Store(next)
+}
+
 func (ap *ApiProvider) IsReady() (bool, error) {
 	if !ap.usersReady {
 		return false, ErrUsersNotReady
@@ -1049,13 +1544,126 @@ func (ap *ApiProvider) Lists() *lists.Client {
 }
 
 func (ap *ApiProvider) IsBotToken() bool {
-	client, ok := ap.client.(*MCPSlackClient)
-	return ok && client != nil && client.IsBotToken()
+	client, ok := ap.mcpClient()
+	return ok && client.IsBotToken()
+}
+
+// AuthResponse returns the identity the provider is authenticated as.
+func (ap *ApiProvider) AuthResponse() (*slack.AuthTestResponse, error) {
+	if client, ok := ap.mcpClient(); ok && client.AuthResponse() != nil {
+		return client.AuthResponse(), nil
+	}
+	return ap.client.AuthTest()
+}
+
+// Permalink returns the link to a message, threadTs is set for replies. It is
+// empty when the workspace URL is unknown.
+func (ap *ApiProvider) Permalink(channel, ts, threadTs string) string {
+	ar, err := ap.AuthResponse()
+	if err != nil || ar.URL == "" {
+		return ""
+	}
+	return permalink(ar.URL, channel, ts, threadTs)
+}
+
+func permalink(workspaceURL, channel, ts, threadTs string) string {
+	link := fmt.Sprintf("%sarchives/%s/p%s", workspaceURL, channel, strings.ReplaceAll(ts, ".", ""))
+	if threadTs != "" && threadTs != ts {
+		link += fmt.Sprintf("?thread_ts=%s&cid=%s", threadTs, channel)
+	}
+	return link
+}
+
+// HasLocalSearch reports whether searches are answered from the message cache
+// because the token can't call search.messages.
+func (ap *ApiProvider) HasLocalSearch() bool {
+	return ap.messageCache != nil && ap.messageCache.localSearch
+}
+
+// includeArchivedChannels checks if SLACK_MCP_CHANNELS_INCLUDE_ARCHIVED asks
+// for archived channels in the channels cache.
+func includeArchivedChannels() bool {
+	v := strings.ToLower(os.Getenv("SLACK_MCP_CHANNELS_INCLUDE_ARCHIVED"))
+	return v == "true" || v == "1" || v == "yes"
+}
+
+// IncludesArchivedChannels reports whether archived channels are kept in the
+// channels cache, they are left out by default.
+func (ap *ApiProvider) IncludesArchivedChannels() bool {
+	return includeArchivedChannels()
+}
+
+// IsArchive reports whether the provider serves an archive from disk, which is
+// read-only.
+func (ap *ApiProvider) IsArchive() bool {
+	return ap.archive
 }
 
 func (ap *ApiProvider) IsOAuth() bool {
-	client, ok := ap.client.(*MCPSlackClient)
-	return ok && client != nil && client.IsOAuth()
+	client, ok := ap.mcpClient()
+	return ok && client.IsOAuth()
+}
+
+// mcpClient returns the underlying MCPSlackClient, looking through the message
+// cache and rate limiting wrappers.
+func (ap *ApiProvider) mcpClient() (*MCPSlackClient, bool) {
+	client := ap.client
+	if mc, ok := client.(*cachedClient); ok {
+		client = mc.SlackAPI
+	}
+	if rl, ok := client.(*rateLimitedClient); ok {
+		client = rl.next
+	}
+	c, ok := client.(*MCPSlackClient)
+	return c, ok && c != nil
+}
+
+// SyncMessages keeps the channels listed in SLACK_MCP_MESSAGE_CACHE_SYNC up to
+// date in the message cache until ctx is done, and returns right away when the
+// cache is disabled or no channel is listed. Names like #general are resolved
+// from the channels cache, so call it once channels are loaded. * syncs every
+// cached channel, which is the default with local search.
+func (ap *ApiProvider) SyncMessages(ctx context.Context) {
+	if ap.messageCache == nil {
+		return
+	}
+
+	cms := ap.ProvideChannelsMaps()
+	refs, ok := os.LookupEnv("SLACK_MCP_MESSAGE_CACHE_SYNC")
+	if !ok && ap.messageCache.localSearch {
+		refs = "*"
+	}
+	var channels []string
+	for _, ref := range strings.Split(refs, ",") {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		if ref == "*" {
+			for id := range cms.Channels {
+				channels = append(channels, id)
+			}
+			continue
+		}
+		if strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "@") {
+			id, ok := cms.ChannelsInv[ref]
+			if !ok {
+				ap.logger.Warn("Channel to sync not found, skipping", zap.String("channel", ref))
+				continue
+			}
+			ref = id
+		}
+		channels = append(channels, ref)
+	}
+	if len(channels) == 0 {
+		return
+	}
+
+	ap.logger.Info("Syncing channel messages in the background",
+		zap.String("context", "console"),
+		zap.Strings("channels", channels),
+	)
+	ap.messageCache.syncChannels(ctx, channels)
 }
 
 // SearchUsers searches for users by name, email, or display name.
@@ -1103,6 +1711,22 @@ func (ap *ApiProvider) searchUsersInCache(query string, limit int) ([]slack.User
 	return results, nil
 }
 
+// withChannelDetails adds what channels_list filters and sorts on to a
+// channel built by mapChannel. Conversations only list IMs and group DMs the
+// user is in, so they are always member of them.
+func withChannelDetails(c Channel, channel slack.Channel) Channel {
+	c.IsMember = channel.IsMember || channel.IsIM || channel.IsMpIM
+	c.IsArchived = channel.IsArchived
+	c.IsShared = channel.IsShared || channel.IsExtShared || channel.IsOrgShared
+	c.IsExtShared = channel.IsExtShared
+	c.ConnectedTeamIDs = channel.ConnectedTeamIDs
+	if len(c.ConnectedTeamIDs) == 0 {
+		c.ConnectedTeamIDs = channel.SharedTeamIDs
+	}
+	c.Created = int64(channel.Created)
+	return c
+}
+
 func mapChannel(
 	id, name, nameNormalized, topic, purpose, user string,
 	members []string,
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/archive"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// NewArchive returns a provider backed by a Slack export or slackdump archive
+// at path instead of a live workspace. No token is needed, the users and
+// channels caches are built from the archive and never written to disk, and
+// every write fails with archive.ErrReadOnly.
+func NewArchive(transport, path string, logger *zap.Logger) (*ApiProvider, error) {
+	client, err := archive.Open(context.Background(), path, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	ap := &ApiProvider{
+		transport: transport,
+		client:    client,
+		logger:    logger,
+		archive:   true,
+	}
+	ap.usersSnapshot.Store(&UsersCache{
+		Users:    make(map[string]slack.User),
+		UsersInv: make(map[string]string),
+	})
+	ap.channelsSnapshot.Store(&ChannelsCache{
+		Channels:    make(map[string]Channel),
+		ChannelsInv: make(map[string]string),
+	})
+	return ap, nil
+}
@@ -0,0 +1,728 @@
+// Package archive serves the part of the Slack API used by the MCP tools from
+// a Slack export (ZIP or directory) or a slackdump archive, so old workspaces
+// can be browsed without a token.
+//
+// The archive is read once when it is opened and kept in memory; it is never
+// written to.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	rslack "github.com/rusq/slack"
+	"github.com/rusq/slackdump/v3/source"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrReadOnly is returned by every method that would change the workspace.
+	ErrReadOnly = errors.New("archive is read-only")
+	// ErrNotSupported is returned for data an archive does not carry.
+	ErrNotSupported = errors.New("not available in an archive")
+)
+
+// fallbackURL is reported by AuthTest when the archive does not record the
+// workspace URL, it only has to look like a Slack workspace URL.
+const fallbackURL = "https://archive.slack.com/"
+
+// fileURLPrefix is used for files that were archived without a URL.
+const fileURLPrefix = "archive://files/"
+
+// Client implements the Slack API surface of the provider on top of an archive.
+type Client struct {
+	src    source.SourceResumeCloser
+	logger *zap.Logger
+
+	auth     *slack.AuthTestResponse
+	users    []slack.User
+	channels []slack.Channel
+	byID     map[string]*slack.Channel
+	// messages holds every message of a channel, thread replies included,
+	// oldest first.
+	messages map[string][]slack.Message
+	files    map[string]archivedFile
+	fileURLs map[string]string
+}
+
+type archivedFile struct {
+	file    slack.File
+	channel *rslack.Channel
+}
+
+// Open loads the archive at path: a Slack export ZIP or directory, or any
+// archive format slackdump can read.
+func Open(ctx context.Context, path string, logger *zap.Logger) (*Client, error) {
+	src, err := source.Load(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive %s: %w", path, err)
+	}
+
+	c := &Client{
+		src:      src,
+		logger:   logger,
+		byID:     make(map[string]*slack.Channel),
+		messages: make(map[string][]slack.Message),
+		files:    make(map[string]archivedFile),
+		fileURLs: make(map[string]string),
+	}
+	if err := c.load(ctx, path); err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	logger.Info("Loaded Slack archive",
+		zap.String("context", "console"),
+		zap.String("path", path),
+		zap.String("type", src.Type().String()),
+		zap.Int("users", len(c.users)),
+		zap.Int("channels", len(c.channels)),
+		zap.Int("files", len(c.files)),
+	)
+	return c, nil
+}
+
+// Close releases the archive.
+func (c *Client) Close() error {
+	return c.src.Close()
+}
+
+func (c *Client) load(ctx context.Context, path string) error {
+	rusers, err := c.src.Users(ctx)
+	if err != nil && !errors.Is(err, source.ErrNotFound) {
+		return fmt.Errorf("failed to read users: %w", err)
+	}
+	if err := convert(rusers, &c.users); err != nil {
+		return err
+	}
+
+	rchannels, err := c.src.Channels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read channels: %w", err)
+	}
+	if err := convert(rchannels, &c.channels); err != nil {
+		return err
+	}
+	for i := range c.channels {
+		c.byID[c.channels[i].ID] = &c.channels[i]
+	}
+
+	for i := range rchannels {
+		if err := c.loadChannel(ctx, &rchannels[i]); err != nil {
+			return fmt.Errorf("failed to read messages of %s: %w", rchannels[i].ID, err)
+		}
+	}
+
+	c.auth = &slack.AuthTestResponse{
+		URL:  fallbackURL,
+		Team: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+	}
+	if info, err := c.src.WorkspaceInfo(ctx); err == nil && info != nil {
+		if err := convert(info, c.auth); err != nil {
+			return err
+		}
+		if c.auth.URL == "" {
+			c.auth.URL = fallbackURL
+		}
+	}
+	return nil
+}
+
+func (c *Client) loadChannel(ctx context.Context, ch *rslack.Channel) error {
+	it, err := c.src.AllMessages(ctx, ch.ID)
+	if errors.Is(err, source.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var raw []rslack.Message
+	seen := make(map[string]bool)
+	add := func(m rslack.Message) {
+		if !seen[m.Timestamp] {
+			seen[m.Timestamp] = true
+			raw = append(raw, m)
+		}
+	}
+	var threads []string
+	for m, err := range it {
+		if err != nil {
+			return err
+		}
+		add(m)
+		if m.ThreadTimestamp == m.Timestamp && (m.ReplyCount > 0 || len(m.Replies) > 0) {
+			threads = append(threads, m.ThreadTimestamp)
+		}
+	}
+	for _, ts := range threads {
+		it, err := c.src.AllThreadMessages(ctx, ch.ID, ts)
+		if errors.Is(err, source.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for m, err := range it {
+			if err != nil {
+				return err
+			}
+			add(m)
+		}
+	}
+
+	var msgs []slack.Message
+	if err := convert(raw, &msgs); err != nil {
+		return err
+	}
+	sort.SliceStable(msgs, func(i, j int) bool { return tsLess(msgs[i].Timestamp, msgs[j].Timestamp) })
+	c.messages[ch.ID] = msgs
+
+	for _, m := range raw {
+		for _, rf := range m.Files {
+			var f slack.File
+			if err := convert(rf, &f); err != nil {
+				return err
+			}
+			if f.ID == "" {
+				continue
+			}
+			if f.URLPrivate == "" && f.URLPrivateDownload == "" {
+				f.URLPrivate = fileURLPrefix + f.ID
+			}
+			c.files[f.ID] = archivedFile{file: f, channel: ch}
+			for _, u := range []string{f.URLPrivate, f.URLPrivateDownload} {
+				if u != "" {
+					c.fileURLs[u] = f.ID
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// convert copies a value between the slackdump and slack-go flavours of the
+// Slack types, which share their JSON representation.
+func convert(from, to any) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+func (c *Client) AuthTest() (*slack.AuthTestResponse, error) {
+	return c.AuthTestContext(context.Background())
+}
+
+func (c *Client) AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error) {
+	auth := *c.auth
+	return &auth, nil
+}
+
+func (c *Client) GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) ([]slack.User, error) {
+	return append([]slack.User(nil), c.users...), nil
+}
+
+// GetUserGroupsContext returns no groups, exports don't include them.
+func (c *Client) GetUserGroupsContext(ctx context.Context, options ...slack.GetUserGroupsOption) ([]slack.UserGroup, error) {
+	return []slack.UserGroup{}, nil
+}
+
+func (c *Client) GetUserGroupMembersContext(ctx context.Context, userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error) {
+	return nil, fmt.Errorf("usergroups: %w", ErrNotSupported)
+}
+
+func (c *Client) UpdateUserGroupMembersContext(ctx context.Context, userGroup string, members string, options ...slack.UpdateUserGroupMembersOption) (slack.UserGroup, error) {
+	return slack.UserGroup{}, ErrReadOnly
+}
+
+// GetUsersInfo accepts user IDs either as separate arguments or comma separated.
+func (c *Client) GetUsersInfo(users ...string) (*[]slack.User, error) {
+	want := make(map[string]bool)
+	for _, u := range users {
+		for _, id := range strings.Split(u, ",") {
+			want[strings.TrimSpace(id)] = true
+		}
+	}
+	res := []slack.User{}
+	for _, u := range c.users {
+		if want[u.ID] {
+			res = append(res, u)
+		}
+	}
+	if len(res) == 0 {
+		return nil, slack.SlackErrorResponse{Err: "user_not_found"}
+	}
+	return &res, nil
+}
+
+func (c *Client) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
+	var res []slack.Channel
+	for _, ch := range c.channels {
+		if params.ExcludeArchived && ch.IsArchived {
+			continue
+		}
+		if len(params.Types) > 0 && !hasType(params.Types, channelType(&ch)) {
+			continue
+		}
+		res = append(res, ch)
+	}
+	return res, "", nil
+}
+
+func (c *Client) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	ch, ok := c.byID[input.ChannelID]
+	if !ok {
+		return nil, slack.SlackErrorResponse{Err: "channel_not_found"}
+	}
+	res := *ch
+	return &res, nil
+}
+
+// GetUsersInConversationContext returns the members the archive recorded for
+// the channel, all at once.
+func (c *Client) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
+	ch, ok := c.byID[params.ChannelID]
+	if !ok {
+		return nil, "", slack.SlackErrorResponse{Err: "channel_not_found"}
+	}
+	return append([]string(nil), ch.Members...), "", nil
+}
+
+func channelType(ch *slack.Channel) string {
+	switch {
+	case ch.IsIM:
+		return "im"
+	case ch.IsMpIM:
+		return "mpim"
+	case ch.IsPrivate || ch.IsGroup:
+		return "private_channel"
+	default:
+		return "public_channel"
+	}
+}
+
+func hasType(types []string, t string) bool {
+	for _, want := range types {
+		for _, w := range strings.Split(want, ",") {
+			if strings.TrimSpace(w) == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetConversationHistoryContext returns top level messages and thread parents,
+// newest first, like conversations.history.
+func (c *Client) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	if _, ok := c.byID[params.ChannelID]; !ok {
+		return nil, slack.SlackErrorResponse{Err: "channel_not_found"}
+	}
+
+	all := c.messages[params.ChannelID]
+	var msgs []slack.Message
+	for i := len(all) - 1; i >= 0; i-- {
+		m := all[i]
+		if m.ThreadTimestamp != "" && m.ThreadTimestamp != m.Timestamp {
+			continue
+		}
+		if inRange(m.Timestamp, params.Oldest, params.Latest, params.Inclusive) {
+			msgs = append(msgs, m)
+		}
+	}
+
+	page, next, err := paginate(msgs, params.Cursor, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	res := &slack.GetConversationHistoryResponse{
+		SlackResponse: slack.SlackResponse{Ok: true},
+		HasMore:       next != "",
+		Messages:      page,
+	}
+	res.ResponseMetaData.NextCursor = next
+	return res, nil
+}
+
+// GetConversationRepliesContext returns the thread parent followed by its
+// replies, oldest first, like conversations.replies.
+func (c *Client) GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error) {
+	if _, ok := c.byID[params.ChannelID]; !ok {
+		return nil, false, "", slack.SlackErrorResponse{Err: "channel_not_found"}
+	}
+
+	var msgs []slack.Message
+	for _, m := range c.messages[params.ChannelID] {
+		if m.Timestamp != params.Timestamp && m.ThreadTimestamp != params.Timestamp {
+			continue
+		}
+		if inRange(m.Timestamp, params.Oldest, params.Latest, params.Inclusive) {
+			msgs = append(msgs, m)
+		}
+	}
+	if len(msgs) == 0 {
+		return nil, false, "", slack.SlackErrorResponse{Err: "thread_not_found"}
+	}
+
+	page, next, err := paginate(msgs, params.Cursor, params.Limit)
+	if err != nil {
+		return nil, false, "", err
+	}
+	return page, next != "", next, nil
+}
+
+func (c *Client) GetFileInfoContext(ctx context.Context, fileID string, count, page int) (*slack.File, []slack.Comment, *slack.Paging, error) {
+	af, ok := c.files[fileID]
+	if !ok {
+		return nil, nil, nil, slack.SlackErrorResponse{Err: "file_not_found"}
+	}
+	f := af.file
+	return &f, nil, &slack.Paging{}, nil
+}
+
+// GetFileContext copies a file stored in the archive. Slack exports only
+// reference files by URL, those are reported as missing.
+func (c *Client) GetFileContext(ctx context.Context, downloadURL string, writer io.Writer) error {
+	id, ok := c.fileURLs[downloadURL]
+	if !ok {
+		return fmt.Errorf("%s: %w", downloadURL, fs.ErrNotExist)
+	}
+	af := c.files[id]
+
+	storage := c.src.Files()
+	pth, err := storage.File(id, af.file.Name)
+	if err != nil {
+		var ch rslack.Channel
+		if af.channel != nil {
+			ch = *af.channel
+		}
+		var rf rslack.File
+		if err := convert(af.file, &rf); err != nil {
+			return err
+		}
+		pth = storage.FilePath(&ch, &rf)
+	}
+	if pth == "" {
+		return fmt.Errorf("file %s was not downloaded into the archive: %w", id, fs.ErrNotExist)
+	}
+
+	f, err := storage.FS().Open(pth)
+	if err != nil {
+		return fmt.Errorf("file %s was not downloaded into the archive: %w", id, err)
+	}
+	defer f.Close()
+	_, err = io.Copy(writer, f)
+	return err
+}
+
+// GetFilesContext lists archived files. Types understands "all", "canvases"
+// and plain file types such as "pdf".
+func (c *Client) GetFilesContext(ctx context.Context, params slack.GetFilesParameters) ([]slack.File, *slack.Paging, error) {
+	var res []slack.File
+	for _, af := range c.files {
+		f := af.file
+		if params.Channel != "" && (af.channel == nil || af.channel.ID != params.Channel) {
+			continue
+		}
+		if params.User != "" && f.User != params.User {
+			continue
+		}
+		if !fileHasType(&f, params.Types) {
+			continue
+		}
+		res = append(res, f)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Created != res[j].Created {
+			return res[i].Created > res[j].Created
+		}
+		return res[i].ID < res[j].ID
+	})
+
+	count := params.Count
+	if count <= 0 {
+		count = 100
+	}
+	page := params.Page
+	if page <= 0 {
+		page = 1
+	}
+	paging := &slack.Paging{Count: count, Total: len(res), Page: page, Pages: (len(res) + count - 1) / count}
+	start := min((page-1)*count, len(res))
+	end := min(start+count, len(res))
+	return res[start:end], paging, nil
+}
+
+func fileHasType(f *slack.File, types string) bool {
+	if types == "" {
+		return true
+	}
+	for _, t := range strings.Split(types, ",") {
+		switch t = strings.TrimSpace(t); t {
+		case "all":
+			return true
+		case "canvases":
+			if f.Filetype == "quip" || f.Filetype == "canvas" {
+				return true
+			}
+		default:
+			if f.Filetype == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *Client) LookupCanvasSectionsContext(ctx context.Context, params slack.LookupCanvasSectionsParams) ([]slack.CanvasSection, error) {
+	return nil, fmt.Errorf("canvas sections: %w", ErrNotSupported)
+}
+
+// ClientUserBoot has nothing to report, archives carry no Slack Connect state.
+func (c *Client) ClientUserBoot(ctx context.Context) (*edge.ClientUserBootResponse, error) {
+	return &edge.ClientUserBootResponse{}, nil
+}
+
+// ClientCounts reports nothing unread, archives carry no read state.
+func (c *Client) ClientCounts(ctx context.Context) (edge.ClientCountsResponse, error) {
+	return edge.ClientCountsResponse{}, nil
+}
+
+// ConversationsGenericInfo returns the recorded channels, archives don't know
+// more about them than conversations.info.
+func (c *Client) ConversationsGenericInfo(ctx context.Context, channelIDs ...string) ([]slack.Channel, error) {
+	var res []slack.Channel
+	for _, id := range channelIDs {
+		if ch, ok := c.byID[id]; ok {
+			res = append(res, *ch)
+		}
+	}
+	return res, nil
+}
+
+func (c *Client) ListPinsContext(ctx context.Context, channel string) ([]slack.Item, *slack.Paging, error) {
+	return nil, nil, fmt.Errorf("pins: %w", ErrNotSupported)
+}
+
+func (c *Client) ListBookmarksContext(ctx context.Context, channelID string) ([]slack.Bookmark, error) {
+	return nil, fmt.Errorf("bookmarks: %w", ErrNotSupported)
+}
+
+func (c *Client) GetOtherTeamInfoContext(ctx context.Context, team string) (*slack.TeamInfo, error) {
+	return nil, fmt.Errorf("team info: %w", ErrNotSupported)
+}
+
+// UsersSearch matches query against user names, display names and emails.
+func (c *Client) UsersSearch(ctx context.Context, query string, count int) ([]slack.User, error) {
+	q := strings.ToLower(query)
+	var res []slack.User
+	for _, u := range c.users {
+		if u.Deleted {
+			continue
+		}
+		for _, s := range []string{u.Name, u.RealName, u.Profile.DisplayName, u.Profile.Email} {
+			if strings.Contains(strings.ToLower(s), q) {
+				res = append(res, u)
+				break
+			}
+		}
+		if count > 0 && len(res) >= count {
+			break
+		}
+	}
+	return res, nil
+}
+
+func (c *Client) PostMessageContext(ctx context.Context, channel string, options ...slack.MsgOption) (string, string, error) {
+	return "", "", ErrReadOnly
+}
+
+func (c *Client) MarkConversationContext(ctx context.Context, channel, ts string) error {
+	return ErrReadOnly
+}
+
+func (c *Client) CreateConversationContext(ctx context.Context, params slack.CreateConversationParams) (*slack.Channel, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *Client) ArchiveConversationContext(ctx context.Context, channelID string) error {
+	return ErrReadOnly
+}
+
+func (c *Client) UnArchiveConversationContext(ctx context.Context, channelID string) error {
+	return ErrReadOnly
+}
+
+func (c *Client) RenameConversationContext(ctx context.Context, channelID, channelName string) (*slack.Channel, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *Client) SetTopicOfConversationContext(ctx context.Context, channelID, topic string) (*slack.Channel, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *Client) SetPurposeOfConversationContext(ctx context.Context, channelID, purpose string) (*slack.Channel, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *Client) JoinConversationContext(ctx context.Context, channelID string) (*slack.Channel, string, []string, error) {
+	return nil, "", nil, ErrReadOnly
+}
+
+func (c *Client) LeaveConversationContext(ctx context.Context, channelID string) (bool, error) {
+	return false, ErrReadOnly
+}
+
+func (c *Client) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *Client) KickUserFromConversationContext(ctx context.Context, channelID string, user string) error {
+	return ErrReadOnly
+}
+
+func (c *Client) AddPinContext(ctx context.Context, channel string, item slack.ItemRef) error {
+	return ErrReadOnly
+}
+
+func (c *Client) RemovePinContext(ctx context.Context, channel string, item slack.ItemRef) error {
+	return ErrReadOnly
+}
+
+func (c *Client) AddBookmarkContext(ctx context.Context, channelID string, params slack.AddBookmarkParameters) (slack.Bookmark, error) {
+	return slack.Bookmark{}, ErrReadOnly
+}
+
+func (c *Client) RemoveBookmarkContext(ctx context.Context, channelID, bookmarkID string) error {
+	return ErrReadOnly
+}
+
+func (c *Client) AddUserReminderContext(ctx context.Context, userID, text, time string) (*slack.Reminder, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *Client) ListRemindersContext(ctx context.Context) ([]*slack.Reminder, error) {
+	return nil, fmt.Errorf("reminders: %w", ErrNotSupported)
+}
+
+func (c *Client) CompleteReminderContext(ctx context.Context, reminderID string) error {
+	return ErrReadOnly
+}
+
+func (c *Client) DeleteReminderContext(ctx context.Context, reminderID string) error {
+	return ErrReadOnly
+}
+
+func (c *Client) GetUserPresenceContext(ctx context.Context, user string) (*slack.UserPresence, error) {
+	return nil, fmt.Errorf("presence: %w", ErrNotSupported)
+}
+
+func (c *Client) GetDNDTeamInfoContext(ctx context.Context, users []string) (map[string]slack.DNDStatus, error) {
+	return nil, fmt.Errorf("dnd: %w", ErrNotSupported)
+}
+
+func (c *Client) SetUserCustomStatusContext(ctx context.Context, statusText, statusEmoji string, statusExpiration int64) error {
+	return ErrReadOnly
+}
+
+func (c *Client) SetSnoozeContext(ctx context.Context, minutes int) (*slack.DNDStatus, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *Client) EndSnoozeContext(ctx context.Context) (*slack.DNDStatus, error) {
+	return nil, ErrReadOnly
+}
+
+// GetUserProfileContext returns the profile as exported with the user.
+func (c *Client) GetUserProfileContext(ctx context.Context, userID string) (*edge.UserProfile, error) {
+	for _, u := range c.users {
+		if u.ID == userID {
+			return &edge.UserProfile{UserProfile: u.Profile}, nil
+		}
+	}
+	return nil, slack.SlackErrorResponse{Err: "user_not_found"}
+}
+
+func (c *Client) GetTeamProfileContext(ctx context.Context) (*slack.TeamProfile, error) {
+	return nil, fmt.Errorf("team profile: %w", ErrNotSupported)
+}
+
+func (c *Client) AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	return ErrReadOnly
+}
+
+func (c *Client) RemoveReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	return ErrReadOnly
+}
+
+func (c *Client) CreateCanvasContext(ctx context.Context, title string, documentContent slack.DocumentContent) (string, error) {
+	return "", ErrReadOnly
+}
+
+func (c *Client) EditCanvasContext(ctx context.Context, params slack.EditCanvasParams) error {
+	return ErrReadOnly
+}
+
+// paginate pages through msgs, the cursor is the offset of the first message.
+func paginate(msgs []slack.Message, cursor string, limit int) ([]slack.Message, string, error) {
+	start := 0
+	if cursor != "" {
+		var err error
+		if start, err = strconv.Atoi(cursor); err != nil || start < 0 {
+			return nil, "", slack.SlackErrorResponse{Err: "invalid_cursor"}
+		}
+	}
+	start = min(start, len(msgs))
+	if limit <= 0 {
+		limit = 100
+	}
+	end := min(start+limit, len(msgs))
+	next := ""
+	if end < len(msgs) {
+		next = strconv.Itoa(end)
+	}
+	return msgs[start:end], next, nil
+}
+
+func inRange(ts, oldest, latest string, inclusive bool) bool {
+	t := tsKey(ts)
+	if oldest != "" {
+		o := tsKey(oldest)
+		if t < o || (!inclusive && t == o) {
+			return false
+		}
+	}
+	if latest != "" {
+		l := tsKey(latest)
+		if t > l || (!inclusive && t == l) {
+			return false
+		}
+	}
+	return true
+}
+
+// tsKey makes Slack timestamps comparable as strings, "1700000000.5" and
+// "1700000000.500000" get the same key.
+func tsKey(ts string) string {
+	sec, frac, _ := strings.Cut(ts, ".")
+	if len(frac) < 6 {
+		frac += strings.Repeat("0", 6-len(frac))
+	}
+	return fmt.Sprintf("%012s.%s", sec, frac)
+}
+
+func tsLess(a, b string) bool {
+	return tsKey(a) < tsKey(b)
+}
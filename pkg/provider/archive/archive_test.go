@@ -0,0 +1,236 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// exportFiles is a small Slack export: three users, a public, an archived and
+// a private channel, two DMs, a thread and an attached file.
+var exportFiles = map[string]string{
+	"users.json": `[
+		{"id": "U001", "name": "alice", "real_name": "Alice Archer", "profile": {"display_name": "alice", "email": "alice@example.com"}},
+		{"id": "U002", "name": "bob", "real_name": "Bob Builder", "profile": {"display_name": "bob", "email": "bob@example.com"}},
+		{"id": "U003", "name": "carol", "real_name": "Carol Cook", "profile": {"display_name": "carol", "email": "carol@example.com"}}
+	]`,
+	"channels.json": `[
+		{"id": "C001", "name": "general", "created": 1700000000, "members": ["U001", "U002", "U003"], "topic": {"value": "Company wide"}, "purpose": {"value": "Everything"}},
+		{"id": "C002", "name": "old", "created": 1700000000, "is_archived": true}
+	]`,
+	"groups.json": `[{"id": "G001", "name": "secret", "created": 1700000000, "is_private": true, "members": ["U001", "U002"]}]`,
+	"dms.json": `[
+		{"id": "D001", "created": 1700000000, "members": ["U001", "U002"]},
+		{"id": "D002", "created": 1700000000, "members": ["U001", "U003"]}
+	]`,
+	"general/2024-01-15.json": `[
+		{"type": "message", "user": "U001", "text": "Hello world", "ts": "1705312800.000100"},
+		{"type": "message", "user": "U002", "text": "Deploy thread", "ts": "1705316400.000200", "thread_ts": "1705316400.000200", "reply_count": 1, "replies": [{"user": "U001", "ts": "1705316460.000300"}]},
+		{"type": "message", "user": "U001", "text": "Deploy done", "ts": "1705316460.000300", "thread_ts": "1705316400.000200", "parent_user_id": "U002"},
+		{"type": "message", "user": "U002", "text": "See the notes", "ts": "1705320000.000400", "files": [{"id": "F001", "name": "notes.txt", "title": "Notes", "filetype": "text", "mimetype": "text/plain", "size": 11, "created": 1705320000}]}
+	]`,
+	"general/attachments/F001-notes.txt": "hello notes",
+	"D001/2024-01-16.json": `[
+		{"type": "message", "user": "U002", "text": "Secret plan", "ts": "1705400000.000100"}
+	]`,
+}
+
+func writeExport(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range exportFiles {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func openExport(t *testing.T) *Client {
+	t.Helper()
+	c, err := Open(context.Background(), writeExport(t), zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestUnitArchiveUsersAndChannels(t *testing.T) {
+	c := openExport(t)
+	ctx := context.Background()
+
+	users, err := c.GetUsersContext(ctx)
+	require.NoError(t, err)
+	assert.Len(t, users, 3)
+
+	info, err := c.GetUsersInfo("U002,U003")
+	require.NoError(t, err)
+	assert.Len(t, *info, 2)
+
+	found, err := c.UsersSearch(ctx, "CAROL", 10)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "U003", found[0].ID)
+
+	ids := func(types ...string) []string {
+		chans, cursor, err := c.GetConversationsContext(ctx, &slack.GetConversationsParameters{Types: types, ExcludeArchived: true})
+		require.NoError(t, err)
+		assert.Empty(t, cursor)
+		var res []string
+		for _, ch := range chans {
+			res = append(res, ch.ID)
+		}
+		return res
+	}
+	assert.Equal(t, []string{"C001"}, ids("public_channel"))
+	assert.Equal(t, []string{"G001"}, ids("private_channel"))
+	assert.ElementsMatch(t, []string{"D001", "D002"}, ids("im"))
+
+	auth, err := c.AuthTest()
+	require.NoError(t, err)
+	assert.Equal(t, fallbackURL, auth.URL)
+}
+
+func TestUnitArchiveHistoryAndReplies(t *testing.T) {
+	c := openExport(t)
+	ctx := context.Background()
+
+	history, err := c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C001", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, history.Messages, 2)
+	assert.Equal(t, "See the notes", history.Messages[0].Text)
+	assert.Equal(t, "Deploy thread", history.Messages[1].Text)
+	require.True(t, history.HasMore)
+
+	history, err = c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C001", Limit: 2, Cursor: history.ResponseMetaData.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, history.Messages, 1)
+	assert.Equal(t, "Hello world", history.Messages[0].Text)
+	assert.False(t, history.HasMore)
+
+	history, err = c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C001", Oldest: "1705316400.000200"})
+	require.NoError(t, err)
+	assert.Len(t, history.Messages, 1)
+
+	replies, hasMore, _, err := c.GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{ChannelID: "C001", Timestamp: "1705316400.000200"})
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+	require.Len(t, replies, 2)
+	assert.Equal(t, "Deploy thread", replies[0].Text)
+	assert.Equal(t, "Deploy done", replies[1].Text)
+
+	_, err = c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C999"})
+	assert.EqualError(t, err, "channel_not_found")
+}
+
+func TestUnitArchiveSearch(t *testing.T) {
+	c := openExport(t)
+	ctx := context.Background()
+
+	search := func(query string) []slack.SearchMessage {
+		t.Helper()
+		res, _, err := c.SearchContext(ctx, query, slack.SearchParameters{Count: 20, Page: 1})
+		require.NoError(t, err)
+		return res.Matches
+	}
+	texts := func(matches []slack.SearchMessage) []string {
+		var res []string
+		for _, m := range matches {
+			res = append(res, m.Text)
+		}
+		return res
+	}
+
+	assert.Equal(t, []string{"Deploy done", "Deploy thread"}, texts(search("deploy")))
+	assert.Equal(t, []string{"Deploy done"}, texts(search("deploy is:thread from:<@U001>")))
+	assert.Equal(t, []string{"Secret plan"}, texts(search("in:<@U002>")))
+	assert.Equal(t, []string{"Hello world"}, texts(search("hello in:#general on:2024-01-15")))
+	assert.Empty(t, search("hello after:2024-01-15"))
+	assert.Len(t, search("during:2024-01"), 5)
+	assert.Equal(t, []string{"Secret plan", "Deploy done", "Deploy thread"}, texts(search("with:<@U002>")))
+
+	matches := search("done")
+	require.Len(t, matches, 1)
+	assert.Equal(t, "general", matches[0].Channel.Name)
+	assert.True(t, strings.Contains(matches[0].Permalink, "thread_ts=1705316400.000200"), matches[0].Permalink)
+
+	res, _, err := c.SearchContext(ctx, "during:2024", slack.SearchParameters{Count: 2, Page: 2})
+	require.NoError(t, err)
+	assert.Len(t, res.Matches, 2)
+	assert.Equal(t, 3, res.Pagination.PageCount)
+
+	_, _, err = c.SearchContext(ctx, "from:<@U404>", slack.SearchParameters{})
+	assert.Error(t, err)
+}
+
+func TestUnitArchiveFiles(t *testing.T) {
+	c := openExport(t)
+	ctx := context.Background()
+
+	f, _, _, err := c.GetFileInfoContext(ctx, "F001", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "notes.txt", f.Name)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.GetFileContext(ctx, f.URLPrivate, &buf))
+	assert.Equal(t, "hello notes", buf.String())
+
+	files, _, err := c.GetFilesContext(ctx, slack.GetFilesParameters{Channel: "C001"})
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	canvases, _, err := c.GetFilesContext(ctx, slack.GetFilesParameters{Types: "canvases"})
+	require.NoError(t, err)
+	assert.Empty(t, canvases)
+
+	err = c.GetFileContext(ctx, "https://files.slack.com/unknown", &buf)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestUnitArchiveIsReadOnly(t *testing.T) {
+	c := openExport(t)
+	ctx := context.Background()
+
+	_, _, err := c.PostMessageContext(ctx, "C001", slack.MsgOptionText("hi", false))
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.ErrorIs(t, c.AddReactionContext(ctx, "wave", slack.NewRefToMessage("C001", "1705312800.000100")), ErrReadOnly)
+	assert.ErrorIs(t, c.MarkConversationContext(ctx, "C001", "1705312800.000100"), ErrReadOnly)
+	_, err = c.CreateCanvasContext(ctx, "Title", slack.DocumentContent{})
+	assert.ErrorIs(t, err, ErrReadOnly)
+}
+
+func TestUnitArchiveOpensZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range exportFiles {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	path := filepath.Join(t.TempDir(), "acme-export.zip")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+	c, err := Open(context.Background(), path, zap.NewNop())
+	require.NoError(t, err)
+	defer c.Close()
+
+	auth, err := c.AuthTest()
+	require.NoError(t, err)
+	assert.Equal(t, "acme-export", auth.Team)
+
+	history, err := c.GetConversationHistoryContext(context.Background(), &slack.GetConversationHistoryParameters{ChannelID: "D001"})
+	require.NoError(t, err)
+	require.Len(t, history.Messages, 1)
+	assert.Equal(t, "Secret plan", history.Messages[0].Text)
+}
@@ -0,0 +1,207 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/searchquery"
+	"github.com/slack-go/slack"
+)
+
+// SearchContext searches the archived messages, newest first. Files are not
+// searched.
+func (c *Client) SearchContext(ctx context.Context, raw string, params slack.SearchParameters) (*slack.SearchMessages, *slack.SearchFiles, error) {
+	q := searchquery.Parse(raw)
+
+	from, err := c.resolveUsers(q.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	with, err := c.resolveUsers(q.With)
+	if err != nil {
+		return nil, nil, err
+	}
+	channels, err := c.resolveChannels(q.In)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var matches []slack.SearchMessage
+	for _, ch := range c.channels {
+		if len(channels) > 0 && !channels[ch.ID] {
+			continue
+		}
+		msgs := c.messages[ch.ID]
+		for _, m := range msgs {
+			if q.Thread && m.ThreadTimestamp == "" {
+				continue
+			}
+			if len(from) > 0 && !from[m.User] {
+				continue
+			}
+			if len(with) > 0 && !c.isWith(&ch, msgs, &m, with) {
+				continue
+			}
+			if !q.MatchDay(m.Timestamp) || !containsAll(strings.ToLower(m.Text), q.Words) {
+				continue
+			}
+			matches = append(matches, c.searchMessage(&ch, &m))
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return tsLess(matches[j].Timestamp, matches[i].Timestamp) })
+
+	count := params.Count
+	if count <= 0 {
+		count = slack.DEFAULT_SEARCH_COUNT
+	}
+	page := params.Page
+	if page <= 0 {
+		page = 1
+	}
+	pages := (len(matches) + count - 1) / count
+	start := min((page-1)*count, len(matches))
+	end := min(start+count, len(matches))
+
+	res := &slack.SearchMessages{
+		Matches: matches[start:end],
+		Paging:  slack.Paging{Count: count, Total: len(matches), Page: page, Pages: pages},
+		Pagination: slack.Pagination{
+			TotalCount: len(matches),
+			Page:       page,
+			PerPage:    count,
+			PageCount:  pages,
+			First:      start + 1,
+			Last:       end,
+		},
+		Total: len(matches),
+	}
+	return res, &slack.SearchFiles{}, nil
+}
+
+func (c *Client) searchMessage(ch *slack.Channel, m *slack.Message) slack.SearchMessage {
+	name := ch.Name
+	if ch.IsIM {
+		name = ch.User
+	}
+	username := m.Username
+	for _, u := range c.users {
+		if u.ID == m.User {
+			username = u.Name
+			break
+		}
+	}
+	return slack.SearchMessage{
+		Type: "message",
+		Channel: slack.CtxChannel{
+			ID:        ch.ID,
+			Name:      name,
+			IsPrivate: ch.IsPrivate,
+			IsMPIM:    ch.IsMpIM,
+		},
+		User:        m.User,
+		Username:    username,
+		Timestamp:   m.Timestamp,
+		Blocks:      m.Blocks,
+		Text:        m.Text,
+		Permalink:   c.permalink(ch.ID, m),
+		Attachments: m.Attachments,
+	}
+}
+
+// permalink mimics Slack's permalinks, the search handler reads thread_ts from it.
+func (c *Client) permalink(channelID string, m *slack.Message) string {
+	link := fmt.Sprintf("%sarchives/%s/p%s", c.auth.URL, channelID, strings.ReplaceAll(m.Timestamp, ".", ""))
+	if m.ThreadTimestamp != "" && m.ThreadTimestamp != m.Timestamp {
+		link += fmt.Sprintf("?thread_ts=%s&cid=%s", m.ThreadTimestamp, channelID)
+	}
+	return link
+}
+
+// isWith reports whether m is in a conversation with one of the users: a DM
+// or group DM they are part of, or a thread they posted in.
+func (c *Client) isWith(ch *slack.Channel, msgs []slack.Message, m *slack.Message, users map[string]bool) bool {
+	if ch.IsIM && users[ch.User] {
+		return true
+	}
+	if ch.IsMpIM {
+		for _, member := range ch.Members {
+			if users[member] {
+				return true
+			}
+		}
+	}
+	if m.ThreadTimestamp == "" {
+		return false
+	}
+	for _, other := range msgs {
+		if (other.ThreadTimestamp == m.ThreadTimestamp || other.Timestamp == m.ThreadTimestamp) && users[other.User] {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveUsers turns <@U123>, @name, U123 or name into user IDs.
+func (c *Client) resolveUsers(refs []string) (map[string]bool, error) {
+	ids := make(map[string]bool)
+	for _, ref := range refs {
+		ref = strings.TrimPrefix(strings.Trim(ref, "<>"), "@")
+		ref, _, _ = strings.Cut(ref, "|")
+		found := false
+		for _, u := range c.users {
+			if u.ID == ref || u.Name == ref {
+				ids[u.ID] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("user %q not found in the archive", ref)
+		}
+	}
+	return ids, nil
+}
+
+// resolveChannels turns #name, name, <#C123|name>, C123 or a user reference
+// (meaning the DM with that user) into channel IDs.
+func (c *Client) resolveChannels(refs []string) (map[string]bool, error) {
+	ids := make(map[string]bool)
+	for _, ref := range refs {
+		if strings.HasPrefix(ref, "<@") || strings.HasPrefix(ref, "@") {
+			users, err := c.resolveUsers([]string{ref})
+			if err != nil {
+				return nil, err
+			}
+			for _, ch := range c.channels {
+				if ch.IsIM && users[ch.User] {
+					ids[ch.ID] = true
+				}
+			}
+			continue
+		}
+		name := strings.TrimPrefix(strings.Trim(ref, "<>"), "#")
+		name, _, _ = strings.Cut(name, "|")
+		found := false
+		for _, ch := range c.channels {
+			if ch.ID == name || (ch.Name != "" && (ch.Name == name || ch.NameNormalized == name)) {
+				ids[ch.ID] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("channel %q not found in the archive", ref)
+		}
+	}
+	return ids, nil
+}
+
+func containsAll(text string, words []string) bool {
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -281,8 +281,9 @@ type Profile1 struct {
 	Team                   string `json:"team"`
 }
 
+// Subteams lists the IDs of the user groups the authenticated user is in.
 type Subteams struct {
-	Self []any `json:"self"`
+	Self []string `json:"self"`
 }
 
 type Team struct {
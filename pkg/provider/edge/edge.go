@@ -1,7 +1,8 @@
 // Package edge provides a limited implementation of undocumented Slack Edge
 // API necessary to get the data from a slack workspace.
 //
-// It handles rate limit retries internally (but only once).
+// Rate limit and transient failure retries are left to the retry transport
+// of the HTTP client, and so is recording requests (see pkg/cassette).
 package edge
 
 import (
@@ -41,21 +42,26 @@ type Client struct {
 
 	// teamID is the team ID
 	teamID string
-	tape   io.WriteCloser
 }
 
 type Option func(*Client)
 
-func WithTape(tape io.WriteCloser) Option {
+// OptionHTTPClient - provide a custom http client to the slack client.
+func OptionHTTPClient(client httpClient) func(*Client) {
 	return func(cl *Client) {
-		cl.tape = tape
+		cl.cl = client
 	}
 }
 
-// OptionHTTPClient - provide a custom http client to the slack client.
-func OptionHTTPClient(client httpClient) func(*Client) {
+// OptionAPIURL points the client at a different Slack deployment, e.g. a
+// local stand-in. apiURL is the Web API root ("https://host/api/"), the edge
+// cache API is expected at "/cache/<teamID>/" on the same host.
+func OptionAPIURL(apiURL string) Option {
 	return func(cl *Client) {
-		cl.cl = client
+		cl.webclientAPI = apiURL
+		if u, err := url.Parse(apiURL); err == nil {
+			cl.edgeAPI = fmt.Sprintf("%s://%s/cache/%s/", u.Scheme, u.Host, cl.teamID)
+		}
 	}
 }
 
@@ -80,17 +86,12 @@ func NewWithClient(workspaceName string, teamID string, token string, cl *http.C
 	if token == "" {
 		return nil, ErrNoToken
 	}
-	tape, err := os.Create("tape.txt")
-	if err != nil {
-		return nil, err
-	}
 	return &Client{
 		cl:           cl,
 		token:        token,
 		teamID:       teamID,
 		webclientAPI: fmt.Sprintf("https://%s.%s/api/", workspaceName, getSlackBaseDomain()),
 		edgeAPI:      fmt.Sprintf("https://edgeapi.%s/cache/%s/", getSlackBaseDomain(), teamID),
-		tape:         tape,
 	}, nil
 }
 
@@ -105,16 +106,6 @@ func NewWithToken(ctx context.Context, token string, cookies []*http.Cookie) (*C
 	return New(ctx, prov)
 }
 
-type nopTape struct{}
-
-func (nopTape) Write(p []byte) (n int, err error) {
-	return len(p), nil
-}
-
-func (nopTape) Close() error {
-	return nil
-}
-
 // NewWithInfo is the same as New, but doesn't call the AuthTest on
 // initialisation.  Caller must ensure that the token is valid.
 func NewWithInfo(info *slack.AuthTestResponse, prov auth.Provider, opt ...Option) (*Client, error) {
@@ -128,7 +119,6 @@ func NewWithInfo(info *slack.AuthTestResponse, prov auth.Provider, opt ...Option
 		teamID:       info.TeamID,
 		webclientAPI: info.URL + "api/",
 		edgeAPI:      fmt.Sprintf("https://edgeapi.%s/cache/%s/", getSlackBaseDomain(), info.TeamID),
-		tape:         nopTape{},
 	}
 
 	for _, o := range opt {
@@ -158,9 +148,6 @@ func (cl *Client) Raw() httpClient {
 }
 
 func (cl *Client) Close() error {
-	if cl.tape != nil {
-		return cl.tape.Close()
-	}
 	return nil
 }
 
@@ -214,9 +201,7 @@ func (cl *Client) PostJSON(ctx context.Context, path string, req PostRequest) (*
 	if err != nil {
 		return nil, err
 	}
-	tape := cl.recorder(bytes.NewReader(data))
-	defer cl.record([]byte("\n\n"))
-	r, err := http.NewRequestWithContext(ctx, http.MethodPost, cl.edgeAPI+path, tape)
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, cl.edgeAPI+path, bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -245,21 +230,11 @@ func (cl *Client) PostForm(ctx context.Context, path string, form url.Values) (*
 	return cl.PostFormRaw(ctx, cl.webclientAPI+path, form)
 }
 
-func (cl *Client) record(b []byte) {
-	if cl.tape != nil {
-		if _, err := cl.tape.Write(b); err != nil {
-			slog.Default().Error("error writing to tape", "error", err)
-		}
-	}
-}
-
 func (cl *Client) PostFormRaw(ctx context.Context, url string, form url.Values) (*http.Response, error) {
 	if form["token"] == nil {
 		form.Set("token", cl.token)
 	}
-	r := cl.recorder(strings.NewReader(form.Encode()))
-	defer cl.record([]byte("\n\n"))
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, r)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, err
 	}
@@ -272,7 +247,7 @@ func (cl *Client) ParseResponse(req any, r *http.Response) error {
 		return fmt.Errorf("error: status code: %s", r.Status)
 	}
 	defer r.Body.Close()
-	bodyBytes, err := io.ReadAll(cl.recorder(r.Body))
+	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
 		return err
 	}
@@ -282,10 +257,10 @@ func (cl *Client) ParseResponse(req any, r *http.Response) error {
 	return nil
 }
 
-// do is a helper function to do the request and handle rate limiting.  it
-// tries to handle the rate limiting by waiting for the Retry-After only once,
-// if it receives another rate limit error, it returns slack.RateLimitedError
-// to let the caller handle it.
+// do is a helper function to do the request. Transient failures, including
+// rate limiting, are retried by the retry transport of the HTTP client; if
+// the request is still rate limited it returns slack.RateLimitedError to let
+// the caller handle it.
 func do(ctx context.Context, cl httpClient, req *http.Request) (*http.Response, error) {
 	ctx, task := trace.NewTask(ctx, "edge.do")
 	defer task.End()
@@ -301,26 +276,13 @@ func do(ctx context.Context, cl httpClient, req *http.Request) (*http.Response,
 		return nil, err
 	}
 	if resp.StatusCode == http.StatusTooManyRequests {
+		resp.Body.Close()
 		wait, err := parseRetryAfter(resp)
 		if err != nil {
 			return nil, err
 		}
-		lg.InfoContext(ctx, "got rate limited, waiting", "delay", wait)
-
-		time.Sleep(wait)
-		resp, err = cl.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		// if we are still rate limited, then we are in trouble
-		if resp.StatusCode == http.StatusTooManyRequests {
-			lg.DebugContext(ctx, "edge.do: did my best, but still rate limited, giving up, not my problem")
-			wait, err = parseRetryAfter(resp)
-			if err != nil {
-				return nil, err
-			}
-			return nil, &slack.RateLimitedError{RetryAfter: wait}
-		}
+		lg.DebugContext(ctx, "edge.do: still rate limited after retries, giving up", "delay", wait)
+		return nil, &slack.RateLimitedError{RetryAfter: wait}
 	}
 	if resp.StatusCode < http.StatusOK || http.StatusMultipleChoices <= resp.StatusCode {
 		body, _ := io.ReadAll(resp.Body)
@@ -386,13 +348,6 @@ func webclientReason(reason string) WebClientFields {
 	}
 }
 
-func (cl *Client) recorder(r io.Reader) io.Reader {
-	if cl.tape == nil {
-		return r
-	}
-	return io.TeeReader(r, cl.tape)
-}
-
 // Pagination contains the pagination information.  It is truly fucked, Slack
 // does not allow to seek past Page 100, when page > 100 requested, Slack
 // returns the first page (Page=1).  Seems to be an internal limitation.  The
@@ -0,0 +1,33 @@
+package edge
+
+import (
+	"context"
+	"runtime/trace"
+)
+
+// reminders.* API, slack-go doesn't wrap reminders.complete.
+
+type remindersCompleteForm struct {
+	BaseRequest
+	Reminder string `json:"reminder"`
+}
+
+// RemindersComplete marks a reminder as complete.
+func (cl *Client) RemindersComplete(ctx context.Context, reminderID string) error {
+	ctx, task := trace.NewTask(ctx, "RemindersComplete")
+	defer task.End()
+
+	form := remindersCompleteForm{
+		BaseRequest: BaseRequest{Token: cl.token},
+		Reminder:    reminderID,
+	}
+	resp, err := cl.PostForm(ctx, "reminders.complete", values(form, true))
+	if err != nil {
+		return err
+	}
+	var r baseResponse
+	if err := cl.ParseResponse(&r, resp); err != nil {
+		return err
+	}
+	return r.validate("reminders.complete")
+}
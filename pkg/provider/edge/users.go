@@ -55,3 +55,47 @@ type usersSearchForm struct {
 	Query string `json:"query"`
 	Count int    `json:"count,omitempty"`
 }
+
+// users.profile.get API, slack-go's UserProfile has no pronouns.
+
+// UserProfile is a profile as returned by users.profile.get.
+type UserProfile struct {
+	slack.UserProfile
+	Pronouns string `json:"pronouns,omitempty"`
+}
+
+type usersProfileGetForm struct {
+	BaseRequest
+	User          string `json:"user"`
+	IncludeLabels bool   `json:"include_labels"`
+}
+
+type usersProfileGetResponse struct {
+	baseResponse
+	Profile UserProfile `json:"profile"`
+}
+
+// UsersProfileGet returns the profile of a user with the labels of its
+// custom fields.
+func (cl *Client) UsersProfileGet(ctx context.Context, userID string) (*UserProfile, error) {
+	ctx, task := trace.NewTask(ctx, "UsersProfileGet")
+	defer task.End()
+
+	form := usersProfileGetForm{
+		BaseRequest:   BaseRequest{Token: cl.token},
+		User:          userID,
+		IncludeLabels: true,
+	}
+	resp, err := cl.PostForm(ctx, "users.profile.get", values(form, true))
+	if err != nil {
+		return nil, err
+	}
+	var r usersProfileGetResponse
+	if err := cl.ParseResponse(&r, resp); err != nil {
+		return nil, err
+	}
+	if err := r.validate("users.profile.get"); err != nil {
+		return nil, err
+	}
+	return &r.Profile, nil
+}
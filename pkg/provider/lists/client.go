@@ -7,7 +7,12 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
+	"github.com/slack-go/slack"
 )
 
 const defaultAPIURL = "https://slack.com/api/"
@@ -17,18 +22,40 @@ type Client struct {
 	token      string
 	httpClient *http.Client
 	apiURL     string
+	limits     *limiter.Registry
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// OptionRateLimits routes every call through the per-method Slack limiters.
+func OptionRateLimits(limits *limiter.Registry) Option {
+	return func(c *Client) {
+		c.limits = limits
+	}
+}
+
+// OptionAPIURL overrides the Slack Web API root, e.g. "https://slack-gov.com/api/".
+func OptionAPIURL(apiURL string) Option {
+	return func(c *Client) {
+		c.apiURL = apiURL
+	}
 }
 
 // NewClient creates a new Lists API client.
-func NewClient(token string, httpClient *http.Client) *Client {
+func NewClient(token string, httpClient *http.Client, opts ...Option) *Client {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
-	return &Client{
+	c := &Client{
 		token:      token,
 		httpClient: httpClient,
 		apiURL:     defaultAPIURL,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // postJSON sends a POST request with a JSON body to the given Slack API method.
@@ -38,34 +65,35 @@ func (c *Client) postJSON(ctx context.Context, method string, payload any) ([]by
 		return nil, fmt.Errorf("failed to marshal request for %s: %w", method, err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+method, strings.NewReader(string(jsonBody)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request for %s: %w", method, err)
-	}
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	req.Header.Set("Authorization", "Bearer "+c.token)
+	return c.call(ctx, method, "application/json; charset=utf-8", string(jsonBody))
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call %s: %w", method, err)
-	}
-	defer resp.Body.Close()
+// post sends a POST request to the given Slack API method with form-encoded parameters.
+func (c *Client) post(ctx context.Context, method string, params url.Values) ([]byte, error) {
+	return c.call(ctx, method, "application/x-www-form-urlencoded", params.Encode())
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response from %s: %w", method, err)
+// call sends the request, waiting for the method's rate limiter first when one is configured.
+func (c *Client) call(ctx context.Context, method, contentType, payload string) ([]byte, error) {
+	if c.limits == nil {
+		return c.do(ctx, method, contentType, payload)
 	}
 
-	return body, nil
+	var body []byte
+	err := c.limits.Do(ctx, method, func() error {
+		var err error
+		body, err = c.do(ctx, method, contentType, payload)
+		return err
+	})
+	return body, err
 }
 
-// post sends a POST request to the given Slack API method with form-encoded parameters.
-func (c *Client) post(ctx context.Context, method string, params url.Values) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+method, strings.NewReader(params.Encode()))
+func (c *Client) do(ctx context.Context, method, contentType, payload string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+method, strings.NewReader(payload))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request for %s: %w", method, err)
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Authorization", "Bearer "+c.token)
 
 	resp, err := c.httpClient.Do(req)
@@ -74,6 +102,11 @@ func (c *Client) post(ctx context.Context, method string, params url.Values) ([]
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return nil, fmt.Errorf("failed to call %s: %w", method, &slack.RateLimitedError{RetryAfter: time.Duration(retryAfter) * time.Second})
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response from %s: %w", method, err)
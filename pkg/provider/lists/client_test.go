@@ -0,0 +1,53 @@
+package lists
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
+	"github.com/korotovsky/slack-mcp-server/pkg/retry"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUnitClientRateLimited(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/slackLists.items.delete", r.URL.Path)
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	t.Run("without limiter the error is surfaced", func(t *testing.T) {
+		calls.Store(0)
+		c := NewClient("xoxp-test", srv.Client())
+		c.apiURL = srv.URL + "/"
+
+		err := c.DeleteItem(context.Background(), "F1", "Rec1")
+		var rle *slack.RateLimitedError
+		require.True(t, errors.As(err, &rle))
+		assert.Equal(t, time.Second, rle.RetryAfter)
+	})
+
+	t.Run("with the retry transport the call is retried", func(t *testing.T) {
+		calls.Store(0)
+		httpClient := &http.Client{Transport: retry.NewTransport(srv.Client().Transport, retry.DefaultPolicy(), zap.NewNop())}
+		c := NewClient("xoxp-test", httpClient, OptionRateLimits(limiter.NewRegistry(zap.NewNop())))
+		c.apiURL = srv.URL + "/"
+
+		require.NoError(t, c.DeleteItem(context.Background(), "F1", "Rec1"))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
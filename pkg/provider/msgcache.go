@@ -0,0 +1,468 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/msgstore"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const defaultMessageCacheTTL = 1 * time.Minute
+
+// messageCursorPrefix marks cursors handed out for pages served from the
+// message store, they carry the ts of the last message of the page.
+const messageCursorPrefix = "msgstore:"
+
+// messageSyncPageSize is the page size used to fetch new messages.
+var messageSyncPageSize = 200
+
+// getMessageCachePath returns the message store path from SLACK_MCP_MESSAGE_CACHE,
+// "" while the message cache is disabled. "true" picks a file in the cache dir.
+func getMessageCachePath() string {
+	path := strings.TrimSpace(os.Getenv("SLACK_MCP_MESSAGE_CACHE"))
+	switch path {
+	case "", "0", "false", "no":
+		return ""
+	case "1", "true", "yes":
+		return filepath.Join(getCacheDir(), "messages.db")
+	}
+	return path
+}
+
+// getMessageCacheTTL returns from SLACK_MCP_MESSAGE_CACHE_TTL how long synced
+// messages are served without asking Slack for newer ones, default 1 minute.
+// Supports formats: "1m", "30s", "60" (seconds), "0" (always check Slack).
+// Negative values are rejected and fall back to default.
+func getMessageCacheTTL() time.Duration {
+	ttlStr := os.Getenv("SLACK_MCP_MESSAGE_CACHE_TTL")
+	if ttlStr == "" {
+		return defaultMessageCacheTTL
+	}
+
+	if d, err := time.ParseDuration(ttlStr); err == nil {
+		if d < 0 {
+			return defaultMessageCacheTTL
+		}
+		return d
+	}
+
+	if secs, err := strconv.ParseInt(ttlStr, 10, 64); err == nil {
+		if secs < 0 {
+			return defaultMessageCacheTTL
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	return defaultMessageCacheTTL
+}
+
+// cachedClient wraps a SlackAPI and keeps conversation history and thread
+// replies in a local message store. Channels and threads that were synced
+// within the TTL are served from the store; once that runs out only messages
+// newer than the last synced one are fetched. Edits, reactions and deletions
+// of messages that are already stored are not picked up by these incremental
+// fetches.
+type cachedClient struct {
+	SlackAPI
+	store  *msgstore.Store
+	team   string
+	ttl    time.Duration
+	logger *zap.Logger
+
+	// localSearch answers SearchContext from the store, for tokens that
+	// can't call search.messages; url and directory build its results.
+	localSearch bool
+	url         string
+	directory   directory
+
+	locks sync.Map // channel or channel/thread_ts -> *sync.Mutex
+}
+
+func newCachedClient(next SlackAPI, store *msgstore.Store, team string, ttl time.Duration, logger *zap.Logger) *cachedClient {
+	return &cachedClient{SlackAPI: next, store: store, team: team, ttl: ttl, logger: logger}
+}
+
+// newMessageCache opens the message store when SLACK_MCP_MESSAGE_CACHE is set
+// and returns next wrapped in it, or nil when the cache is disabled.
+func newMessageCache(next SlackAPI, client *MCPSlackClient, logger *zap.Logger) *cachedClient {
+	path := getMessageCachePath()
+	if path == "" || client == nil {
+		return nil
+	}
+	store, err := msgstore.Open(path)
+	if err != nil {
+		logger.Fatal("Failed to open message cache", zap.String("path", path), zap.Error(err))
+	}
+	logger.Info("Using local message cache",
+		zap.String("context", "console"),
+		zap.String("path", path),
+	)
+	c := newCachedClient(next, store, client.AuthResponse().TeamID, getMessageCacheTTL(), logger)
+	c.localSearch = client.IsBotToken()
+	c.url = client.AuthResponse().URL
+	return c
+}
+
+func (c *cachedClient) lock(key string) func() {
+	mu, _ := c.locks.LoadOrStore(key, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+func (c *cachedClient) fresh(st *msgstore.State) bool {
+	return time.Since(st.SyncedAt) < c.ttl
+}
+
+func (c *cachedClient) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	p := *params
+	if p.Cursor != "" {
+		ts, ok := strings.CutPrefix(p.Cursor, messageCursorPrefix)
+		if !ok {
+			// a cursor of Slack itself, keep paging there
+			return c.fetchHistory(ctx, &p, nil)
+		}
+		p.Cursor, p.Latest, p.Inclusive = "", ts, false
+	}
+
+	unlock := c.lock(p.ChannelID)
+	defer unlock()
+
+	st, err := c.store.State(ctx, c.team, p.ChannelID, "")
+	if err != nil {
+		c.logger.Warn("Message cache read failed", zap.String("channel", p.ChannelID), zap.Error(err))
+		return c.SlackAPI.GetConversationHistoryContext(ctx, &p)
+	}
+	if st == nil {
+		return c.fetchHistory(ctx, &p, nil)
+	}
+	if !c.fresh(st) {
+		if _, err := c.syncHistory(ctx, p.ChannelID, st); err != nil {
+			return nil, err
+		}
+	}
+
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	msgs, err := c.store.History(ctx, c.team, p.ChannelID, msgstore.Query{
+		Oldest:    p.Oldest,
+		Latest:    p.Latest,
+		Inclusive: p.Inclusive,
+		Limit:     limit + 1,
+	})
+	if err != nil {
+		c.logger.Warn("Message cache read failed", zap.String("channel", p.ChannelID), zap.Error(err))
+		return c.SlackAPI.GetConversationHistoryContext(ctx, &p)
+	}
+
+	// Without a full page the rest may be older than what's stored.
+	covered := st.Complete || (p.Oldest != "" && p.Oldest >= st.Oldest)
+	if len(msgs) <= limit && !covered {
+		return c.fetchHistory(ctx, &p, st)
+	}
+
+	res := &slack.GetConversationHistoryResponse{
+		SlackResponse: slack.SlackResponse{Ok: true},
+		Messages:      msgs,
+	}
+	if len(msgs) > limit {
+		res.Messages = msgs[:limit]
+		res.HasMore = true
+		res.ResponseMetaData.NextCursor = messageCursorPrefix + msgs[limit-1].Timestamp
+	}
+	c.logger.Debug("Served history from message cache", zap.String("channel", p.ChannelID), zap.Int("count", len(res.Messages)))
+	return res, nil
+}
+
+// fetchHistory asks Slack, stores the result and widens the synced range of
+// the channel when the page joins it.
+func (c *cachedClient) fetchHistory(ctx context.Context, p *slack.GetConversationHistoryParameters, st *msgstore.State) (*slack.GetConversationHistoryResponse, error) {
+	res, err := c.SlackAPI.GetConversationHistoryContext(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.store.Put(ctx, c.team, p.ChannelID, res.Messages); err != nil {
+		c.logger.Warn("Message cache write failed", zap.String("channel", p.ChannelID), zap.Error(err))
+		return res, nil
+	}
+	if p.Cursor != "" {
+		return res, nil
+	}
+
+	// The page holds every message between lower and upper, "" being unbounded.
+	upper := p.Latest
+	lower := p.Oldest
+	if res.HasMore && len(res.Messages) > 0 {
+		lower = res.Messages[len(res.Messages)-1].Timestamp
+	}
+	next := msgstore.State{
+		Oldest:   lower,
+		Latest:   newestTS(res.Messages, ""),
+		Complete: lower == "",
+		SyncedAt: time.Now(),
+	}
+	switch {
+	case st == nil && upper != "":
+		// not joined to the newest messages, incremental fetches would leave a gap
+		return res, nil
+	case st == nil:
+	case upper == "" && lower > st.Latest && st.Latest != "":
+		// newer than everything stored with a gap in between, start over
+	case upper != "" && upper < st.Oldest:
+		return res, nil
+	default:
+		if upper != "" {
+			next.Latest, next.SyncedAt = st.Latest, st.SyncedAt
+		} else {
+			next.Latest = newestTS(nil, next.Latest, st.Latest)
+		}
+		if st.Oldest <= lower {
+			next.Oldest, next.Complete = st.Oldest, st.Complete
+		}
+	}
+	if err := c.store.SetState(ctx, c.team, p.ChannelID, "", next); err != nil {
+		c.logger.Warn("Message cache write failed", zap.String("channel", p.ChannelID), zap.Error(err))
+	}
+	return res, nil
+}
+
+// syncHistory fetches every message newer than the synced range of a channel
+// and returns the ts of the latest reply of each thread parent it saw.
+func (c *cachedClient) syncHistory(ctx context.Context, channel string, st *msgstore.State) (map[string]string, error) {
+	since := st.Latest
+	if since == "" {
+		since = st.Oldest
+	}
+	p := &slack.GetConversationHistoryParameters{ChannelID: channel, Oldest: since, Limit: messageSyncPageSize}
+	latest := st.Latest
+	count := 0
+	threads := make(map[string]string)
+	for {
+		res, err := c.SlackAPI.GetConversationHistoryContext(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.store.Put(ctx, c.team, channel, res.Messages); err != nil {
+			return nil, err
+		}
+		for _, m := range res.Messages {
+			if m.ReplyCount > 0 {
+				threads[m.Timestamp] = m.LatestReply
+			}
+		}
+		latest = newestTS(res.Messages, latest)
+		count += len(res.Messages)
+		if !res.HasMore || res.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		p.Cursor = res.ResponseMetaData.NextCursor
+	}
+
+	st.Latest = latest
+	st.SyncedAt = time.Now()
+	c.logger.Debug("Synced channel history", zap.String("channel", channel), zap.Int("new_messages", count))
+	return threads, c.store.SetState(ctx, c.team, channel, "", *st)
+}
+
+func (c *cachedClient) GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error) {
+	p := *params
+	if p.Cursor != "" {
+		ts, ok := strings.CutPrefix(p.Cursor, messageCursorPrefix)
+		if !ok {
+			msgs, hasMore, next, err := c.SlackAPI.GetConversationRepliesContext(ctx, &p)
+			if err == nil {
+				if err := c.store.Put(ctx, c.team, p.ChannelID, msgs); err != nil {
+					c.logger.Warn("Message cache write failed", zap.String("channel", p.ChannelID), zap.Error(err))
+				}
+			}
+			return msgs, hasMore, next, err
+		}
+		p.Cursor, p.Oldest, p.Inclusive = "", ts, false
+	}
+
+	// ts may point at any message of the thread, the store is keyed by its parent
+	root := p.Timestamp
+	if m, err := c.store.Message(ctx, c.team, p.ChannelID, p.Timestamp); err == nil && m != nil && m.ThreadTimestamp != "" {
+		root = m.ThreadTimestamp
+	}
+
+	unlock := c.lock(p.ChannelID + "/" + root)
+	defer unlock()
+
+	st, err := c.store.State(ctx, c.team, p.ChannelID, root)
+	if err != nil {
+		c.logger.Warn("Message cache read failed", zap.String("channel", p.ChannelID), zap.Error(err))
+		return c.SlackAPI.GetConversationRepliesContext(ctx, &p)
+	}
+	if st == nil || !c.fresh(st) {
+		if root, err = c.syncReplies(ctx, p.ChannelID, root, st); err != nil {
+			return nil, false, "", err
+		}
+	}
+
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	parent, err := c.store.Message(ctx, c.team, p.ChannelID, root)
+	if err != nil {
+		return nil, false, "", err
+	}
+	replies, err := c.store.Replies(ctx, c.team, p.ChannelID, root, msgstore.Query{
+		Oldest:    p.Oldest,
+		Latest:    p.Latest,
+		Inclusive: p.Inclusive,
+		Limit:     limit + 1,
+	})
+	if err != nil {
+		return nil, false, "", err
+	}
+
+	var (
+		msgs    []slack.Message
+		hasMore bool
+		next    string
+	)
+	// like Slack, every page starts with the parent
+	if parent != nil {
+		msgs = append(msgs, *parent)
+	}
+	if len(replies) > limit {
+		replies = replies[:limit]
+		hasMore = true
+		next = messageCursorPrefix + replies[limit-1].Timestamp
+	}
+	msgs = append(msgs, replies...)
+	c.logger.Debug("Served replies from message cache", zap.String("channel", p.ChannelID), zap.String("thread_ts", root), zap.Int("count", len(msgs)))
+	return msgs, hasMore, next, nil
+}
+
+// syncReplies fetches the replies of a thread newer than its synced range, or
+// the whole thread if it was never synced, and returns the ts of its parent.
+func (c *cachedClient) syncReplies(ctx context.Context, channel, ts string, st *msgstore.State) (string, error) {
+	p := &slack.GetConversationRepliesParameters{ChannelID: channel, Timestamp: ts, Limit: messageSyncPageSize}
+	var latest string
+	if st != nil {
+		p.Oldest, latest = st.Latest, st.Latest
+	}
+	root := ts
+	for {
+		msgs, hasMore, next, err := c.SlackAPI.GetConversationRepliesContext(ctx, p)
+		if err != nil {
+			return "", err
+		}
+		if err := c.store.Put(ctx, c.team, channel, msgs); err != nil {
+			return "", err
+		}
+		if len(msgs) > 0 && msgs[0].ThreadTimestamp != "" {
+			root = msgs[0].ThreadTimestamp
+		}
+		latest = newestTS(msgs, latest)
+		if !hasMore || next == "" {
+			break
+		}
+		p.Cursor = next
+	}
+
+	return root, c.store.SetState(ctx, c.team, channel, root, msgstore.State{
+		Oldest:   root,
+		Latest:   latest,
+		Complete: true,
+		SyncedAt: time.Now(),
+	})
+}
+
+// syncChannels keeps channels in the store until ctx is done. A channel that
+// was never synced gets its full history on the first pass, later passes only
+// fetch newer messages.
+func (c *cachedClient) syncChannels(ctx context.Context, channels []string) {
+	interval := c.ttl
+	if interval <= 0 {
+		interval = defaultMessageCacheTTL
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	skip := make(map[string]bool)
+	for {
+		for _, channel := range channels {
+			if skip[channel] {
+				continue
+			}
+			if err := c.syncChannel(ctx, channel); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if isInaccessible(err) {
+					c.logger.Info("Channel can't be read, no longer syncing it", zap.String("channel", channel), zap.Error(err))
+					skip[channel] = true
+					continue
+				}
+				c.logger.Warn("Failed to sync channel messages", zap.String("channel", channel), zap.Error(err))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *cachedClient) syncChannel(ctx context.Context, channel string) error {
+	unlock := c.lock(channel)
+	defer unlock()
+
+	st, err := c.store.State(ctx, c.team, channel, "")
+	if err != nil {
+		return err
+	}
+	if st == nil || !st.Complete {
+		// backfill from the first message, what's stored already is kept
+		st = &msgstore.State{Complete: true}
+	}
+	threads, err := c.syncHistory(ctx, channel, st)
+	if err != nil {
+		return err
+	}
+
+	// Replies of the threads seen are synced too so they can be searched.
+	// Threads whose parent is older than the last pass aren't seen again.
+	for ts, latestReply := range threads {
+		tst, err := c.store.State(ctx, c.team, channel, ts)
+		if err != nil {
+			return err
+		}
+		if tst != nil && tst.Latest >= latestReply {
+			continue
+		}
+		if _, err := c.syncReplies(ctx, channel, ts, tst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newestTS returns the largest of the timestamps of msgs and more.
+func newestTS(msgs []slack.Message, more ...string) string {
+	newest := ""
+	for _, ts := range more {
+		if ts > newest {
+			newest = ts
+		}
+	}
+	for _, m := range msgs {
+		if m.Timestamp > newest {
+			newest = m.Timestamp
+		}
+	}
+	return newest
+}
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/msgstore"
+	"github.com/korotovsky/slack-mcp-server/pkg/test/fakeslack"
+	"github.com/rusq/slackdump/v3/auth"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestCachedClient(t *testing.T) (*cachedClient, *fakeslack.Server) {
+	t.Helper()
+	srv := fakeslack.New()
+	t.Cleanup(srv.Close)
+	t.Setenv("SLACK_MCP_API_URL", srv.APIURL())
+
+	authProvider, err := auth.NewValueAuth(fakeslack.Token, "")
+	require.NoError(t, err)
+	client, err := NewMCPSlackClient(authProvider, zap.NewNop())
+	require.NoError(t, err)
+
+	store, err := msgstore.Open(filepath.Join(t.TempDir(), "messages.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	return newCachedClient(client, store, "T001", time.Hour, zap.NewNop()), srv
+}
+
+func countCalls(srv *fakeslack.Server, method string) int {
+	n := 0
+	for _, c := range srv.Calls() {
+		if c == method {
+			n++
+		}
+	}
+	return n
+}
+
+func TestUnitMessageCacheHistory(t *testing.T) {
+	c, srv := newTestCachedClient(t)
+	ctx := context.Background()
+	params := &slack.GetConversationHistoryParameters{ChannelID: "C001", Limit: 100}
+
+	first, err := c.GetConversationHistoryContext(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, first.Messages, 2)
+	assert.Equal(t, 1, countCalls(srv, "conversations.history"))
+
+	// the whole channel came back, so the next read is local
+	second, err := c.GetConversationHistoryContext(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, first.Messages, second.Messages)
+	assert.Equal(t, 1, countCalls(srv, "conversations.history"))
+
+	// once the TTL ran out only newer messages are fetched
+	newest := first.Messages[0].Timestamp
+	ts := srv.AddMessage("C001", "U001", "Anything new?", "")
+	c.ttl = 0
+	third, err := c.GetConversationHistoryContext(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, third.Messages, 3)
+	assert.Equal(t, ts, third.Messages[0].Timestamp)
+	assert.Equal(t, 2, countCalls(srv, "conversations.history"))
+
+	st, err := c.store.State(ctx, "T001", "C001", "")
+	require.NoError(t, err)
+	assert.True(t, st.Complete)
+	assert.Equal(t, ts, st.Latest)
+	assert.Greater(t, ts, newest)
+}
+
+func TestUnitMessageCacheHistoryPaging(t *testing.T) {
+	c, srv := newTestCachedClient(t)
+	ctx := context.Background()
+	require.NoError(t, c.syncChannel(ctx, "C001"))
+	calls := countCalls(srv, "conversations.history")
+
+	var texts []string
+	params := &slack.GetConversationHistoryParameters{ChannelID: "C001", Limit: 1}
+	for {
+		res, err := c.GetConversationHistoryContext(ctx, params)
+		require.NoError(t, err)
+		for _, m := range res.Messages {
+			texts = append(texts, m.Text)
+		}
+		if !res.HasMore {
+			break
+		}
+		assert.True(t, strings.HasPrefix(res.ResponseMetaData.NextCursor, messageCursorPrefix))
+		params.Cursor = res.ResponseMetaData.NextCursor
+	}
+	assert.Equal(t, []string{"Release planning thread", "Welcome to the fake workspace"}, texts)
+	assert.Equal(t, calls, countCalls(srv, "conversations.history"))
+}
+
+func TestUnitMessageCachePartialRange(t *testing.T) {
+	c, srv := newTestCachedClient(t)
+	ctx := context.Background()
+	msgs := srv.Messages("C001")
+
+	// only the newest message is known, older ones still come from Slack
+	_, err := c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C001", Limit: 1})
+	require.NoError(t, err)
+	res, err := c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C001", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, res.Messages, 2)
+	assert.Equal(t, 2, countCalls(srv, "conversations.history"))
+
+	// now both are stored and the range reaches the first message
+	res, err = c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C001", Limit: 10, Oldest: msgs[0].Timestamp, Inclusive: true})
+	require.NoError(t, err)
+	assert.Len(t, res.Messages, 2)
+	assert.Equal(t, 2, countCalls(srv, "conversations.history"))
+}
+
+func TestUnitMessageCacheReplies(t *testing.T) {
+	c, srv := newTestCachedClient(t)
+	ctx := context.Background()
+	parent := srv.Messages("C001")[1]
+	params := &slack.GetConversationRepliesParameters{ChannelID: "C001", Timestamp: parent.Timestamp}
+
+	msgs, hasMore, _, err := c.GetConversationRepliesContext(ctx, params)
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+	require.Len(t, msgs, 3)
+	assert.Equal(t, parent.Timestamp, msgs[0].Timestamp)
+
+	// looking the thread up by one of its replies is served locally too
+	_, _, _, err = c.GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{ChannelID: "C001", Timestamp: msgs[2].Timestamp})
+	require.NoError(t, err)
+	assert.Equal(t, 1, countCalls(srv, "conversations.replies"))
+
+	ts := srv.AddMessage("C001", "U003", "Docs are done", parent.Timestamp)
+	c.ttl = 0
+	msgs, _, _, err = c.GetConversationRepliesContext(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, msgs, 4)
+	assert.Equal(t, ts, msgs[3].Timestamp)
+	assert.Equal(t, 3, msgs[0].ReplyCount)
+	assert.Equal(t, 2, countCalls(srv, "conversations.replies"))
+
+	// pages of one reply, each led by the parent
+	c.ttl = time.Hour
+	params.Limit = 1
+	var replies []string
+	for {
+		msgs, hasMore, next, err := c.GetConversationRepliesContext(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, parent.Timestamp, msgs[0].Timestamp)
+		for _, m := range msgs[1:] {
+			replies = append(replies, m.Timestamp)
+		}
+		if !hasMore {
+			break
+		}
+		params.Cursor = next
+	}
+	assert.Len(t, replies, 3)
+	assert.Equal(t, 2, countCalls(srv, "conversations.replies"))
+}
+
+func TestUnitMessageCacheSlackCursor(t *testing.T) {
+	c, srv := newTestCachedClient(t)
+	ctx := context.Background()
+
+	// Slack's own cursors keep paging through Slack
+	res, err := c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C001", Limit: 1, Latest: "1800000000.000000"})
+	require.NoError(t, err)
+	require.True(t, res.HasMore)
+	res, err = c.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C001", Limit: 1, Latest: "1800000000.000000", Cursor: res.ResponseMetaData.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome to the fake workspace", res.Messages[0].Text)
+	assert.Equal(t, 2, countCalls(srv, "conversations.history"))
+
+	// a range that doesn't reach the newest message isn't treated as synced
+	st, err := c.store.State(ctx, "T001", "C001", "")
+	require.NoError(t, err)
+	assert.Nil(t, st)
+}
@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/msgstore"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/searchquery"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// directory resolves the users and channels named in search queries,
+// ApiProvider implements it.
+type directory interface {
+	ProvideUsersMap() *UsersCache
+	ProvideChannelsMaps() *ChannelsCache
+}
+
+// SearchContext answers from the full-text index of the message store when
+// the token can't call search.messages, i.e. for bot tokens. Only messages
+// that were synced are found, files are not searched.
+func (c *cachedClient) SearchContext(ctx context.Context, raw string, params slack.SearchParameters) (*slack.SearchMessages, *slack.SearchFiles, error) {
+	if !c.localSearch {
+		return c.SlackAPI.SearchContext(ctx, raw, params)
+	}
+
+	search, err := c.searchFilters(searchquery.Parse(raw))
+	if err != nil {
+		return nil, nil, err
+	}
+	count := params.Count
+	if count <= 0 {
+		count = slack.DEFAULT_SEARCH_COUNT
+	}
+	page := params.Page
+	if page <= 0 {
+		page = 1
+	}
+	search.Offset, search.Limit = (page-1)*count, count
+
+	hits, total, err := c.store.Search(ctx, c.team, search)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.logger.Debug("Searched message cache", zap.String("query", raw), zap.Int("total", total))
+
+	cms := c.directory.ProvideChannelsMaps()
+	users := c.directory.ProvideUsersMap()
+	matches := make([]slack.SearchMessage, 0, len(hits))
+	for _, h := range hits {
+		matches = append(matches, c.searchMessage(cms.Channels[h.Channel], h, users))
+	}
+
+	pages := (total + count - 1) / count
+	res := &slack.SearchMessages{
+		Matches: matches,
+		Paging:  slack.Paging{Count: count, Total: total, Page: page, Pages: pages},
+		Pagination: slack.Pagination{
+			TotalCount: total,
+			Page:       page,
+			PerPage:    count,
+			PageCount:  pages,
+			First:      search.Offset + 1,
+			Last:       search.Offset + len(matches),
+		},
+		Total: total,
+	}
+	return res, &slack.SearchFiles{}, nil
+}
+
+// searchFilters resolves the modifiers of q against the users and channels
+// caches.
+func (c *cachedClient) searchFilters(q searchquery.Query) (msgstore.Search, error) {
+	search := msgstore.Search{Words: q.Words, Threads: q.Thread}
+	cms := c.directory.ProvideChannelsMaps()
+
+	for _, ref := range q.In {
+		if strings.HasPrefix(ref, "<@") || strings.HasPrefix(ref, "@") {
+			id, err := c.resolveUser(ref)
+			if err != nil {
+				return search, err
+			}
+			found := false
+			for _, ch := range cms.Channels {
+				if ch.IsIM && ch.User == id {
+					search.Channels = append(search.Channels, ch.ID)
+					found = true
+				}
+			}
+			if !found {
+				return search, fmt.Errorf("direct message with %q not found", ref)
+			}
+			continue
+		}
+		name := strings.Trim(ref, "<>")
+		name, _, _ = strings.Cut(name, "|")
+		name = strings.TrimPrefix(name, "#")
+		if _, ok := cms.Channels[name]; ok {
+			search.Channels = append(search.Channels, name)
+		} else if id, ok := cms.ChannelsInv["#"+name]; ok {
+			search.Channels = append(search.Channels, id)
+		} else {
+			return search, fmt.Errorf("channel %q not found", ref)
+		}
+	}
+
+	for _, ref := range q.From {
+		id, err := c.resolveUser(ref)
+		if err != nil {
+			return search, err
+		}
+		search.Users = append(search.Users, id)
+	}
+
+	for _, ref := range q.With {
+		id, err := c.resolveUser(ref)
+		if err != nil {
+			return search, err
+		}
+		search.With = append(search.With, id)
+		for _, ch := range cms.Channels {
+			if (ch.IsIM && ch.User == id) || (ch.IsMpIM && containsString(ch.Members, id)) {
+				search.WithChannels = append(search.WithChannels, ch.ID)
+			}
+		}
+	}
+
+	from, to, err := q.Range()
+	if err != nil {
+		return search, err
+	}
+	if !from.IsZero() {
+		search.Oldest = unixTS(from)
+	}
+	if !to.IsZero() {
+		search.Latest = unixTS(to)
+	}
+	return search, nil
+}
+
+// resolveUser turns <@U123>, @name, U123 or name into a user ID.
+func (c *cachedClient) resolveUser(ref string) (string, error) {
+	name := strings.TrimPrefix(strings.Trim(ref, "<>"), "@")
+	name, _, _ = strings.Cut(name, "|")
+	users := c.directory.ProvideUsersMap()
+	if _, ok := users.Users[name]; ok {
+		return name, nil
+	}
+	if id, ok := users.UsersInv[name]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("user %q not found", ref)
+}
+
+func (c *cachedClient) searchMessage(ch Channel, h msgstore.Hit, users *UsersCache) slack.SearchMessage {
+	m := h.Message
+	name := strings.TrimLeft(ch.Name, "#@")
+	if ch.IsIM {
+		name = ch.User
+	}
+	username := m.Username
+	if u, ok := users.Users[m.User]; ok {
+		username = u.Name
+	}
+
+	// the search handler reads thread_ts from the permalink
+	link := permalink(c.url, h.Channel, m.Timestamp, m.ThreadTimestamp)
+
+	return slack.SearchMessage{
+		Type: "message",
+		Channel: slack.CtxChannel{
+			ID:        h.Channel,
+			Name:      name,
+			IsPrivate: ch.IsPrivate,
+			IsMPIM:    ch.IsMpIM,
+		},
+		User:        m.User,
+		Username:    username,
+		Timestamp:   m.Timestamp,
+		Blocks:      m.Blocks,
+		Text:        m.Text,
+		Permalink:   link,
+		Attachments: m.Attachments,
+	}
+}
+
+// isInaccessible reports whether err means the token can't read a channel,
+// e.g. a bot that was never invited.
+func isInaccessible(err error) bool {
+	var slackErr slack.SlackErrorResponse
+	if !errors.As(err, &slackErr) {
+		return false
+	}
+	switch slackErr.Err {
+	case "not_in_channel", "channel_not_found", "missing_scope":
+		return true
+	}
+	return false
+}
+
+func unixTS(t time.Time) string {
+	return fmt.Sprintf("%d.000000", t.Unix())
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
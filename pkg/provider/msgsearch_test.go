@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDirectory struct {
+	users    *UsersCache
+	channels *ChannelsCache
+}
+
+func (d *testDirectory) ProvideUsersMap() *UsersCache        { return d.users }
+func (d *testDirectory) ProvideChannelsMaps() *ChannelsCache { return d.channels }
+
+func TestUnitMessageCacheLocalSearch(t *testing.T) {
+	c, srv := newTestCachedClient(t)
+	ctx := context.Background()
+
+	dir := &testDirectory{
+		users:    &UsersCache{Users: map[string]slack.User{}, UsersInv: map[string]string{}},
+		channels: &ChannelsCache{Channels: map[string]Channel{}, ChannelsInv: map[string]string{}},
+	}
+	for _, u := range []slack.User{{ID: "U001", Name: "alice"}, {ID: "U002", Name: "bob"}, {ID: "U003", Name: "carol"}} {
+		dir.users.Users[u.ID] = u
+		dir.users.UsersInv[u.Name] = u.ID
+	}
+	for _, ch := range []Channel{
+		{ID: "C001", Name: "#general"},
+		{ID: "C002", Name: "#random"},
+		{ID: "D001", Name: "@bob", IsIM: true, User: "U002"},
+	} {
+		dir.channels.Channels[ch.ID] = ch
+		dir.channels.ChannelsInv[ch.Name] = ch.ID
+	}
+	c.directory, c.localSearch, c.url = dir, true, "https://example.slack.com/"
+
+	for _, ch := range []string{"C001", "C002", "D001"} {
+		require.NoError(t, c.syncChannel(ctx, ch))
+	}
+	// the threads seen while syncing are synced too
+	assert.Equal(t, 1, countCalls(srv, "conversations.replies"))
+
+	search := func(query string) *slack.SearchMessages {
+		t.Helper()
+		res, _, err := c.SearchContext(ctx, query, slack.SearchParameters{Count: 10, Page: 1})
+		require.NoError(t, err)
+		return res
+	}
+
+	res := search("docs")
+	require.Len(t, res.Matches, 1)
+	m := res.Matches[0]
+	assert.Equal(t, "I will update the docs", m.Text)
+	assert.Equal(t, "carol", m.Username)
+	assert.Equal(t, slack.CtxChannel{ID: "C001", Name: "general"}, m.Channel)
+	assert.Contains(t, m.Permalink, "https://example.slack.com/archives/C001/p")
+	assert.Contains(t, m.Permalink, "?thread_ts=")
+
+	assert.Len(t, search("is:thread in:#general").Matches, 3)
+	assert.Len(t, search("from:<@U002>").Matches, 3)
+	assert.Len(t, search("from:@bob in:#general").Matches, 2)
+	res = search("in:<@U002>")
+	require.Len(t, res.Matches, 1)
+	assert.Equal(t, "U002", res.Matches[0].Channel.Name)
+	assert.Len(t, search("with:@carol").Matches, 3)
+	assert.Empty(t, search("lunch before:2023-01-01").Matches)
+	assert.Len(t, search("lunch after:2023-01-01").Matches, 1)
+
+	res, _, err := c.SearchContext(ctx, "in:#general", slack.SearchParameters{Count: 2, Page: 2})
+	require.NoError(t, err)
+	assert.Len(t, res.Matches, 2)
+	assert.Equal(t, slack.Pagination{TotalCount: 4, Page: 2, PerPage: 2, PageCount: 2, First: 3, Last: 4}, res.Pagination)
+
+	_, _, err = c.SearchContext(ctx, "in:#nowhere", slack.SearchParameters{})
+	assert.ErrorContains(t, err, "not found")
+
+	// search.messages is never called
+	assert.Zero(t, countCalls(srv, "search.messages"))
+}
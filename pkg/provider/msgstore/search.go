@@ -0,0 +1,160 @@
+package msgstore
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"github.com/slack-go/slack"
+)
+
+// Search selects stored messages of a workspace. Empty fields don't filter.
+type Search struct {
+	// Words must all appear in the message, a trailing * matches a prefix.
+	// Results are ranked by relevance when set and newest first otherwise.
+	Words []string
+	// Channels limits the search to these channel IDs.
+	Channels []string
+	// Users limits the search to messages posted by these user IDs.
+	Users []string
+	// With limits the search to threads one of these users posted in and to
+	// the conversations in WithChannels, their DMs and group DMs.
+	With         []string
+	WithChannels []string
+	// Threads limits the search to thread parents and replies.
+	Threads bool
+	// Oldest (inclusive) and Latest (exclusive) are Slack timestamps.
+	Oldest string
+	Latest string
+
+	Offset int
+	Limit  int
+}
+
+// Hit is a message found by Search.
+type Hit struct {
+	Channel string
+	Message slack.Message
+}
+
+// Search runs q and returns a page of hits and the total number of matches.
+func (s *Store) Search(ctx context.Context, team string, q Search) ([]Hit, int, error) {
+	from := `messages m`
+	where := ` WHERE m.team = ?`
+	args := []any{team}
+	order := ` ORDER BY m.ts DESC`
+
+	if match := ftsQuery(q.Words); match != "" {
+		from += ` JOIN messages_fts f ON f.rowid = m.rowid`
+		where += ` AND messages_fts MATCH ?`
+		args = append(args, match)
+		order = ` ORDER BY f.rank, m.ts DESC`
+	} else if len(q.Words) > 0 {
+		// nothing searchable was left, e.g. only punctuation
+		return nil, 0, nil
+	}
+	if len(q.Channels) > 0 {
+		where += ` AND m.channel IN (` + placeholders(len(q.Channels)) + `)`
+		args = appendStrings(args, q.Channels)
+	}
+	if len(q.Users) > 0 {
+		where += ` AND m.user IN (` + placeholders(len(q.Users)) + `)`
+		args = appendStrings(args, q.Users)
+	}
+	if len(q.With) > 0 {
+		where += ` AND (m.thread_ts IN (SELECT thread_ts FROM messages WHERE team = ? AND thread_ts != '' AND user IN (` + placeholders(len(q.With)) + `))`
+		args = appendStrings(append(args, team), q.With)
+		if len(q.WithChannels) > 0 {
+			where += ` OR m.channel IN (` + placeholders(len(q.WithChannels)) + `)`
+			args = appendStrings(args, q.WithChannels)
+		}
+		where += `)`
+	}
+	if q.Threads {
+		where += ` AND m.thread_ts != ''`
+	}
+	if q.Oldest != "" {
+		where += ` AND m.ts >= ?`
+		args = append(args, q.Oldest)
+	}
+	if q.Latest != "" {
+		where += ` AND m.ts < ?`
+		args = append(args, q.Latest)
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+from+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT m.channel, m.data FROM ` + from + where + order
+	if q.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, q.Limit, q.Offset)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var hits []Hit
+	for rows.Next() {
+		var (
+			h    Hit
+			data []byte
+		)
+		if err := rows.Scan(&h.Channel, &data); err != nil {
+			return nil, 0, err
+		}
+		if err := json.Unmarshal(data, &h.Message); err != nil {
+			return nil, 0, err
+		}
+		hits = append(hits, h)
+	}
+	return hits, total, rows.Err()
+}
+
+// indexText is what the full-text index holds for a message: its text and
+// the text of its attachments and files.
+func indexText(m *slack.Message) string {
+	parts := []string{m.Text}
+	for _, a := range m.Attachments {
+		parts = append(parts, a.Pretext, a.Title, a.Text, a.Fallback)
+	}
+	for _, f := range m.Files {
+		parts = append(parts, f.Title, f.Name)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ftsQuery turns search words into an FTS5 query matching all of them. Every
+// word is quoted so FTS5 operators in it are searched for literally.
+func ftsQuery(words []string) string {
+	var terms []string
+	for _, w := range words {
+		prefix := strings.HasSuffix(w, "*")
+		w = strings.Trim(w, `*"`)
+		if !strings.ContainsFunc(w, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+			continue
+		}
+		term := `"` + strings.ReplaceAll(w, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " ")
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func appendStrings(args []any, values []string) []any {
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return args
+}
@@ -0,0 +1,122 @@
+package msgstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func posted(user string, m slack.Message) slack.Message {
+	m.User = user
+	return m
+}
+
+func hitTimestamps(hits []Hit) []string {
+	var out []string
+	for _, h := range hits {
+		out = append(out, h.Message.Timestamp)
+	}
+	return out
+}
+
+func TestUnitStoreSearch(t *testing.T) {
+	ctx := context.Background()
+	s, err := Open(filepath.Join(t.TempDir(), "messages.db"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Put(ctx, "T1", "C1", []slack.Message{
+		posted("U1", message("1700000001.000000", "", "", "Deploying the release today")),
+		posted("U2", message("1700000002.000000", "1700000002.000000", "", "release notes draft, release blockers listed")),
+		posted("U3", message("1700000003.000000", "1700000002.000000", "", "looks good")),
+		posted("U1", message("1700000004.000000", "", "", "lunch?")),
+	}))
+	require.NoError(t, s.Put(ctx, "T1", "D1", []slack.Message{
+		posted("U2", message("1700000005.000000", "", "", "the release is out")),
+	}))
+	require.NoError(t, s.Put(ctx, "T2", "C1", []slack.Message{
+		posted("U1", message("1700000006.000000", "", "", "release in the other workspace")),
+	}))
+
+	// the message mentioning the word twice ranks first, stemming matches "releases"
+	hits, total, err := s.Search(ctx, "T1", Search{Words: []string{"releases"}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, "1700000002.000000", hits[0].Message.Timestamp)
+	assert.ElementsMatch(t, []string{"1700000001.000000", "1700000002.000000", "1700000005.000000"}, hitTimestamps(hits))
+
+	hits, _, err = s.Search(ctx, "T1", Search{Words: []string{"deploy*"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1700000001.000000"}, hitTimestamps(hits))
+
+	// without words the newest come first
+	hits, total, err = s.Search(ctx, "T1", Search{Channels: []string{"C1"}, Offset: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []string{"1700000003.000000", "1700000002.000000"}, hitTimestamps(hits))
+
+	hits, _, err = s.Search(ctx, "T1", Search{Users: []string{"U1"}, Oldest: "1700000002.000000"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1700000004.000000"}, hitTimestamps(hits))
+
+	hits, _, err = s.Search(ctx, "T1", Search{Threads: true, Latest: "1700000003.000000"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1700000002.000000"}, hitTimestamps(hits))
+
+	// threads U3 posted in and the DM with them
+	hits, _, err = s.Search(ctx, "T1", Search{With: []string{"U3"}, WithChannels: []string{"D1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1700000005.000000", "1700000003.000000", "1700000002.000000"}, hitTimestamps(hits))
+
+	// FTS5 syntax is searched for literally, punctuation alone matches nothing
+	hits, total, err = s.Search(ctx, "T1", Search{Words: []string{`lunch"`, "OR"}})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, hits)
+	hits, _, err = s.Search(ctx, "T1", Search{Words: []string{"?"}})
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+
+	// a replaced message is reindexed
+	require.NoError(t, s.Put(ctx, "T1", "C1", []slack.Message{posted("U1", message("1700000004.000000", "", "", "dinner?"))}))
+	hits, _, err = s.Search(ctx, "T1", Search{Words: []string{"lunch"}})
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+	hits, _, err = s.Search(ctx, "T1", Search{Words: []string{"dinner"}})
+	require.NoError(t, err)
+	assert.Len(t, hits, 1)
+}
+
+func TestUnitStoreSearchMigration(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "messages.db")
+
+	// a database written before the search index existed
+	db, err := sql.Open("sqlite", "file:"+path)
+	require.NoError(t, err)
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, migrations[0](ctx, tx))
+	data, err := json.Marshal(posted("U1", message("1700000001.000000", "", "", "an old message")))
+	require.NoError(t, err)
+	_, err = tx.Exec(`INSERT INTO messages (team, channel, ts, data) VALUES ('T1', 'C1', '1700000001.000000', ?)`, data)
+	require.NoError(t, err)
+	_, err = tx.Exec(`PRAGMA user_version = 1`)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.NoError(t, db.Close())
+
+	s, err := Open(path)
+	require.NoError(t, err)
+	defer s.Close()
+	hits, _, err := s.Search(ctx, "T1", Search{Words: []string{"old"}, Users: []string{"U1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1700000001.000000"}, hitTimestamps(hits))
+}
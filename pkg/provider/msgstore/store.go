@@ -0,0 +1,321 @@
+// Package msgstore keeps Slack messages in a local SQLite database, keyed by
+// workspace, channel and ts, together with how far each channel and thread
+// has been synced.
+package msgstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/slack-go/slack"
+	_ "modernc.org/sqlite"
+)
+
+// migrations bring the database to the next version each, the version is
+// kept in PRAGMA user_version.
+var migrations = []func(ctx context.Context, tx *sql.Tx) error{
+	execMigration(`
+		CREATE TABLE IF NOT EXISTS messages (
+			team      TEXT NOT NULL,
+			channel   TEXT NOT NULL,
+			ts        TEXT NOT NULL,
+			thread_ts TEXT NOT NULL DEFAULT '',
+			subtype   TEXT NOT NULL DEFAULT '',
+			data      BLOB NOT NULL,
+			PRIMARY KEY (team, channel, ts)
+		);
+		CREATE INDEX IF NOT EXISTS messages_thread ON messages (team, channel, thread_ts, ts);
+		CREATE TABLE IF NOT EXISTS sync_state (
+			team      TEXT NOT NULL,
+			channel   TEXT NOT NULL,
+			thread_ts TEXT NOT NULL DEFAULT '',
+			oldest    TEXT NOT NULL,
+			latest    TEXT NOT NULL,
+			complete  INTEGER NOT NULL DEFAULT 0,
+			synced_at INTEGER NOT NULL,
+			PRIMARY KEY (team, channel, thread_ts)
+		);`),
+	addSearchIndex,
+}
+
+func execMigration(query string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query)
+		return err
+	}
+}
+
+// addSearchIndex adds the author column and the full-text index, and indexes
+// the messages stored so far.
+func addSearchIndex(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		ALTER TABLE messages ADD COLUMN user TEXT NOT NULL DEFAULT '';
+		CREATE INDEX messages_user ON messages (team, user);
+		CREATE VIRTUAL TABLE messages_fts USING fts5 (text, tokenize = 'porter unicode61');`); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT rowid, data FROM messages`)
+	if err != nil {
+		return err
+	}
+	type row struct {
+		id  int64
+		msg slack.Message
+	}
+	var all []row
+	for rows.Next() {
+		var (
+			r    row
+			data []byte
+		)
+		if err := rows.Scan(&r.id, &data); err != nil {
+			rows.Close()
+			return err
+		}
+		if err := json.Unmarshal(data, &r.msg); err != nil {
+			rows.Close()
+			return err
+		}
+		all = append(all, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range all {
+		if _, err := tx.ExecContext(ctx, `UPDATE messages SET user = ? WHERE rowid = ?`, r.msg.User, r.id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO messages_fts (rowid, text) VALUES (?, ?)`, r.id, indexText(&r.msg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Store is safe for concurrent use.
+type Store struct {
+	db *sql.DB
+}
+
+// Query selects messages by ts. Oldest and Latest are Slack timestamps, empty
+// means unbounded; Limit 0 returns everything.
+type Query struct {
+	Oldest    string
+	Latest    string
+	Inclusive bool
+	Limit     int
+}
+
+// State records which part of a channel or thread is stored without gaps:
+// every message with Oldest <= ts <= Latest. Complete means nothing older than
+// Oldest exists.
+type State struct {
+	Oldest   string
+	Latest   string
+	Complete bool
+	SyncedAt time.Time
+}
+
+// Open opens the database at path, creating it and its directory if needed.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer, serialising here avoids SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+	if err := migrate(context.Background(), db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise message store %s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	for ; version < len(migrations); version++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := migrations[version](ctx, tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, version+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put inserts msgs or replaces the stored copies.
+func (s *Store) Put(ctx context.Context, team, channel string, msgs []slack.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO messages (team, channel, ts, thread_ts, subtype, user, data) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (team, channel, ts) DO UPDATE SET
+			thread_ts = excluded.thread_ts, subtype = excluded.subtype, user = excluded.user, data = excluded.data
+		RETURNING rowid`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, m := range msgs {
+		if m.Timestamp == "" {
+			continue
+		}
+		data, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		var id int64
+		if err := stmt.QueryRowContext(ctx, team, channel, m.Timestamp, m.ThreadTimestamp, m.SubType, m.User, data).Scan(&id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM messages_fts WHERE rowid = ?`, id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO messages_fts (rowid, text) VALUES (?, ?)`, id, indexText(&m)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// History returns the messages shown in the channel itself, newest first:
+// top level messages, thread parents and replies broadcast to the channel.
+func (s *Store) History(ctx context.Context, team, channel string, q Query) ([]slack.Message, error) {
+	where, args := q.where()
+	query := `SELECT data FROM messages
+		WHERE team = ? AND channel = ? AND (thread_ts = '' OR thread_ts = ts OR subtype = 'thread_broadcast')` + where + `
+		ORDER BY ts DESC`
+	return s.query(ctx, query, q.Limit, append([]any{team, channel}, args...)...)
+}
+
+// Replies returns the replies of a thread, oldest first. The parent is not
+// included, see Message.
+func (s *Store) Replies(ctx context.Context, team, channel, threadTS string, q Query) ([]slack.Message, error) {
+	where, args := q.where()
+	query := `SELECT data FROM messages
+		WHERE team = ? AND channel = ? AND thread_ts = ? AND ts != thread_ts` + where + `
+		ORDER BY ts ASC`
+	return s.query(ctx, query, q.Limit, append([]any{team, channel, threadTS}, args...)...)
+}
+
+// Message returns a single message, or nil if it's not stored.
+func (s *Store) Message(ctx context.Context, team, channel, ts string) (*slack.Message, error) {
+	msgs, err := s.query(ctx, `SELECT data FROM messages WHERE team = ? AND channel = ? AND ts = ?`, 1, team, channel, ts)
+	if err != nil || len(msgs) == 0 {
+		return nil, err
+	}
+	return &msgs[0], nil
+}
+
+// State returns the sync state of a channel, or of a thread when threadTS is
+// set. It's nil if the channel or thread was never synced.
+func (s *Store) State(ctx context.Context, team, channel, threadTS string) (*State, error) {
+	var (
+		st       State
+		syncedAt int64
+	)
+	err := s.db.QueryRowContext(ctx, `
+		SELECT oldest, latest, complete, synced_at FROM sync_state
+		WHERE team = ? AND channel = ? AND thread_ts = ?`, team, channel, threadTS,
+	).Scan(&st.Oldest, &st.Latest, &st.Complete, &syncedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	st.SyncedAt = time.Unix(syncedAt, 0)
+	return &st, nil
+}
+
+func (s *Store) SetState(ctx context.Context, team, channel, threadTS string, st State) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO sync_state (team, channel, thread_ts, oldest, latest, complete, synced_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (team, channel, thread_ts) DO UPDATE SET
+			oldest = excluded.oldest, latest = excluded.latest, complete = excluded.complete, synced_at = excluded.synced_at`,
+		team, channel, threadTS, st.Oldest, st.Latest, st.Complete, st.SyncedAt.Unix())
+	return err
+}
+
+func (q Query) where() (string, []any) {
+	var (
+		where string
+		args  []any
+	)
+	lt, gt := " < ?", " > ?"
+	if q.Inclusive {
+		lt, gt = " <= ?", " >= ?"
+	}
+	if q.Oldest != "" {
+		where += " AND ts" + gt
+		args = append(args, q.Oldest)
+	}
+	if q.Latest != "" {
+		where += " AND ts" + lt
+		args = append(args, q.Latest)
+	}
+	return where, args
+}
+
+func (s *Store) query(ctx context.Context, query string, limit int, args ...any) ([]slack.Message, error) {
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var msgs []slack.Message
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var m slack.Message
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, rows.Err()
+}
@@ -0,0 +1,87 @@
+package msgstore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func message(ts, threadTS, subtype, text string) slack.Message {
+	var m slack.Message
+	m.Timestamp, m.ThreadTimestamp, m.SubType, m.Text = ts, threadTS, subtype, text
+	return m
+}
+
+func timestamps(msgs []slack.Message) []string {
+	var out []string
+	for _, m := range msgs {
+		out = append(out, m.Timestamp)
+	}
+	return out
+}
+
+func TestUnitStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache", "messages.db")
+	s, err := Open(path)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(ctx, "T1", "C1", []slack.Message{
+		message("1700000001.000000", "", "", "first"),
+		message("1700000002.000000", "1700000002.000000", "", "parent"),
+		message("1700000003.000000", "1700000002.000000", "", "reply"),
+		message("1700000004.000000", "1700000002.000000", "thread_broadcast", "broadcast"),
+		message("1700000005.000000", "", "", "last"),
+	}))
+	require.NoError(t, s.Put(ctx, "T2", "C1", []slack.Message{message("1700000006.000000", "", "", "other workspace")}))
+	// a second copy replaces the first
+	require.NoError(t, s.Put(ctx, "T1", "C1", []slack.Message{message("1700000005.000000", "", "", "edited")}))
+
+	history, err := s.History(ctx, "T1", "C1", Query{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1700000005.000000", "1700000004.000000", "1700000002.000000", "1700000001.000000"}, timestamps(history))
+	assert.Equal(t, "edited", history[0].Text)
+
+	history, err = s.History(ctx, "T1", "C1", Query{Oldest: "1700000002.000000", Latest: "1700000005.000000", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1700000004.000000"}, timestamps(history))
+
+	history, err = s.History(ctx, "T1", "C1", Query{Oldest: "1700000002.000000", Inclusive: true})
+	require.NoError(t, err)
+	assert.Len(t, history, 3)
+
+	replies, err := s.Replies(ctx, "T1", "C1", "1700000002.000000", Query{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1700000003.000000", "1700000004.000000"}, timestamps(replies))
+
+	m, err := s.Message(ctx, "T1", "C1", "1700000002.000000")
+	require.NoError(t, err)
+	assert.Equal(t, "parent", m.Text)
+	m, err = s.Message(ctx, "T1", "C2", "1700000002.000000")
+	require.NoError(t, err)
+	assert.Nil(t, m)
+
+	st, err := s.State(ctx, "T1", "C1", "")
+	require.NoError(t, err)
+	assert.Nil(t, st)
+
+	synced := time.Unix(1700000100, 0)
+	require.NoError(t, s.SetState(ctx, "T1", "C1", "", State{Oldest: "1700000001.000000", Latest: "1700000005.000000", Complete: true, SyncedAt: synced}))
+	require.NoError(t, s.Close())
+
+	// everything survives a restart
+	s, err = Open(path)
+	require.NoError(t, err)
+	defer s.Close()
+	st, err = s.State(ctx, "T1", "C1", "")
+	require.NoError(t, err)
+	assert.Equal(t, &State{Oldest: "1700000001.000000", Latest: "1700000005.000000", Complete: true, SyncedAt: synced}, st)
+	history, err = s.History(ctx, "T1", "C1", Query{})
+	require.NoError(t, err)
+	assert.Len(t, history, 4)
+}
@@ -0,0 +1,436 @@
+package provider
+
+import (
+	"context"
+	"io"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/slack-go/slack"
+)
+
+// rateLimitedClient wraps a SlackAPI and routes every call through the
+// per-method limiter of the Slack method it ends up calling.
+type rateLimitedClient struct {
+	next   SlackAPI
+	limits *limiter.Registry
+}
+
+func newRateLimitedClient(next SlackAPI, limits *limiter.Registry) SlackAPI {
+	return &rateLimitedClient{next: next, limits: limits}
+}
+
+func (c *rateLimitedClient) AuthTest() (res *slack.AuthTestResponse, err error) {
+	err = c.limits.Do(context.Background(), "auth.test", func() error {
+		res, err = c.next.AuthTest()
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) AuthTestContext(ctx context.Context) (res *slack.AuthTestResponse, err error) {
+	err = c.limits.Do(ctx, "auth.test", func() error {
+		res, err = c.next.AuthTestContext(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) (res []slack.User, err error) {
+	err = c.limits.Do(ctx, "users.list", func() error {
+		res, err = c.next.GetUsersContext(ctx, options...)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) GetUsersInfo(users ...string) (res *[]slack.User, err error) {
+	err = c.limits.Do(context.Background(), "users.info", func() error {
+		res, err = c.next.GetUsersInfo(users...)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) GetUserGroupsContext(ctx context.Context, options ...slack.GetUserGroupsOption) (res []slack.UserGroup, err error) {
+	err = c.limits.Do(ctx, "usergroups.list", func() error {
+		res, err = c.next.GetUserGroupsContext(ctx, options...)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) GetUserGroupMembersContext(ctx context.Context, userGroup string, options ...slack.GetUserGroupMembersOption) (res []string, err error) {
+	err = c.limits.Do(ctx, "usergroups.users.list", func() error {
+		res, err = c.next.GetUserGroupMembersContext(ctx, userGroup, options...)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) UpdateUserGroupMembersContext(ctx context.Context, userGroup string, members string, options ...slack.UpdateUserGroupMembersOption) (res slack.UserGroup, err error) {
+	err = c.limits.Do(ctx, "usergroups.users.update", func() error {
+		res, err = c.next.UpdateUserGroupMembersContext(ctx, userGroup, members, options...)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) PostMessageContext(ctx context.Context, channel string, options ...slack.MsgOption) (respChannel, respTs string, err error) {
+	err = c.limits.Do(ctx, "chat.postMessage", func() error {
+		respChannel, respTs, err = c.next.PostMessageContext(ctx, channel, options...)
+		return err
+	})
+	return respChannel, respTs, err
+}
+
+func (c *rateLimitedClient) MarkConversationContext(ctx context.Context, channel, ts string) error {
+	return c.limits.Do(ctx, "conversations.mark", func() error {
+		return c.next.MarkConversationContext(ctx, channel, ts)
+	})
+}
+
+func (c *rateLimitedClient) AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	return c.limits.Do(ctx, "reactions.add", func() error {
+		return c.next.AddReactionContext(ctx, name, item)
+	})
+}
+
+func (c *rateLimitedClient) RemoveReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	return c.limits.Do(ctx, "reactions.remove", func() error {
+		return c.next.RemoveReactionContext(ctx, name, item)
+	})
+}
+
+func (c *rateLimitedClient) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (res *slack.Channel, err error) {
+	err = c.limits.Do(ctx, "conversations.info", func() error {
+		res, err = c.next.GetConversationInfoContext(ctx, input)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) CreateConversationContext(ctx context.Context, params slack.CreateConversationParams) (res *slack.Channel, err error) {
+	err = c.limits.Do(ctx, "conversations.create", func() error {
+		res, err = c.next.CreateConversationContext(ctx, params)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) ArchiveConversationContext(ctx context.Context, channelID string) error {
+	return c.limits.Do(ctx, "conversations.archive", func() error {
+		return c.next.ArchiveConversationContext(ctx, channelID)
+	})
+}
+
+func (c *rateLimitedClient) UnArchiveConversationContext(ctx context.Context, channelID string) error {
+	return c.limits.Do(ctx, "conversations.unarchive", func() error {
+		return c.next.UnArchiveConversationContext(ctx, channelID)
+	})
+}
+
+func (c *rateLimitedClient) RenameConversationContext(ctx context.Context, channelID, channelName string) (res *slack.Channel, err error) {
+	err = c.limits.Do(ctx, "conversations.rename", func() error {
+		res, err = c.next.RenameConversationContext(ctx, channelID, channelName)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) SetTopicOfConversationContext(ctx context.Context, channelID, topic string) (res *slack.Channel, err error) {
+	err = c.limits.Do(ctx, "conversations.setTopic", func() error {
+		res, err = c.next.SetTopicOfConversationContext(ctx, channelID, topic)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) SetPurposeOfConversationContext(ctx context.Context, channelID, purpose string) (res *slack.Channel, err error) {
+	err = c.limits.Do(ctx, "conversations.setPurpose", func() error {
+		res, err = c.next.SetPurposeOfConversationContext(ctx, channelID, purpose)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) (ids []string, nextCursor string, err error) {
+	err = c.limits.Do(ctx, "conversations.members", func() error {
+		ids, nextCursor, err = c.next.GetUsersInConversationContext(ctx, params)
+		return err
+	})
+	return ids, nextCursor, err
+}
+
+func (c *rateLimitedClient) JoinConversationContext(ctx context.Context, channelID string) (res *slack.Channel, warning string, warnings []string, err error) {
+	err = c.limits.Do(ctx, "conversations.join", func() error {
+		res, warning, warnings, err = c.next.JoinConversationContext(ctx, channelID)
+		return err
+	})
+	return res, warning, warnings, err
+}
+
+func (c *rateLimitedClient) LeaveConversationContext(ctx context.Context, channelID string) (notInChannel bool, err error) {
+	err = c.limits.Do(ctx, "conversations.leave", func() error {
+		notInChannel, err = c.next.LeaveConversationContext(ctx, channelID)
+		return err
+	})
+	return notInChannel, err
+}
+
+func (c *rateLimitedClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (res *slack.Channel, err error) {
+	err = c.limits.Do(ctx, "conversations.invite", func() error {
+		res, err = c.next.InviteUsersToConversationContext(ctx, channelID, users...)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) KickUserFromConversationContext(ctx context.Context, channelID string, user string) error {
+	return c.limits.Do(ctx, "conversations.kick", func() error {
+		return c.next.KickUserFromConversationContext(ctx, channelID, user)
+	})
+}
+
+func (c *rateLimitedClient) ListPinsContext(ctx context.Context, channel string) (items []slack.Item, paging *slack.Paging, err error) {
+	err = c.limits.Do(ctx, "pins.list", func() error {
+		items, paging, err = c.next.ListPinsContext(ctx, channel)
+		return err
+	})
+	return items, paging, err
+}
+
+func (c *rateLimitedClient) ListBookmarksContext(ctx context.Context, channelID string) (res []slack.Bookmark, err error) {
+	err = c.limits.Do(ctx, "bookmarks.list", func() error {
+		res, err = c.next.ListBookmarksContext(ctx, channelID)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) AddPinContext(ctx context.Context, channel string, item slack.ItemRef) error {
+	return c.limits.Do(ctx, "pins.add", func() error {
+		return c.next.AddPinContext(ctx, channel, item)
+	})
+}
+
+func (c *rateLimitedClient) RemovePinContext(ctx context.Context, channel string, item slack.ItemRef) error {
+	return c.limits.Do(ctx, "pins.remove", func() error {
+		return c.next.RemovePinContext(ctx, channel, item)
+	})
+}
+
+func (c *rateLimitedClient) AddBookmarkContext(ctx context.Context, channelID string, params slack.AddBookmarkParameters) (res slack.Bookmark, err error) {
+	err = c.limits.Do(ctx, "bookmarks.add", func() error {
+		res, err = c.next.AddBookmarkContext(ctx, channelID, params)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) RemoveBookmarkContext(ctx context.Context, channelID, bookmarkID string) error {
+	return c.limits.Do(ctx, "bookmarks.remove", func() error {
+		return c.next.RemoveBookmarkContext(ctx, channelID, bookmarkID)
+	})
+}
+
+func (c *rateLimitedClient) AddUserReminderContext(ctx context.Context, userID, text, time string) (res *slack.Reminder, err error) {
+	err = c.limits.Do(ctx, "reminders.add", func() error {
+		res, err = c.next.AddUserReminderContext(ctx, userID, text, time)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) ListRemindersContext(ctx context.Context) (res []*slack.Reminder, err error) {
+	err = c.limits.Do(ctx, "reminders.list", func() error {
+		res, err = c.next.ListRemindersContext(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) CompleteReminderContext(ctx context.Context, reminderID string) error {
+	return c.limits.Do(ctx, "reminders.complete", func() error {
+		return c.next.CompleteReminderContext(ctx, reminderID)
+	})
+}
+
+func (c *rateLimitedClient) DeleteReminderContext(ctx context.Context, reminderID string) error {
+	return c.limits.Do(ctx, "reminders.delete", func() error {
+		return c.next.DeleteReminderContext(ctx, reminderID)
+	})
+}
+
+func (c *rateLimitedClient) GetUserPresenceContext(ctx context.Context, user string) (res *slack.UserPresence, err error) {
+	err = c.limits.Do(ctx, "users.getPresence", func() error {
+		res, err = c.next.GetUserPresenceContext(ctx, user)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) GetDNDTeamInfoContext(ctx context.Context, users []string) (res map[string]slack.DNDStatus, err error) {
+	err = c.limits.Do(ctx, "dnd.teamInfo", func() error {
+		res, err = c.next.GetDNDTeamInfoContext(ctx, users)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) SetUserCustomStatusContext(ctx context.Context, statusText, statusEmoji string, statusExpiration int64) error {
+	return c.limits.Do(ctx, "users.profile.set", func() error {
+		return c.next.SetUserCustomStatusContext(ctx, statusText, statusEmoji, statusExpiration)
+	})
+}
+
+func (c *rateLimitedClient) SetSnoozeContext(ctx context.Context, minutes int) (res *slack.DNDStatus, err error) {
+	err = c.limits.Do(ctx, "dnd.setSnooze", func() error {
+		res, err = c.next.SetSnoozeContext(ctx, minutes)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) EndSnoozeContext(ctx context.Context) (res *slack.DNDStatus, err error) {
+	err = c.limits.Do(ctx, "dnd.endSnooze", func() error {
+		res, err = c.next.EndSnoozeContext(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) GetUserProfileContext(ctx context.Context, userID string) (res *edge.UserProfile, err error) {
+	err = c.limits.Do(ctx, "users.profile.get", func() error {
+		res, err = c.next.GetUserProfileContext(ctx, userID)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) GetTeamProfileContext(ctx context.Context) (res *slack.TeamProfile, err error) {
+	err = c.limits.Do(ctx, "team.profile.get", func() error {
+		res, err = c.next.GetTeamProfileContext(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) GetOtherTeamInfoContext(ctx context.Context, team string) (res *slack.TeamInfo, err error) {
+	err = c.limits.Do(ctx, "team.info", func() error {
+		res, err = c.next.GetOtherTeamInfoContext(ctx, team)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (res *slack.GetConversationHistoryResponse, err error) {
+	err = c.limits.Do(ctx, "conversations.history", func() error {
+		res, err = c.next.GetConversationHistoryContext(ctx, params)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) GetConversationRepliesContext(ctx context.Context, params *slack.GetConversationRepliesParameters) (msgs []slack.Message, hasMore bool, nextCursor string, err error) {
+	err = c.limits.Do(ctx, "conversations.replies", func() error {
+		msgs, hasMore, nextCursor, err = c.next.GetConversationRepliesContext(ctx, params)
+		return err
+	})
+	return msgs, hasMore, nextCursor, err
+}
+
+func (c *rateLimitedClient) SearchContext(ctx context.Context, query string, params slack.SearchParameters) (msgs *slack.SearchMessages, files *slack.SearchFiles, err error) {
+	err = c.limits.Do(ctx, "search.messages", func() error {
+		msgs, files, err = c.next.SearchContext(ctx, query, params)
+		return err
+	})
+	return msgs, files, err
+}
+
+func (c *rateLimitedClient) GetFileInfoContext(ctx context.Context, fileID string, count, page int) (file *slack.File, comments []slack.Comment, paging *slack.Paging, err error) {
+	err = c.limits.Do(ctx, "files.info", func() error {
+		file, comments, paging, err = c.next.GetFileInfoContext(ctx, fileID, count, page)
+		return err
+	})
+	return file, comments, paging, err
+}
+
+func (c *rateLimitedClient) GetFileContext(ctx context.Context, downloadURL string, writer io.Writer) error {
+	// The download writes straight into writer, so a retry could duplicate
+	// partial content: only wait for the limiter here.
+	if err := c.limits.Wait(ctx, "files.download"); err != nil {
+		return err
+	}
+	return c.next.GetFileContext(ctx, downloadURL, writer)
+}
+
+func (c *rateLimitedClient) GetFilesContext(ctx context.Context, params slack.GetFilesParameters) (files []slack.File, paging *slack.Paging, err error) {
+	err = c.limits.Do(ctx, "files.list", func() error {
+		files, paging, err = c.next.GetFilesContext(ctx, params)
+		return err
+	})
+	return files, paging, err
+}
+
+func (c *rateLimitedClient) GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) (channels []slack.Channel, nextCursor string, err error) {
+	err = c.limits.Do(ctx, "conversations.list", func() error {
+		channels, nextCursor, err = c.next.GetConversationsContext(ctx, params)
+		return err
+	})
+	return channels, nextCursor, err
+}
+
+func (c *rateLimitedClient) CreateCanvasContext(ctx context.Context, title string, documentContent slack.DocumentContent) (id string, err error) {
+	err = c.limits.Do(ctx, "canvases.create", func() error {
+		id, err = c.next.CreateCanvasContext(ctx, title, documentContent)
+		return err
+	})
+	return id, err
+}
+
+func (c *rateLimitedClient) EditCanvasContext(ctx context.Context, params slack.EditCanvasParams) error {
+	return c.limits.Do(ctx, "canvases.edit", func() error {
+		return c.next.EditCanvasContext(ctx, params)
+	})
+}
+
+func (c *rateLimitedClient) LookupCanvasSectionsContext(ctx context.Context, params slack.LookupCanvasSectionsParams) (sections []slack.CanvasSection, err error) {
+	err = c.limits.Do(ctx, "canvases.sections.lookup", func() error {
+		sections, err = c.next.LookupCanvasSectionsContext(ctx, params)
+		return err
+	})
+	return sections, err
+}
+
+func (c *rateLimitedClient) ClientUserBoot(ctx context.Context) (res *edge.ClientUserBootResponse, err error) {
+	err = c.limits.Do(ctx, "client.userBoot", func() error {
+		res, err = c.next.ClientUserBoot(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) ClientCounts(ctx context.Context) (res edge.ClientCountsResponse, err error) {
+	err = c.limits.Do(ctx, "client.counts", func() error {
+		res, err = c.next.ClientCounts(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) ConversationsGenericInfo(ctx context.Context, channelIDs ...string) (res []slack.Channel, err error) {
+	err = c.limits.Do(ctx, "conversations.genericInfo", func() error {
+		res, err = c.next.ConversationsGenericInfo(ctx, channelIDs...)
+		return err
+	})
+	return res, err
+}
+
+func (c *rateLimitedClient) UsersSearch(ctx context.Context, query string, count int) (users []slack.User, err error) {
+	err = c.limits.Do(ctx, "users/search", func() error {
+		users, err = c.next.UsersSearch(ctx, query, count)
+		return err
+	})
+	return users, err
+}
@@ -0,0 +1,132 @@
+// Package searchquery parses the Slack search queries built by the
+// conversations_search_messages tool, for sources that search on their own
+// instead of calling search.messages.
+package searchquery
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Query is a parsed Slack search query. Only the modifiers the search tool
+// produces are understood; anything else is searched for as text.
+type Query struct {
+	Words  []string // lower case
+	Thread bool
+	In     []string
+	From   []string
+	With   []string
+	Before string
+	After  string
+	On     string
+	During string
+}
+
+func Parse(raw string) Query {
+	var q Query
+	for _, tok := range strings.Fields(raw) {
+		key, value, found := strings.Cut(tok, ":")
+		if !found || value == "" {
+			q.Words = append(q.Words, strings.ToLower(tok))
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "is":
+			if value == "thread" {
+				q.Thread = true
+			}
+		case "in":
+			q.In = append(q.In, value)
+		case "from":
+			q.From = append(q.From, value)
+		case "with":
+			q.With = append(q.With, value)
+		case "before":
+			q.Before = value
+		case "after":
+			q.After = value
+		case "on":
+			q.On = value
+		case "during":
+			q.During = value
+		default:
+			q.Words = append(q.Words, strings.ToLower(tok))
+		}
+	}
+	return q
+}
+
+// MatchDay applies the date modifiers, dates are compared as YYYY-MM-DD in
+// local time. during: also accepts a year or a month, e.g. 2024 or 2024-07.
+func (q *Query) MatchDay(ts string) bool {
+	if q.Before == "" && q.After == "" && q.On == "" && q.During == "" {
+		return true
+	}
+	sec, _, _ := strings.Cut(ts, ".")
+	var unix int64
+	if _, err := fmt.Sscan(sec, &unix); err != nil {
+		return false
+	}
+	day := time.Unix(unix, 0).Format("2006-01-02")
+
+	switch {
+	case q.On != "" && day != q.On:
+		return false
+	case q.During != "" && !strings.HasPrefix(day, q.During):
+		return false
+	case q.Before != "" && day >= q.Before:
+		return false
+	case q.After != "" && day <= q.After:
+		return false
+	}
+	return true
+}
+
+// Range returns the span of time the date modifiers allow in local time, from
+// inclusive and to exclusive. Zero times are unbounded.
+func (q *Query) Range() (from, to time.Time, err error) {
+	if q.On != "" {
+		if from, err = parseDay(q.On); err != nil {
+			return from, to, err
+		}
+		return from, from.AddDate(0, 0, 1), nil
+	}
+	if q.During != "" {
+		switch len(q.During) {
+		case len("2006"):
+			from, err = time.ParseInLocation("2006", q.During, time.Local)
+			to = from.AddDate(1, 0, 0)
+		case len("2006-01"):
+			from, err = time.ParseInLocation("2006-01", q.During, time.Local)
+			to = from.AddDate(0, 1, 0)
+		default:
+			from, err = parseDay(q.During)
+			to = from.AddDate(0, 0, 1)
+		}
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid during: date %q", q.During)
+		}
+		return from, to, nil
+	}
+	if q.After != "" {
+		if from, err = parseDay(q.After); err != nil {
+			return from, to, err
+		}
+		from = from.AddDate(0, 0, 1)
+	}
+	if q.Before != "" {
+		if to, err = parseDay(q.Before); err != nil {
+			return from, to, err
+		}
+	}
+	return from, to, nil
+}
+
+func parseDay(s string) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return t, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", s)
+	}
+	return t, nil
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/korotovsky/slack-mcp-server/pkg/provider/lists"
 	"github.com/slack-go/slack"
 	"go.uber.org/zap"
-	"golang.org/x/time/rate"
 )
 
 // NewTestProvider creates an ApiProvider with a mock SlackAPI for unit testing.
@@ -17,7 +16,6 @@ func NewTestProvider(client SlackAPI, logger *zap.Logger) *ApiProvider {
 		client:        client,
 		listsClient:   nil,
 		logger:        logger,
-		rateLimiter:   rate.NewLimiter(rate.Inf, 0),
 		usersReady:    true,
 		channelsReady: true,
 	}
@@ -0,0 +1,132 @@
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Policy describes how failed Slack calls are retried.
+type Policy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry, it doubles on every attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. Retry-After values above it are not waited
+	// for, the error is handed back to the caller instead.
+	MaxDelay time.Duration
+	// Budget limits retries across all calls sharing it, nil means unlimited.
+	Budget *Budget
+
+	jitter func() float64
+}
+
+// DefaultBudget is shared by all HTTP clients, so a Slack outage can't
+// multiply traffic through retries. The retry transport is the only layer
+// that retries, the per-method limiters only pace calls.
+var DefaultBudget = NewBudget(20, 0.1)
+
+// DefaultPolicy is the policy used for every Slack client.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   60 * time.Second,
+		Budget:     DefaultBudget,
+	}
+}
+
+// Backoff returns how long to wait before retry number attempt (starting at
+// 0). It uses exponential backoff with jitter between half and the full delay,
+// and never waits less than the server's Retry-After.
+func (p Policy) Backoff(attempt int, retryAfter time.Duration) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	jitter := p.jitter
+	if jitter == nil {
+		jitter = rand.Float64
+	}
+	delay = delay/2 + time.Duration(jitter()*float64(delay/2))
+
+	if retryAfter > delay {
+		return retryAfter
+	}
+	return delay
+}
+
+// Allow reports whether another retry may be made and takes it from the budget.
+func (p Policy) Allow(attempt int, retryAfter time.Duration) bool {
+	if attempt >= p.MaxRetries || retryAfter > p.MaxDelay {
+		return false
+	}
+	return p.Budget.Withdraw()
+}
+
+// Succeeded returns a fraction of a retry to the budget.
+func (p Policy) Succeeded() {
+	p.Budget.Deposit()
+}
+
+// Sleep waits for d or until ctx is done, whichever happens first.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Budget is a token bucket for retries: every retry costs one token and
+// every successful call earns ratio tokens back, up to max. When Slack keeps
+// failing the budget runs dry and calls fail fast instead of piling up.
+type Budget struct {
+	mu     sync.Mutex
+	tokens float64
+	max    float64
+	ratio  float64
+}
+
+func NewBudget(max int, ratio float64) *Budget {
+	return &Budget{
+		tokens: float64(max),
+		max:    float64(max),
+		ratio:  ratio,
+	}
+}
+
+// Withdraw takes one retry from the budget, a nil budget always allows it.
+func (b *Budget) Withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Deposit credits a successful call.
+func (b *Budget) Deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += b.ratio
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+}
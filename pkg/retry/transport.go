@@ -0,0 +1,162 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// writeVerbs are the actions of Slack methods that change state, e.g.
+// chat.postMessage or reactions.add. Such calls are only retried when Slack
+// certainly did not process them.
+var writeVerbs = []string{
+	"post", "update", "delete", "create", "edit", "add", "remove", "upload",
+	"invite", "kick", "archive", "unarchive", "rename", "join", "leave",
+	"open", "close", "set", "end", "complete", "enable", "disable",
+	"memessage", "schedule", "share",
+}
+
+// Transport retries transient failures of the wrapped RoundTripper according
+// to a Policy:
+//   - 429 responses are retried for every method, Slack did not process them;
+//   - 5xx responses and network errors are retried for read methods only;
+//   - writes are retried after network errors only if the connection was
+//     never established.
+type Transport struct {
+	next   http.RoundTripper
+	policy Policy
+	logger *zap.Logger
+}
+
+func NewTransport(next http.RoundTripper, policy Policy, logger *zap.Logger) *Transport {
+	return &Transport{next: next, policy: policy, logger: logger}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	idempotent := IsIdempotent(req)
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 {
+			r = req.Clone(ctx)
+			if req.Body != nil && req.Body != http.NoBody {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r.Body = body
+			}
+		}
+
+		resp, err := t.next.RoundTrip(r)
+
+		retryAfter, retryable := shouldRetry(ctx, resp, err, idempotent)
+		if !retryable {
+			if err == nil && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+				t.policy.Succeeded()
+			}
+			return resp, err
+		}
+		if (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) || !t.policy.Allow(attempt, retryAfter) {
+			return resp, err
+		}
+
+		wait := t.policy.Backoff(attempt, retryAfter)
+		fields := []zap.Field{
+			zap.String("method", slackMethod(req)),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("wait", wait),
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		} else {
+			fields = append(fields, zap.Int("status", resp.StatusCode))
+			drain(resp)
+		}
+		t.logger.Warn("Slack request failed, retrying", fields...)
+
+		if err := Sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func shouldRetry(ctx context.Context, resp *http.Response, err error, idempotent bool) (time.Duration, bool) {
+	if ctx.Err() != nil {
+		return 0, false
+	}
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+		return 0, idempotent || isDialError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return parseRetryAfter(resp), true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return parseRetryAfter(resp), idempotent
+	}
+	return 0, false
+}
+
+// isDialError reports whether the request failed before a connection was made.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+func parseRetryAfter(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After")))
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+func slackMethod(req *http.Request) string {
+	return path.Base(req.URL.Path)
+}
+
+// IsIdempotent reports whether a request can be sent twice without side
+// effects. Slack Web API calls are POSTs, so the method name decides.
+func IsIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return !IsWriteMethod(slackMethod(req))
+}
+
+// IsWriteMethod reports whether a Slack method name such as chat.postMessage
+// changes state.
+func IsWriteMethod(method string) bool {
+	action := strings.ToLower(method[strings.LastIndex(method, ".")+1:])
+	for _, verb := range writeVerbs {
+		if strings.HasPrefix(action, verb) {
+			return true
+		}
+	}
+	return false
+}
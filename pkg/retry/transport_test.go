@@ -0,0 +1,223 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testPolicy() Policy {
+	return Policy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second}
+}
+
+// flakyServer fails the first n requests with the given status (or by
+// dropping the connection when status is 0), then answers {"ok":true}.
+func flakyServer(t *testing.T, n int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "channel=C1", string(body), "body must be replayed on every attempt")
+
+		if calls.Add(1) <= n {
+			if status == 0 {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+				conn.Close()
+				return
+			}
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func post(t *testing.T, client *http.Client, ctx context.Context, rawURL string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, strings.NewReader(url.Values{"channel": {"C1"}}.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return client.Do(req)
+}
+
+func newClient(policy Policy) *http.Client {
+	return &http.Client{Transport: NewTransport(http.DefaultTransport, policy, zap.NewNop())}
+}
+
+func TestUnitTransportRetriesReads(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"rate limited", http.StatusTooManyRequests},
+		{"server error", http.StatusInternalServerError},
+		{"bad gateway", http.StatusBadGateway},
+		{"unavailable", http.StatusServiceUnavailable},
+		{"connection dropped", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := flakyServer(t, 2, tt.status, nil)
+
+			resp, err := post(t, newClient(testPolicy()), context.Background(), srv.URL+"/api/conversations.history")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, int32(3), calls.Load())
+		})
+	}
+}
+
+func TestUnitTransportWrites(t *testing.T) {
+	t.Run("server errors are not retried", func(t *testing.T) {
+		srv, calls := flakyServer(t, 1, http.StatusInternalServerError, nil)
+
+		resp, err := post(t, newClient(testPolicy()), context.Background(), srv.URL+"/api/chat.postMessage")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("dropped connections are not retried", func(t *testing.T) {
+		srv, calls := flakyServer(t, 1, 0, nil)
+
+		_, err := post(t, newClient(testPolicy()), context.Background(), srv.URL+"/api/reactions.add")
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("rate limits are retried", func(t *testing.T) {
+		srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, nil)
+
+		resp, err := post(t, newClient(testPolicy()), context.Background(), srv.URL+"/api/chat.postMessage")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("dial errors are retried", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := ln.Addr().String()
+		ln.Close()
+
+		var attempts atomic.Int32
+		rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts.Add(1)
+			return http.DefaultTransport.RoundTrip(req)
+		})
+		client := &http.Client{Transport: NewTransport(rt, testPolicy(), zap.NewNop())}
+
+		_, err = post(t, client, context.Background(), "http://"+addr+"/api/chat.postMessage")
+		require.Error(t, err)
+		assert.Equal(t, int32(4), attempts.Load())
+	})
+}
+
+func TestUnitTransportGivesUp(t *testing.T) {
+	t.Run("after max retries", func(t *testing.T) {
+		srv, calls := flakyServer(t, 100, http.StatusServiceUnavailable, nil)
+
+		resp, err := post(t, newClient(testPolicy()), context.Background(), srv.URL+"/api/conversations.history")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, int32(4), calls.Load())
+	})
+
+	t.Run("when retry-after exceeds the max delay", func(t *testing.T) {
+		srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"600"}})
+
+		resp, err := post(t, newClient(testPolicy()), context.Background(), srv.URL+"/api/conversations.history")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("when the budget is exhausted", func(t *testing.T) {
+		srv, calls := flakyServer(t, 100, http.StatusServiceUnavailable, nil)
+
+		policy := testPolicy()
+		policy.Budget = NewBudget(2, 0.1)
+
+		resp, err := post(t, newClient(policy), context.Background(), srv.URL+"/api/conversations.history")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, int32(3), calls.Load())
+
+		resp, err = post(t, newClient(policy), context.Background(), srv.URL+"/api/conversations.history")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, int32(4), calls.Load())
+	})
+
+	t.Run("when the context is cancelled while waiting", func(t *testing.T) {
+		srv, calls := flakyServer(t, 100, http.StatusTooManyRequests, http.Header{"Retry-After": {"3"}})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := post(t, newClient(testPolicy()), ctx, srv.URL+"/api/conversations.history")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestUnitPolicyBackoff(t *testing.T) {
+	p := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, jitter: func() float64 { return 1 }}
+
+	assert.Equal(t, 100*time.Millisecond, p.Backoff(0, 0))
+	assert.Equal(t, 400*time.Millisecond, p.Backoff(2, 0))
+	assert.Equal(t, time.Second, p.Backoff(10, 0))
+	assert.Equal(t, 3*time.Second, p.Backoff(0, 3*time.Second))
+
+	p.jitter = func() float64 { return 0 }
+	assert.Equal(t, 50*time.Millisecond, p.Backoff(0, 0))
+}
+
+func TestUnitIsWriteMethod(t *testing.T) {
+	writes := []string{"chat.postMessage", "reactions.add", "reactions.remove", "canvases.edit", "slackLists.items.create", "users.profile.set", "dnd.setSnooze"}
+	reads := []string{"conversations.history", "conversations.replies", "conversations.members", "search.messages", "users.info", "client.userBoot", "search"}
+
+	for _, m := range writes {
+		assert.True(t, IsWriteMethod(m), m)
+	}
+	for _, m := range reads {
+		assert.False(t, IsWriteMethod(m), m)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUnitArchiveServer(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"users.json":              `[{"id": "U001", "name": "alice", "real_name": "Alice Archer"}]`,
+		"channels.json":           `[{"id": "C001", "name": "general", "name_normalized": "general", "created": 1700000000}]`,
+		"general/2024-01-15.json": `[{"type": "message", "user": "U001", "text": "Archived hello", "ts": "1705312800.000100"}]`,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	t.Setenv("SLACK_MCP_ADD_MESSAGE_TOOL", "true")
+
+	ctx := context.Background()
+	logger := zap.NewNop()
+	p, err := provider.NewArchive("stdio", dir, logger)
+	require.NoError(t, err)
+	require.NoError(t, p.RefreshUsers(ctx))
+	require.NoError(t, p.RefreshChannels(ctx))
+
+	c, err := client.NewInProcessClient(NewMCPServer(p, logger).server)
+	require.NoError(t, err)
+	require.NoError(t, c.Start(ctx))
+	defer c.Close()
+	_, err = c.Initialize(ctx, mcp.InitializeRequest{})
+	require.NoError(t, err)
+
+	tools, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	assert.Contains(t, names, "conversations_history")
+	assert.Contains(t, names, "conversations_search_messages")
+	for _, name := range []string{"conversations_add_message", "reactions_add", "canvases_create", "lists_get_items"} {
+		assert.NotContains(t, names, name)
+	}
+
+	history := callTool(t, c, "conversations_history", map[string]any{"channel_id": "#general", "limit": "10"})
+	assert.Contains(t, history, "Archived hello")
+
+	found := callTool(t, c, "conversations_search_messages", map[string]any{"search_query": "hello", "filter_in_channel": "#general"})
+	assert.Contains(t, found, "Archived hello")
+}
@@ -21,6 +21,12 @@ func withAuthKey(ctx context.Context, auth string) context.Context {
 	return context.WithValue(ctx, authKey{}, auth)
 }
 
+// TokenFromContext returns the raw Authorization value stored by AuthFromRequest.
+func TokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(authKey{}).(string)
+	return token, ok && token != ""
+}
+
 // Authenticate checks if the request is authenticated based on the provided context.
 func validateToken(ctx context.Context, logger *zap.Logger) (bool, error) {
 	// no configured token means no authentication
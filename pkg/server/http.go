@@ -0,0 +1,170 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	corsAllowMethods  = "GET, POST, DELETE, OPTIONS"
+	corsAllowHeaders  = "Authorization, Content-Type, Accept, Last-Event-ID, Mcp-Session-Id, Mcp-Protocol-Version"
+	corsExposeHeaders = "Mcp-Session-Id"
+	corsMaxAge        = "600"
+)
+
+// HTTPConfig describes how the SSE and HTTP transports are exposed to clients.
+type HTTPConfig struct {
+	// BaseURL is the public URL clients reach the server on, e.g. behind a
+	// reverse proxy. It is advertised in the SSE endpoint event.
+	BaseURL string
+	// BasePath prefixes every endpoint, e.g. /slack serves /slack/sse and /slack/mcp.
+	BasePath string
+	// AllowedOrigins lists browser origins allowed to call the server, "*" allows any.
+	// Localhost origins and the origin of BaseURL are always allowed.
+	AllowedOrigins []string
+}
+
+// HTTPConfigFromEnv reads settings from SLACK_MCP_BASE_URL, SLACK_MCP_BASE_PATH
+// and SLACK_MCP_ALLOWED_ORIGINS.
+func HTTPConfigFromEnv() HTTPConfig {
+	cfg := HTTPConfig{
+		BaseURL:  strings.TrimRight(strings.TrimSpace(os.Getenv("SLACK_MCP_BASE_URL")), "/"),
+		BasePath: normalizeBasePath(os.Getenv("SLACK_MCP_BASE_PATH")),
+	}
+	for _, origin := range strings.Split(os.Getenv("SLACK_MCP_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			cfg.AllowedOrigins = append(cfg.AllowedOrigins, origin)
+		}
+	}
+	return cfg
+}
+
+func normalizeBasePath(p string) string {
+	p = strings.Trim(strings.TrimSpace(p), "/")
+	if p == "" {
+		return ""
+	}
+	return "/" + p
+}
+
+func (c HTTPConfig) Validate() error {
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil {
+			return fmt.Errorf("invalid SLACK_MCP_BASE_URL: %w", err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("SLACK_MCP_BASE_URL must be an absolute http or https URL")
+		}
+		if u.RawQuery != "" || u.Fragment != "" {
+			return errors.New("SLACK_MCP_BASE_URL must not contain a query or fragment")
+		}
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return fmt.Errorf("invalid origin %q in SLACK_MCP_ALLOWED_ORIGINS, expected scheme://host[:port]", origin)
+		}
+	}
+	return nil
+}
+
+// WithDefaultBaseURL fills BaseURL from the address the server listens on
+// when no public URL was configured.
+func (c HTTPConfig) WithDefaultBaseURL(l ListenerConfig, host, port string) HTTPConfig {
+	if c.BaseURL != "" {
+		return c
+	}
+	if l.UnixSocket != "" {
+		c.BaseURL = l.Scheme() + "://localhost"
+		return c
+	}
+
+	host = strings.Trim(host, "[]")
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	c.BaseURL = fmt.Sprintf("%s://%s", l.Scheme(), net.JoinHostPort(host, port))
+	return c
+}
+
+// EndpointURL returns the public URL of an endpoint path such as /sse or /mcp.
+func (c HTTPConfig) EndpointURL(endpoint string) string {
+	return c.BaseURL + c.BasePath + endpoint
+}
+
+func (c HTTPConfig) originAllowed(origin string) bool {
+	origin = strings.TrimRight(origin, "/")
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	// Local tools and browsers on the same machine are always trusted.
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+
+	if c.BaseURL != "" {
+		if b, err := url.Parse(c.BaseURL); err == nil && strings.EqualFold(b.Scheme+"://"+b.Host, u.Scheme+"://"+u.Host) {
+			return true
+		}
+	}
+
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// originMiddleware rejects browser requests from origins that are not
+// allowed, which protects locally bound servers from DNS rebinding, and
+// answers CORS preflight requests for allowed ones. Requests without an
+// Origin header come from non-browser clients and pass through untouched.
+func originMiddleware(cfg HTTPConfig, logger *zap.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !cfg.originAllowed(origin) {
+			logger.Warn("Rejected request from disallowed origin",
+				zap.String("origin", origin),
+				zap.String("path", r.URL.Path),
+				zap.String("remote_addr", r.RemoteAddr),
+			)
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		h := w.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Add("Vary", "Origin")
+		h.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", corsAllowMethods)
+			h.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			h.Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestUnitHTTPConfigFromEnv(t *testing.T) {
+	t.Setenv("SLACK_MCP_BASE_URL", "https://mcp.example.com/")
+	t.Setenv("SLACK_MCP_BASE_PATH", "slack/")
+	t.Setenv("SLACK_MCP_ALLOWED_ORIGINS", " https://app.example.com/ , ,https://other.example.com")
+
+	cfg := HTTPConfigFromEnv()
+	assert.Equal(t, "https://mcp.example.com", cfg.BaseURL)
+	assert.Equal(t, "/slack", cfg.BasePath)
+	assert.Equal(t, []string{"https://app.example.com", "https://other.example.com"}, cfg.AllowedOrigins)
+	assert.Equal(t, "https://mcp.example.com/slack/sse", cfg.EndpointURL("/sse"))
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestUnitHTTPConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     HTTPConfig
+		wantErr bool
+	}{
+		{"empty", HTTPConfig{}, false},
+		{"base url with path", HTTPConfig{BaseURL: "https://example.com/proxy"}, false},
+		{"wildcard origin", HTTPConfig{AllowedOrigins: []string{"*"}}, false},
+		{"relative base url", HTTPConfig{BaseURL: "example.com"}, true},
+		{"ftp base url", HTTPConfig{BaseURL: "ftp://example.com"}, true},
+		{"base url with query", HTTPConfig{BaseURL: "https://example.com/?a=b"}, true},
+		{"origin without scheme", HTTPConfig{AllowedOrigins: []string{"example.com"}}, true},
+		{"origin with path", HTTPConfig{AllowedOrigins: []string{"https://example.com/app"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUnitHTTPConfigWithDefaultBaseURL(t *testing.T) {
+	tls := ListenerConfig{TLSCertFile: "a", TLSKeyFile: "b"}
+
+	assert.Equal(t, "http://127.0.0.1:13080", HTTPConfig{}.WithDefaultBaseURL(ListenerConfig{}, "127.0.0.1", "13080").BaseURL)
+	assert.Equal(t, "https://localhost:8443", HTTPConfig{}.WithDefaultBaseURL(tls, "0.0.0.0", "8443").BaseURL)
+	assert.Equal(t, "http://[::1]:13080", HTTPConfig{}.WithDefaultBaseURL(ListenerConfig{}, "::1", "13080").BaseURL)
+	assert.Equal(t, "http://localhost", HTTPConfig{}.WithDefaultBaseURL(ListenerConfig{UnixSocket: "/tmp/mcp.sock"}, "", "").BaseURL)
+	assert.Equal(t, "https://mcp.example.com", HTTPConfig{BaseURL: "https://mcp.example.com"}.WithDefaultBaseURL(ListenerConfig{}, "127.0.0.1", "13080").BaseURL)
+}
+
+func TestUnitOriginMiddleware(t *testing.T) {
+	cfg := HTTPConfig{
+		BaseURL:        "https://mcp.example.com",
+		AllowedOrigins: []string{"https://app.example.com"},
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := originMiddleware(cfg, zap.NewNop(), next)
+
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		wantStatus int
+		wantCORS   bool
+	}{
+		{"no origin", http.MethodPost, "", http.StatusOK, false},
+		{"localhost origin", http.MethodPost, "http://localhost:6274", http.StatusOK, true},
+		{"base url origin", http.MethodPost, "https://mcp.example.com", http.StatusOK, true},
+		{"allowlisted origin", http.MethodPost, "https://app.example.com", http.StatusOK, true},
+		{"rebinding origin", http.MethodPost, "http://attacker.example.net", http.StatusForbidden, false},
+		{"preflight allowed", http.MethodOptions, "https://app.example.com", http.StatusNoContent, true},
+		{"preflight rejected", http.MethodOptions, "http://attacker.example.net", http.StatusForbidden, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/mcp", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantCORS {
+				assert.Equal(t, tt.origin, rec.Header().Get("Access-Control-Allow-Origin"))
+			} else {
+				assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+			}
+			if tt.wantStatus == http.StatusNoContent {
+				assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "Mcp-Session-Id")
+			}
+		})
+	}
+
+	wildcard := originMiddleware(HTTPConfig{AllowedOrigins: []string{"*"}}, zap.NewNop(), next)
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Origin", "https://anything.example.org")
+	rec := httptest.NewRecorder()
+	wildcard.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
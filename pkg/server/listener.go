@@ -0,0 +1,235 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"go.uber.org/zap"
+)
+
+// ListenerConfig describes how the SSE and HTTP transports accept connections.
+type ListenerConfig struct {
+	// UnixSocket is a path to a Unix domain socket, when set TCP is not used.
+	UnixSocket string
+	// TLSCertFile and TLSKeyFile enable native TLS, both are reloaded on change.
+	TLSCertFile string
+	TLSKeyFile  string
+	// ClientCAFile enables mTLS, client certificates must be signed by one of these CAs.
+	ClientCAFile string
+}
+
+// ListenerConfigFromEnv reads listener settings from SLACK_MCP_UNIX_SOCKET,
+// SLACK_MCP_TLS_CERT, SLACK_MCP_TLS_KEY and SLACK_MCP_TLS_CLIENT_CA.
+func ListenerConfigFromEnv() ListenerConfig {
+	return ListenerConfig{
+		UnixSocket:   os.Getenv("SLACK_MCP_UNIX_SOCKET"),
+		TLSCertFile:  os.Getenv("SLACK_MCP_TLS_CERT"),
+		TLSKeyFile:   os.Getenv("SLACK_MCP_TLS_KEY"),
+		ClientCAFile: os.Getenv("SLACK_MCP_TLS_CLIENT_CA"),
+	}
+}
+
+func (c ListenerConfig) TLSEnabled() bool {
+	return c.TLSCertFile != "" || c.TLSKeyFile != ""
+}
+
+func (c ListenerConfig) Scheme() string {
+	if c.TLSEnabled() {
+		return "https"
+	}
+	return "http"
+}
+
+func (c ListenerConfig) Validate() error {
+	if c.TLSEnabled() && (c.TLSCertFile == "" || c.TLSKeyFile == "") {
+		return errors.New("both SLACK_MCP_TLS_CERT and SLACK_MCP_TLS_KEY must be set to enable TLS")
+	}
+	if c.ClientCAFile != "" && !c.TLSEnabled() {
+		return errors.New("SLACK_MCP_TLS_CLIENT_CA requires SLACK_MCP_TLS_CERT and SLACK_MCP_TLS_KEY")
+	}
+	return nil
+}
+
+// newListener opens a TCP or Unix socket listener and wraps it with TLS if configured.
+func newListener(addr string, cfg ListenerConfig, logger *zap.Logger) (net.Listener, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		ln  net.Listener
+		err error
+	)
+	if cfg.UnixSocket != "" {
+		// A stale socket file left after an unclean shutdown prevents binding.
+		if fi, statErr := os.Lstat(cfg.UnixSocket); statErr == nil && fi.Mode()&os.ModeSocket != 0 {
+			if err := os.Remove(cfg.UnixSocket); err != nil {
+				return nil, fmt.Errorf("failed to remove stale unix socket %q: %w", cfg.UnixSocket, err)
+			}
+		}
+		ln, err = net.Listen("unix", cfg.UnixSocket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on unix socket %q: %w", cfg.UnixSocket, err)
+		}
+		if err := os.Chmod(cfg.UnixSocket, 0660); err != nil {
+			ln.Close()
+			return nil, fmt.Errorf("failed to set permissions on unix socket %q: %w", cfg.UnixSocket, err)
+		}
+	} else {
+		ln, err = net.Listen("tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %q: %w", addr, err)
+		}
+	}
+
+	if !cfg.TLSEnabled() {
+		return ln, nil
+	}
+
+	tlsConfig, err := newServerTLSConfig(cfg, logger)
+	if err != nil {
+		ln.Close()
+		return nil, err
+	}
+
+	return tls.NewListener(ln, tlsConfig), nil
+}
+
+func newServerTLSConfig(cfg ListenerConfig, logger *zap.Logger) (*tls.Config, error) {
+	reloader, err := newCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file %q: %w", cfg.ClientCAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if ok := pool.AppendCertsFromPEM(pem); !ok {
+			return nil, fmt.Errorf("no certificates found in client CA file %q", cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+
+		logger.Info("mTLS client certificate verification enabled",
+			zap.String("context", "console"),
+			zap.String("client_ca", cfg.ClientCAFile),
+		)
+	}
+
+	return tlsConfig, nil
+}
+
+// certReloader serves a certificate pair and reloads it from disk when
+// either file's modification time changes, so rotated certificates are
+// picked up without a restart.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	logger   *zap.Logger
+
+	mu          sync.RWMutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func newCertReloader(certFile, keyFile string, logger *zap.Logger) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		logger:   logger,
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) reload() error {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return fmt.Errorf("failed to stat TLS certificate %q: %w", r.certFile, err)
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to stat TLS key %q: %w", r.keyFile, err)
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+
+	var chain []*x509.Certificate
+	for _, der := range cert.Certificate {
+		if c, err := x509.ParseCertificate(der); err == nil {
+			chain = append(chain, c)
+		}
+	}
+	if len(chain) > 0 {
+		cert.Leaf = chain[0]
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.certModTime = certInfo.ModTime()
+	r.keyModTime = keyInfo.ModTime()
+	r.mu.Unlock()
+
+	r.logger.Info("Loaded TLS certificate",
+		zap.String("context", "console"),
+		zap.String("cert_file", r.certFile),
+		zap.String("certificates", text.HumanizeCertificates(chain)),
+	)
+
+	return nil
+}
+
+func (r *certReloader) changed() bool {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return false
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return !certInfo.ModTime().Equal(r.certModTime) || !keyInfo.ModTime().Equal(r.keyModTime)
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if r.changed() {
+		// Keep serving the previous certificate if the new pair is
+		// half-written or otherwise invalid.
+		if err := r.reload(); err != nil {
+			r.logger.Error("Failed to reload TLS certificate, keeping the previous one",
+				zap.String("cert_file", r.certFile),
+				zap.Error(err),
+			)
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func writeSelfSignedPair(t *testing.T, dir, cn string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return certFile, keyFile
+}
+
+func TestUnitListenerConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ListenerConfig
+		wantErr bool
+	}{
+		{"plain tcp", ListenerConfig{}, false},
+		{"unix socket", ListenerConfig{UnixSocket: "/tmp/mcp.sock"}, false},
+		{"tls", ListenerConfig{TLSCertFile: "a", TLSKeyFile: "b"}, false},
+		{"mtls", ListenerConfig{TLSCertFile: "a", TLSKeyFile: "b", ClientCAFile: "c"}, false},
+		{"cert without key", ListenerConfig{TLSCertFile: "a"}, true},
+		{"key without cert", ListenerConfig{TLSKeyFile: "b"}, true},
+		{"client ca without tls", ListenerConfig{ClientCAFile: "c"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUnitCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedPair(t, dir, "first")
+
+	r, err := newCertReloader(certFile, keyFile, zap.NewNop())
+	require.NoError(t, err)
+
+	cert, err := r.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "first", cert.Leaf.Subject.CommonName)
+
+	writeSelfSignedPair(t, dir, "second")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	require.NoError(t, os.Chtimes(keyFile, future, future))
+
+	cert, err = r.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "second", cert.Leaf.Subject.CommonName)
+
+	// A broken pair must not replace the certificate being served.
+	require.NoError(t, os.WriteFile(certFile, []byte("garbage"), 0600))
+	later := future.Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, later, later))
+
+	cert, err = r.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "second", cert.Leaf.Subject.CommonName)
+}
+
+func TestUnitNewListenerUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "mcp.sock")
+
+	ln, err := newListener("", ListenerConfig{UnixSocket: socket}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "unix", ln.Addr().Network())
+
+	conn, err := net.Dial("unix", socket)
+	require.NoError(t, err)
+	conn.Close()
+	ln.Close()
+}
@@ -0,0 +1,652 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/test/fakeslack"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	slackapi "github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newOfflineClient starts the whole server against a fake Slack workspace and
+// returns an MCP client connected to it in-process.
+func newOfflineClient(t *testing.T) (*client.Client, *fakeslack.Server) {
+	t.Helper()
+
+	slack := fakeslack.New()
+	t.Cleanup(slack.Close)
+	return newOfflineClientFor(t, slack), slack
+}
+
+// newOfflineClientFor is newOfflineClient for a fake workspace that was seeded
+// before the caches are filled.
+func newOfflineClientFor(t *testing.T, slack *fakeslack.Server) *client.Client {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("SLACK_MCP_XOXP_TOKEN", fakeslack.Token)
+	t.Setenv("SLACK_MCP_API_URL", slack.APIURL())
+	t.Setenv("SLACK_MCP_USERS_CACHE", filepath.Join(dir, "users.json"))
+	t.Setenv("SLACK_MCP_CHANNELS_CACHE", filepath.Join(dir, "channels.json"))
+	t.Setenv("SLACK_MCP_ADD_MESSAGE_TOOL", "true")
+	t.Setenv("SLACK_MCP_REACTION_TOOL", "true")
+
+	ctx := context.Background()
+	logger := zap.NewNop()
+	p := provider.New("stdio", logger)
+	require.NoError(t, p.RefreshUsers(ctx))
+	require.NoError(t, p.RefreshChannels(ctx))
+
+	c, err := client.NewInProcessClient(NewMCPServer(p, logger).server)
+	require.NoError(t, err)
+	require.NoError(t, c.Start(ctx))
+	t.Cleanup(func() { c.Close() })
+
+	_, err = c.Initialize(ctx, mcp.InitializeRequest{})
+	require.NoError(t, err)
+	return c
+}
+
+func callTool(t *testing.T, c *client.Client, name string, args map[string]any) string {
+	t.Helper()
+
+	res, err := c.CallTool(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: name, Arguments: args},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, res.Content)
+	text, ok := res.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	require.False(t, res.IsError, text.Text)
+	return text.Text
+}
+
+func TestUnitOfflineServer(t *testing.T) {
+	c, slack := newOfflineClient(t)
+
+	channels := callTool(t, c, "channels_list", map[string]any{"channel_types": "public_channel,private_channel"})
+	assert.Contains(t, channels, "#general")
+	assert.Contains(t, channels, "#secret")
+
+	history := callTool(t, c, "conversations_history", map[string]any{"channel_id": "#general"})
+	assert.Contains(t, history, "Welcome to the fake workspace")
+	assert.Contains(t, history, "Release planning thread")
+
+	callTool(t, c, "conversations_add_message", map[string]any{"channel_id": "#random", "payload": "Pizza at noon"})
+	msgs := slack.Messages("C002")
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "Pizza at noon", msgs[1].Text)
+
+	callTool(t, c, "reactions_add", map[string]any{"channel_id": "C002", "timestamp": msgs[1].Timestamp, "emoji": "pizza"})
+	require.Len(t, slack.Messages("C002")[1].Reactions, 1)
+
+	found := callTool(t, c, "conversations_search_messages", map[string]any{"search_query": "changelog"})
+	assert.Contains(t, found, "I can take the changelog")
+
+	items := callTool(t, c, "lists_get_items", map[string]any{"list_id": "F003"})
+	assert.Contains(t, items, "Write release notes")
+}
+
+func TestUnitOfflineAddMessageAllowlist(t *testing.T) {
+	c, slack := newOfflineClient(t)
+
+	// a plain list only allows the listed channels
+	t.Setenv("SLACK_MCP_ADD_MESSAGE_TOOL", "C001")
+	callTool(t, c, "conversations_add_message", map[string]any{"channel_id": "#general", "payload": "allowed"})
+	_, err := c.CallTool(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "conversations_add_message", Arguments: map[string]any{"channel_id": "#random", "payload": "not allowed"}},
+	})
+	require.Error(t, err)
+
+	// a negated list allows everything else
+	t.Setenv("SLACK_MCP_ADD_MESSAGE_TOOL", "!C001")
+	callTool(t, c, "conversations_add_message", map[string]any{"channel_id": "#random", "payload": "allowed too"})
+
+	var texts []string
+	for _, m := range slack.Messages("C002") {
+		texts = append(texts, m.Text)
+	}
+	assert.Contains(t, texts, "allowed too")
+	assert.NotContains(t, texts, "not allowed")
+}
+
+func TestUnitOfflineExport(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("SLACK_MCP_EXPORT_DIR", dir)
+	c, _ := newOfflineClient(t)
+
+	res := callTool(t, c, "conversations_export", map[string]any{"channel_id": "#general", "format": "html"})
+	assert.Contains(t, res, "Exported 4 messages (1 threads with 2 replies, 0 files) from C001")
+
+	pages, err := filepath.Glob(filepath.Join(dir, "general-*.html"))
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+	page, err := os.ReadFile(pages[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "I will update the docs")
+}
+
+func TestUnitOfflineInbox(t *testing.T) {
+	c, slack := newOfflineClient(t)
+
+	res, err := c.CallTool(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "inbox_unreads", Arguments: map[string]any{"include_messages": true}},
+	})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	require.Len(t, res.Content, 2)
+	unreads := res.Content[0].(mcp.TextContent).Text
+	lines := strings.Split(strings.TrimSpace(unreads), "\n")
+	require.Len(t, lines, 4)
+	// the DM comes first, then channels by their latest message
+	assert.True(t, strings.HasPrefix(lines[1], "D001,@bob,im,1,"), lines[1])
+	assert.Contains(t, res.Content[1].(mcp.TextContent).Text, "Hey alice, got a minute?")
+
+	// marking as read is disabled by default
+	_, err = c.CallTool(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "conversations_mark_read", Arguments: map[string]any{"channel_id": "@bob"}},
+	})
+	require.ErrorContains(t, err, "SLACK_MCP_MARK_READ_TOOL")
+	assert.Contains(t, callTool(t, c, "inbox_unreads", nil), "D001")
+
+	t.Setenv("SLACK_MCP_MARK_READ_TOOL", "true")
+	callTool(t, c, "conversations_mark_read", map[string]any{"channel_id": "@bob"})
+	unreads = callTool(t, c, "inbox_unreads", nil)
+	assert.NotContains(t, unreads, "D001")
+	assert.Contains(t, unreads, "C001")
+
+	// a new DM message shows up again
+	slack.AddMessage("D001", "U002", "Ping", "")
+	assert.Contains(t, callTool(t, c, "inbox_unreads", nil), "D001")
+}
+
+func TestUnitOfflineActivityMentions(t *testing.T) {
+	c, slack := newOfflineClient(t)
+
+	mention := slack.AddMessage("C002", "U002", "hey <@U001> can you look at this?", "")
+	slack.AddMessage("C002", "U001", "on it", mention)
+	slack.AddMessage("C001", "U003", "<!subteam^S001> release at 5", "")
+
+	res := callTool(t, c, "activity_mentions", map[string]any{"since": "2023-11-01"})
+	lines := strings.Split(strings.TrimSpace(res), "\n")
+	require.Len(t, lines, 5, res)
+	assert.Equal(t, "Thread,Kind,MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,Permalink,Replied", lines[0])
+	// newest thread first: the group mention, the answered mention, then the
+	// replies to alice's release thread oldest first
+	assert.Contains(t, lines[1], ",group_mention,")
+	assert.Contains(t, lines[2], ",mention,")
+	assert.True(t, strings.HasSuffix(lines[2], ",true"), lines[2])
+	assert.Contains(t, lines[3], "I can take the changelog")
+	assert.Contains(t, lines[3], ",thread_reply,")
+	assert.True(t, strings.HasSuffix(lines[3], ",false"), lines[3])
+	assert.Contains(t, lines[3], "?thread_ts=")
+	assert.Contains(t, lines[4], "I will update the docs")
+}
+
+func TestUnitOfflineHistoryExpandThreads(t *testing.T) {
+	c, slack := newOfflineClient(t)
+
+	res := callTool(t, c, "conversations_history", map[string]any{"channel_id": "#general", "limit": "50", "expand_threads": true})
+	lines := strings.Split(strings.TrimSpace(res), "\n")
+	require.Len(t, lines, 5, res)
+	assert.True(t, strings.HasSuffix(lines[0], ",ReplyCount,Truncated,Permalink,Cursor"), lines[0])
+	// history is newest first, the replies follow their parent oldest first
+	assert.Contains(t, lines[1], "Release planning thread")
+	assert.Contains(t, lines[1], ",2,false,")
+	assert.Contains(t, lines[2], "I can take the changelog")
+	assert.Contains(t, lines[3], "I will update the docs")
+	assert.Contains(t, lines[4], "Welcome")
+
+	// only the newest thread is expanded, the other parent is truncated
+	other := slack.AddMessage("C001", "U002", "Another thread", "")
+	slack.AddMessage("C001", "U003", "A reply", other)
+	res = callTool(t, c, "conversations_history", map[string]any{"channel_id": "#general", "limit": "50", "expand_threads": true, "max_threads": 1})
+	lines = strings.Split(strings.TrimSpace(res), "\n")
+	require.Len(t, lines, 5, res)
+	assert.Contains(t, lines[1], "Another thread")
+	assert.Contains(t, lines[2], "A reply")
+	assert.Contains(t, lines[3], "Release planning thread")
+	assert.Contains(t, lines[3], ",2,true,")
+
+	// without expand_threads only the reply count is listed
+	res = callTool(t, c, "conversations_history", map[string]any{"channel_id": "#general", "limit": "50"})
+	assert.NotContains(t, res, "A reply")
+	assert.Contains(t, res, ",1,false,")
+}
+
+func TestUnitOfflineMessagesGet(t *testing.T) {
+	c, slack := newOfflineClient(t)
+
+	parent := slack.AddMessage("C002", "U002", "Standup notes", "")
+	reply := slack.AddMessage("C002", "U003", "Looks good", parent)
+	lone := slack.AddMessage("C002", "U001", "Solo message", "")
+	replyLink := slack.URL + "/archives/C002/p" + strings.ReplaceAll(reply, ".", "") + "?thread_ts=" + parent + "&cid=C002"
+
+	res := callTool(t, c, "messages_get", map[string]any{"messages": replyLink + ", #random:" + lone})
+	lines := strings.Split(strings.TrimSpace(res), "\n")
+	require.Len(t, lines, 4, res)
+	assert.Contains(t, lines[1], "Standup notes")
+	assert.Contains(t, lines[2], "Looks good")
+	// every row links to its message
+	assert.Contains(t, lines[2], replyLink)
+	assert.Contains(t, lines[3], "Solo message")
+
+	// a reply without its thread_ts still comes with its thread
+	res = callTool(t, c, "messages_get", map[string]any{"messages": "C002:" + reply})
+	lines = strings.Split(strings.TrimSpace(res), "\n")
+	require.Len(t, lines, 3, res)
+	assert.Contains(t, lines[1], "Standup notes")
+
+	// the thread can be read from a pasted permalink
+	res = callTool(t, c, "conversations_replies", map[string]any{"channel_id": replyLink, "limit": "50"})
+	lines = strings.Split(strings.TrimSpace(res), "\n")
+	require.Len(t, lines, 3, res)
+	assert.Contains(t, lines[2], "Looks good")
+}
+
+func TestUnitOfflineChannelAdmin(t *testing.T) {
+	c, slack := newOfflineClient(t)
+	ctx := context.Background()
+
+	_, err := c.CallTool(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "channels_create", Arguments: map[string]any{"name": "inc-1234"}},
+	})
+	require.ErrorContains(t, err, "SLACK_MCP_CHANNEL_ADMIN_TOOL")
+
+	t.Setenv("SLACK_MCP_CHANNEL_ADMIN_TOOL", "true")
+	res := callTool(t, c, "channels_create", map[string]any{"name": "#inc-1234", "topic": "Checkout is down", "purpose": "Incident 1234"})
+	assert.Contains(t, res, ",#inc-1234,Checkout is down,Incident 1234,1,")
+	id := strings.Split(strings.Split(res, "\n")[1], ",")[0]
+
+	// the cache knows the channel right away, so it can be used by name
+	callTool(t, c, "channels_rename", map[string]any{"channel_id": "#inc-1234", "name": "inc-1234-checkout"})
+	callTool(t, c, "channels_set_topic", map[string]any{"channel_id": "#inc-1234-checkout", "topic": "Resolved"})
+	channels := callTool(t, c, "channels_list", map[string]any{"channel_types": "public_channel"})
+	assert.Contains(t, channels, "#inc-1234-checkout,Resolved,Incident 1234")
+	assert.NotContains(t, channels, "#inc-1234,")
+
+	callTool(t, c, "channels_archive", map[string]any{"channel_id": "#inc-1234-checkout"})
+	assert.NotContains(t, callTool(t, c, "channels_list", map[string]any{"channel_types": "public_channel"}), "inc-1234")
+
+	// without archived channels in the cache it takes an ID only
+	_, err = c.CallTool(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "channels_unarchive", Arguments: map[string]any{"channel_id": "#inc-1234-checkout"}},
+	})
+	require.ErrorContains(t, err, "SLACK_MCP_CHANNELS_INCLUDE_ARCHIVED")
+
+	res = callTool(t, c, "channels_unarchive", map[string]any{"channel_id": id})
+	assert.Contains(t, res, "#inc-1234-checkout")
+	assert.Contains(t, callTool(t, c, "channels_list", map[string]any{"channel_types": "public_channel"}), "#inc-1234-checkout")
+	assert.Contains(t, slack.Calls(), "conversations.info")
+}
+
+func TestUnitOfflineChannelMembers(t *testing.T) {
+	c, _ := newOfflineClient(t)
+	t.Setenv("SLACK_MCP_CHANNEL_ADMIN_TOOL", "true")
+
+	res := callTool(t, c, "channels_members", map[string]any{"channel_id": "#general", "limit": 2})
+	lines := strings.Split(strings.TrimSpace(res), "\n")
+	require.Len(t, lines, 3, res)
+	assert.Equal(t, "UserID,UserName,RealName,Cursor", lines[0])
+	assert.Equal(t, "U001,alice,Alice Example,", lines[1])
+	cursor := lines[2][strings.LastIndex(lines[2], ",")+1:]
+	require.NotEmpty(t, cursor)
+	res = callTool(t, c, "channels_members", map[string]any{"channel_id": "C001", "cursor": cursor})
+	assert.Equal(t, "U003,carol,Carol Example,", strings.Split(strings.TrimSpace(res), "\n")[1])
+
+	assert.Contains(t, callTool(t, c, "channels_leave", map[string]any{"channel_id": "#random"}), "Left channel C002")
+	assert.Contains(t, callTool(t, c, "channels_leave", map[string]any{"channel_id": "#random"}), "Not a member")
+	assert.Contains(t, callTool(t, c, "channels_join", map[string]any{"channel_id": "#random"}), ",#random,")
+
+	res = callTool(t, c, "channels_invite", map[string]any{"channel_id": "#random", "users": "@carol"})
+	assert.Contains(t, res, ",#random,,Everything else,3,")
+	assert.Contains(t, callTool(t, c, "channels_members", map[string]any{"channel_id": "#random"}), "U003,carol")
+
+	callTool(t, c, "channels_kick", map[string]any{"channel_id": "#random", "user": "U003"})
+	assert.NotContains(t, callTool(t, c, "channels_members", map[string]any{"channel_id": "#random"}), "carol")
+	assert.Contains(t, callTool(t, c, "channels_list", map[string]any{"channel_types": "public_channel"}), "#random,,Everything else,2,")
+}
+
+func TestUnitOfflineChannelsInfo(t *testing.T) {
+	c, slack := newOfflineClient(t)
+
+	parent := slack.Messages("C001")[1].Timestamp
+	require.True(t, slack.AddPin("C001", parent))
+	slack.AddBookmark("C001", "Handbook", "https://example.com/handbook")
+
+	var info handler.ChannelInfo
+	require.NoError(t, json.Unmarshal([]byte(callTool(t, c, "channels_info", map[string]any{"channel_id": "#general"})), &info))
+	assert.Equal(t, "C001", info.ID)
+	assert.Equal(t, "#general", info.Name)
+	assert.Equal(t, "Company wide announcements", info.Purpose)
+	assert.Equal(t, "2023-11-14T22:13:20Z", info.Created)
+	assert.Equal(t, 3, info.MemberCount)
+	assert.NotEmpty(t, info.LastActivity)
+	require.NotNil(t, info.PinnedCount)
+	assert.Equal(t, 1, *info.PinnedCount)
+	require.Len(t, info.Bookmarks, 1)
+	assert.Equal(t, "https://example.com/handbook", info.Bookmarks[0].Link)
+	assert.Empty(t, info.ExternalTeams)
+	assert.Contains(t, slack.Calls(), "conversations.genericInfo")
+
+	// a Slack Connect channel the cache doesn't know yet
+	shared := &slackapi.Channel{}
+	shared.ID = "C003"
+	shared.Name = "partners"
+	shared.Creator = "U002"
+	shared.IsChannel = true
+	shared.IsShared = true
+	shared.IsExtShared = true
+	shared.ConnectedTeamIDs = []string{fakeslack.TeamID, "T0EXT001"}
+	slack.AddChannel(shared)
+	slack.AddTeam("T0EXT001", "Partner Co")
+
+	text := callTool(t, c, "channels_info", map[string]any{"channel_id": "C003"})
+	assert.Contains(t, text, `"name":"#partners"`)
+	assert.Contains(t, text, `"creatorName":"bob"`)
+	assert.Contains(t, text, `"isExtShared":true`)
+	assert.Contains(t, text, `"externalTeams":[{"id":"T0EXT001","name":"Partner Co"}]`)
+	assert.Contains(t, text, `"pinnedCount":0`)
+}
+
+func TestUnitOfflinePinsAndBookmarks(t *testing.T) {
+	c, slack := newOfflineClient(t)
+	ctx := context.Background()
+	parent := slack.Messages("C001")[1].Timestamp
+
+	_, err := c.CallTool(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "pins_add", Arguments: map[string]any{"channel_id": "#general", "timestamp": parent}},
+	})
+	require.ErrorContains(t, err, "SLACK_MCP_PIN_TOOL")
+
+	t.Setenv("SLACK_MCP_PIN_TOOL", "C001")
+	callTool(t, c, "pins_add", map[string]any{"channel_id": "#general", "timestamp": parent})
+	pins := callTool(t, c, "pins_list", map[string]any{"channel_id": "#general"})
+	lines := strings.Split(strings.TrimSpace(pins), "\n")
+	require.Len(t, lines, 2, pins)
+	assert.Contains(t, lines[1], "Release planning thread")
+
+	_, err = c.CallTool(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "bookmarks_add", Arguments: map[string]any{"channel_id": "#random", "title": "Menu", "link": "https://example.com/menu"}},
+	})
+	require.ErrorContains(t, err, "not allowed for channel \"C002\"")
+
+	callTool(t, c, "bookmarks_add", map[string]any{"channel_id": "C001", "title": "Runbook", "link": "https://example.com/runbook"})
+	bookmarks := slack.Bookmarks("C001")
+	require.Len(t, bookmarks, 1)
+	assert.Contains(t, callTool(t, c, "bookmarks_list", map[string]any{"channel_id": "#general"}), ",Runbook,https://example.com/runbook,link")
+
+	callTool(t, c, "bookmarks_remove", map[string]any{"channel_id": "C001", "bookmark_id": bookmarks[0].ID})
+	assert.Empty(t, slack.Bookmarks("C001"))
+	callTool(t, c, "pins_remove", map[string]any{"channel_id": "C001", "timestamp": parent})
+	lines = strings.Split(strings.TrimSpace(callTool(t, c, "pins_list", map[string]any{"channel_id": "C001"})), "\n")
+	assert.Len(t, lines, 1)
+}
+
+func TestUnitOfflineReminders(t *testing.T) {
+	c, slack := newOfflineClient(t)
+
+	_, err := c.CallTool(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "reminders_add", Arguments: map[string]any{"text": "Follow up", "time": "in 2h"}},
+	})
+	require.ErrorContains(t, err, "SLACK_MCP_REMINDER_TOOL")
+
+	t.Setenv("SLACK_MCP_REMINDER_TOOL", "true")
+	res := callTool(t, c, "reminders_add", map[string]any{"text": "Follow up on the release", "time": "in 2h"})
+	assert.Contains(t, res, ",U001,alice,Follow up on the release,")
+	res = callTool(t, c, "reminders_add", map[string]any{"text": "Review the RFC", "time": "tomorrow at 9am", "user": "@bob"})
+	// bob is in Europe/Berlin
+	assert.Regexp(t, `,U002,bob,Review the RFC,\d{4}-\d{2}-\d{2}T09:00:00\+0[12]:00,false,`, res)
+	callTool(t, c, "reminders_add", map[string]any{"text": "Standup notes", "time": "every weekday at 9am"})
+
+	reminders := slack.Reminders()
+	require.Len(t, reminders, 3)
+	assert.Equal(t, "U002", reminders[1].User)
+	assert.True(t, reminders[2].Recurring)
+
+	callTool(t, c, "reminders_complete", map[string]any{"reminder_id": reminders[0].ID})
+	callTool(t, c, "reminders_delete", map[string]any{"reminder_id": reminders[1].ID})
+
+	lines := strings.Split(strings.TrimSpace(callTool(t, c, "reminders_list", map[string]any{})), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "Standup notes")
+	lines = strings.Split(strings.TrimSpace(callTool(t, c, "reminders_list", map[string]any{"include_completed": true})), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[2], "Follow up on the release")
+}
+
+func TestUnitOfflinePresence(t *testing.T) {
+	c, slack := newOfflineClient(t)
+	now := time.Now()
+
+	bob, ok := slack.User("U002")
+	require.True(t, ok)
+	bob.Profile.StatusText = "On vacation"
+	bob.Profile.StatusEmoji = ":palm_tree:"
+	bob.Profile.StatusExpiration = int(now.Add(48 * time.Hour).Unix())
+	slack.AddUser(bob)
+	slack.SetDND("U002", slackapi.DNDStatus{
+		Enabled:            true,
+		NextStartTimestamp: int(now.Add(-time.Hour).Unix()),
+		NextEndTimestamp:   int(now.Add(time.Hour).Unix()),
+	})
+
+	presence := func(users string) map[string][]string {
+		t.Helper()
+		rows := map[string][]string{}
+		lines := strings.Split(strings.TrimSpace(callTool(t, c, "users_get_presence", map[string]any{"users": users})), "\n")
+		for _, line := range lines[1:] {
+			fields := strings.Split(line, ",")
+			rows[fields[0]] = fields
+		}
+		return rows
+	}
+
+	rows := presence("@bob,U001")
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"U002", "bob", "Bob Example", "away", "On vacation", ":palm_tree:"}, rows["U002"][:6])
+	assert.Equal(t, "true", rows["U002"][7])
+	assert.Equal(t, "Europe/Berlin", rows["U002"][12])
+	assert.Equal(t, "active", rows["U001"][3])
+	assert.Equal(t, "false", rows["U001"][7])
+
+	_, err := c.CallTool(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "dnd_set_snooze", Arguments: map[string]any{"minutes": 30}},
+	})
+	require.ErrorContains(t, err, "SLACK_MCP_STATUS_TOOL")
+
+	t.Setenv("SLACK_MCP_STATUS_TOOL", "true")
+	callTool(t, c, "users_set_status", map[string]any{"status_text": "In a meeting", "status_emoji": "calendar", "expiration": "in 1h"})
+	alice, _ := slack.User("U001")
+	assert.Equal(t, ":calendar:", alice.Profile.StatusEmoji)
+	assert.InDelta(t, now.Add(time.Hour).Unix(), alice.Profile.StatusExpiration, 60)
+
+	callTool(t, c, "dnd_set_snooze", map[string]any{"minutes": 30})
+	assert.True(t, slack.DND("U001").SnoozeEnabled)
+	rows = presence("")
+	assert.Equal(t, []string{"U001", "alice", "Alice Example", "active", "In a meeting", ":calendar:"}, rows["U001"][:6])
+	assert.Equal(t, "true", rows["U001"][7])
+
+	callTool(t, c, "dnd_set_snooze", map[string]any{"minutes": 0})
+	assert.False(t, slack.DND("U001").SnoozeEnabled)
+	callTool(t, c, "users_set_status", map[string]any{})
+	alice, _ = slack.User("U001")
+	assert.Empty(t, alice.Profile.StatusText)
+}
+
+func TestUnitOfflineUserProfile(t *testing.T) {
+	c, slack := newOfflineClient(t)
+
+	slack.SetTeamProfile(
+		slackapi.TeamProfileField{ID: "Xf02", Label: "Manager", Type: "user", Ordering: 1},
+		slackapi.TeamProfileField{ID: "Xf01", Label: "Team", Type: "text", Ordering: 0},
+		slackapi.TeamProfileField{ID: "Xf03", Label: "Badge", Type: "text", Ordering: 2, IsHidden: true},
+	)
+	slack.SetPronouns("U002", "he/him")
+	bob, ok := slack.User("U002")
+	require.True(t, ok)
+	bob.Profile.Phone = "+49 30 1234567"
+	bob.Profile.SetFieldsMap(map[string]slackapi.UserProfileCustomField{
+		"Xf01": {Value: "Platform"},
+		"Xf02": {Value: "U001"},
+		"Xf03": {Value: "42"},
+	})
+	slack.AddUser(bob)
+
+	var profile handler.UserProfileInfo
+	require.NoError(t, json.Unmarshal([]byte(callTool(t, c, "users_get_profile", map[string]any{"user": "@bob"})), &profile))
+	assert.Equal(t, "U002", profile.UserID)
+	assert.Equal(t, "he/him", profile.Pronouns)
+	assert.Equal(t, "+49 30 1234567", profile.Phone)
+	assert.Equal(t, "Europe/Berlin", profile.TZ)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	_, offset := time.Now().In(berlin).Zone()
+	assert.Equal(t, fmt.Sprintf("+%02d:00", offset/3600), profile.UTCOffset)
+	local, err := time.Parse(time.RFC3339, profile.LocalTime)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), local, time.Minute)
+	require.NotNil(t, profile.Manager)
+	assert.Equal(t, "alice", profile.Manager.UserName)
+	require.Len(t, profile.CustomFields, 2)
+	assert.Equal(t, "Team", profile.CustomFields[0].Label)
+	assert.Equal(t, "Platform", profile.CustomFields[0].Value)
+	assert.Equal(t, "Manager", profile.CustomFields[1].Label)
+
+	text := callTool(t, c, "users_get_profile", map[string]any{})
+	assert.Contains(t, text, `"userID":"U001"`)
+	assert.Contains(t, text, `"tz":"America/New_York"`)
+}
+
+func TestUnitOfflineUserGroups(t *testing.T) {
+	slack := fakeslack.New()
+	t.Cleanup(slack.Close)
+	slack.AddUserGroup(slackapi.UserGroup{ID: "S002", Name: "Platform on-call", Handle: "oncall-platform", Description: "Pager rotation", Users: []string{"U002"}})
+	c := newOfflineClientFor(t, slack)
+	// user groups are only listed on first use
+	assert.NotContains(t, slack.Calls(), "usergroups.list")
+
+	groups := callTool(t, c, "usergroups_list", nil)
+	assert.Equal(t, "ID,Handle,Name,Description,MemberCount\nS002,@oncall-platform,Platform on-call,Pager rotation,1\nS001,@releases,Release team,,2\n", groups)
+	assert.NotContains(t, callTool(t, c, "usergroups_list", map[string]any{"query": "pager"}), "@releases")
+
+	members := callTool(t, c, "usergroups_members", map[string]any{"usergroup": "@releases"})
+	assert.Equal(t, "UserID,UserName,RealName\nU001,alice,Alice Example\nU003,carol,Carol Example\n", members)
+
+	slack.AddMessage("C001", "U003", "<!subteam^S002> db is down, cc <!subteam^S009|@ghosts>", "")
+	history := callTool(t, c, "conversations_history", map[string]any{"channel_id": "#general", "limit": "1"})
+	assert.Contains(t, history, "@oncall-platform db is down, cc @ghosts")
+
+	_, err := c.CallTool(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "usergroups_update_members", Arguments: map[string]any{"usergroup": "S001", "add": "@bob"}},
+	})
+	require.ErrorContains(t, err, "SLACK_MCP_USERGROUP_TOOL")
+
+	t.Setenv("SLACK_MCP_USERGROUP_TOOL", "true")
+	res := callTool(t, c, "usergroups_update_members", map[string]any{"usergroup": "@releases", "add": "@bob", "remove": "U003"})
+	assert.Equal(t, "Updated user group @releases, it has 2 members now", res)
+	members = callTool(t, c, "usergroups_members", map[string]any{"usergroup": "S001"})
+	assert.Contains(t, members, "U002,bob")
+	assert.NotContains(t, members, "carol")
+}
+
+func TestUnitOfflineUserGroupsErrors(t *testing.T) {
+	c, slack := newOfflineClient(t)
+	count := func() int {
+		n := 0
+		for _, call := range slack.Calls() {
+			if call == "usergroups.list" {
+				n++
+			}
+		}
+		return n
+	}
+
+	// a transient failure is not cached
+	slack.FailNext("usergroups.list", "internal_error")
+	assert.NotContains(t, callTool(t, c, "usergroups_list", nil), "@releases")
+	assert.Contains(t, callTool(t, c, "usergroups_list", nil), "@releases")
+	assert.Equal(t, 2, count())
+
+	// without access the empty set is kept until the next users refresh
+	c, slack = newOfflineClient(t)
+	slack.FailNext("usergroups.list", "missing_scope")
+	assert.NotContains(t, callTool(t, c, "usergroups_list", nil), "@releases")
+	assert.NotContains(t, callTool(t, c, "usergroups_list", nil), "@releases")
+	assert.Equal(t, 1, count())
+}
+
+func TestUnitOfflineChannelsListFilters(t *testing.T) {
+	c, _ := newOfflineClient(t)
+
+	rows := func(args map[string]any) []string {
+		t.Helper()
+		lines := strings.Split(strings.TrimSpace(callTool(t, c, "channels_list", args)), "\n")
+		return lines[1:]
+	}
+
+	res := rows(map[string]any{"channel_types": "public_channel,private_channel", "sort": "name"})
+	require.Len(t, res, 3)
+	assert.True(t, strings.HasPrefix(res[0], "C001,#general,"))
+	assert.True(t, strings.HasPrefix(res[2], "G001,#secret,"))
+
+	// #random has the newest message
+	res = rows(map[string]any{"channel_types": "public_channel", "sort": "activity"})
+	require.Len(t, res, 2)
+	assert.True(t, strings.HasPrefix(res[0], "C002,#random,"))
+
+	res = rows(map[string]any{"channel_types": "public_channel,private_channel", "text": "planning"})
+	require.Len(t, res, 1)
+	assert.True(t, strings.HasPrefix(res[0], "G001,#secret,"))
+
+	res = rows(map[string]any{"channel_types": "public_channel", "min_members": 3, "member_only": true})
+	require.Len(t, res, 1)
+	assert.True(t, strings.HasPrefix(res[0], "C001,#general,"))
+
+	// pages follow the sort order across calls
+	res = rows(map[string]any{"channel_types": "public_channel,private_channel", "sort": "name", "limit": 2})
+	require.Len(t, res, 2)
+	cursor := res[1][strings.LastIndex(res[1], ",")+1:]
+	res = rows(map[string]any{"channel_types": "public_channel,private_channel", "sort": "name", "cursor": cursor})
+	require.Len(t, res, 1)
+	assert.True(t, strings.HasPrefix(res[0], "G001,#secret,"))
+
+	_, err := c.CallTool(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "channels_list", Arguments: map[string]any{"channel_types": "public_channel", "archived": "never"}},
+	})
+	require.ErrorContains(t, err, "invalid archived")
+}
+
+func TestUnitOfflineArchivedChannels(t *testing.T) {
+	t.Setenv("SLACK_MCP_CHANNELS_INCLUDE_ARCHIVED", "true")
+	t.Setenv("SLACK_MCP_CHANNEL_ADMIN_TOOL", "true")
+	c, _ := newOfflineClient(t)
+
+	callTool(t, c, "channels_archive", map[string]any{"channel_id": "#random"})
+
+	// archived channels stay cached, but are only listed on request
+	assert.NotContains(t, callTool(t, c, "channels_list", map[string]any{"channel_types": "public_channel"}), "#random")
+	archived := callTool(t, c, "channels_list", map[string]any{"channel_types": "public_channel", "archived": "only"})
+	assert.Contains(t, archived, "C002,#random,")
+	assert.NotContains(t, archived, "#general")
+
+	// and their names still resolve
+	assert.Contains(t, callTool(t, c, "conversations_history", map[string]any{"channel_id": "#random"}), "Lunch anyone?")
+
+	// so they can be unarchived by name
+	assert.Contains(t, callTool(t, c, "channels_unarchive", map[string]any{"channel_id": "#random"}), "C002,#random,")
+	assert.Contains(t, callTool(t, c, "channels_list", map[string]any{"channel_types": "public_channel"}), "#random")
+}
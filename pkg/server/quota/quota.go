@@ -0,0 +1,322 @@
+package quota
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// Class groups tools that share a per-client request budget.
+type Class string
+
+const (
+	ClassRead   Class = "read"
+	ClassSearch Class = "search"
+	ClassWrite  Class = "write"
+)
+
+var classes = []Class{ClassRead, ClassSearch, ClassWrite}
+
+// window is the period a per-minute quota refills in. A client idle for
+// longer has a full budget again, so its state can be dropped.
+const window = time.Minute
+
+// Config holds per-client quotas, a zero value disables the corresponding limit.
+type Config struct {
+	// PerMinute is the number of tool calls a client may make per minute for each class.
+	PerMinute map[Class]int
+	// MaxInFlight is the number of tool calls a client may have running at once.
+	MaxInFlight int
+}
+
+// ConfigFromEnv reads quotas from SLACK_MCP_RATE_LIMIT_READ, SLACK_MCP_RATE_LIMIT_SEARCH,
+// SLACK_MCP_RATE_LIMIT_WRITE and SLACK_MCP_MAX_IN_FLIGHT.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{PerMinute: map[Class]int{}}
+
+	envs := map[Class]string{
+		ClassRead:   "SLACK_MCP_RATE_LIMIT_READ",
+		ClassSearch: "SLACK_MCP_RATE_LIMIT_SEARCH",
+		ClassWrite:  "SLACK_MCP_RATE_LIMIT_WRITE",
+	}
+	for class, env := range envs {
+		n, err := parseLimit(env)
+		if err != nil {
+			return Config{}, err
+		}
+		cfg.PerMinute[class] = n
+	}
+
+	n, err := parseLimit("SLACK_MCP_MAX_IN_FLIGHT")
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.MaxInFlight = n
+
+	return cfg, nil
+}
+
+func parseLimit(env string) (int, error) {
+	v := strings.TrimSpace(os.Getenv(env))
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", env, v)
+	}
+	return n, nil
+}
+
+// Enabled reports whether any quota is configured.
+func (c Config) Enabled() bool {
+	if c.MaxInFlight > 0 {
+		return true
+	}
+	for _, n := range c.PerMinute {
+		if n > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ThrottledError describes a rejected tool call, it is returned to the client
+// as structured content so agents can back off for RetryAfterSeconds.
+type ThrottledError struct {
+	Error             string `json:"error"`
+	Reason            string `json:"reason"`
+	Class             Class  `json:"class"`
+	Limit             int    `json:"limit"`
+	RetryAfterSeconds int    `json:"retry_after_seconds"`
+}
+
+// Stats is a snapshot of a single client's counters for one tool class.
+type Stats struct {
+	Client      string `csv:"client"`
+	Class       Class  `csv:"class"`
+	PerMinute   int    `csv:"limitPerMinute"`
+	Allowed     int64  `csv:"allowed"`
+	Throttled   int64  `csv:"throttled"`
+	InFlight    int    `csv:"inFlight"`
+	MaxInFlight int    `csv:"maxInFlight"`
+}
+
+type classState struct {
+	limiter   *rate.Limiter
+	allowed   int64
+	throttled int64
+}
+
+type clientState struct {
+	inFlight int
+	lastSeen time.Time
+	classes  map[Class]*classState
+}
+
+// Manager tracks per-client token buckets and in-flight calls.
+type Manager struct {
+	cfg    Config
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*clientState
+	lastPrune time.Time
+}
+
+func New(cfg Config, logger *zap.Logger) *Manager {
+	return &Manager{
+		cfg:     cfg,
+		logger:  logger,
+		now:     time.Now,
+		clients: make(map[string]*clientState),
+	}
+}
+
+// prune drops clients with no calls in flight that have been idle for longer
+// than the quota window, so sessions that went away don't pile up. It scans
+// the clients at most once per window.
+func (m *Manager) prune(now time.Time) {
+	if now.Sub(m.lastPrune) < window {
+		return
+	}
+	m.lastPrune = now
+	for id, c := range m.clients {
+		if c.inFlight == 0 && now.Sub(c.lastSeen) > window {
+			delete(m.clients, id)
+		}
+	}
+}
+
+func (m *Manager) client(id string) *clientState {
+	c, ok := m.clients[id]
+	if !ok {
+		c = &clientState{classes: make(map[Class]*classState, len(classes))}
+		for _, class := range classes {
+			st := &classState{}
+			if n := m.cfg.PerMinute[class]; n > 0 {
+				// A full minute of budget is available as burst, then it refills evenly.
+				st.limiter = rate.NewLimiter(rate.Limit(float64(n)/60), n)
+			}
+			c.classes[class] = st
+		}
+		m.clients[id] = c
+	}
+	return c
+}
+
+// acquire reserves a slot for a call, on success the returned release func
+// must be called once the call has finished.
+func (m *Manager) acquire(id string, class Class) (func(), *ThrottledError) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.prune(now)
+	c := m.client(id)
+	c.lastSeen = now
+	st := c.classes[class]
+
+	if m.cfg.MaxInFlight > 0 && c.inFlight >= m.cfg.MaxInFlight {
+		st.throttled++
+		return nil, &ThrottledError{
+			Error:             "rate_limited",
+			Reason:            fmt.Sprintf("too many concurrent tool calls, at most %d may be in flight", m.cfg.MaxInFlight),
+			Class:             class,
+			Limit:             m.cfg.MaxInFlight,
+			RetryAfterSeconds: 1,
+		}
+	}
+
+	if st.limiter != nil {
+		r := st.limiter.ReserveN(now, 1)
+		if delay := r.DelayFrom(now); delay > 0 {
+			r.CancelAt(now)
+			st.throttled++
+			return nil, &ThrottledError{
+				Error:             "rate_limited",
+				Reason:            fmt.Sprintf("%s tool call quota of %d per minute exceeded", class, m.cfg.PerMinute[class]),
+				Class:             class,
+				Limit:             m.cfg.PerMinute[class],
+				RetryAfterSeconds: int(math.Ceil(delay.Seconds())),
+			}
+		}
+	}
+
+	st.allowed++
+	c.inFlight++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			c.inFlight--
+			c.lastSeen = m.now()
+			m.mu.Unlock()
+		})
+	}, nil
+}
+
+// Stats returns counters for every client and class seen within the last
+// quota window or with calls in flight, sorted by client and class.
+func (m *Manager) Stats() []Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune(m.now())
+
+	var out []Stats
+	for id, c := range m.clients {
+		for _, class := range classes {
+			st := c.classes[class]
+			out = append(out, Stats{
+				Client:      id,
+				Class:       class,
+				PerMinute:   m.cfg.PerMinute[class],
+				Allowed:     st.allowed,
+				Throttled:   st.throttled,
+				InFlight:    c.inFlight,
+				MaxInFlight: m.cfg.MaxInFlight,
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Client != out[j].Client {
+			return out[i].Client < out[j].Client
+		}
+		return out[i].Class < out[j].Class
+	})
+	return out
+}
+
+// ClientID identifies the caller by API key when one is sent, otherwise by
+// MCP session. Keys are hashed so they never show up in status output.
+func ClientID(ctx context.Context) string {
+	if token, ok := auth.TokenFromContext(ctx); ok {
+		sum := sha256.Sum256([]byte(strings.TrimPrefix(token, "Bearer ")))
+		return "key:" + hex.EncodeToString(sum[:])[:12]
+	}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return "session:" + session.SessionID()
+	}
+	return "anonymous"
+}
+
+// ClassifyTool puts search tools in the search class, tools not annotated as
+// read-only in the write class and everything else in the read class.
+func ClassifyTool(tool *server.ServerTool) Class {
+	if tool == nil {
+		return ClassRead
+	}
+	if strings.Contains(tool.Tool.Name, "search") {
+		return ClassSearch
+	}
+	if hint := tool.Tool.Annotations.ReadOnlyHint; hint == nil || !*hint {
+		return ClassWrite
+	}
+	return ClassRead
+}
+
+// BuildMiddleware enforces quotas for every tool call, classify maps a tool
+// name to its class.
+func BuildMiddleware(m *Manager, classify func(name string) Class) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := ClientID(ctx)
+			class := classify(req.Params.Name)
+
+			release, throttled := m.acquire(id, class)
+			if throttled != nil {
+				m.logger.Warn("Tool call throttled",
+					zap.String("client", id),
+					zap.String("tool", req.Params.Name),
+					zap.String("class", string(class)),
+					zap.String("reason", throttled.Reason),
+					zap.Int("retry_after_seconds", throttled.RetryAfterSeconds),
+				)
+				result := mcp.NewToolResultStructured(throttled,
+					fmt.Sprintf("rate limited: %s, retry after %d seconds", throttled.Reason, throttled.RetryAfterSeconds))
+				result.IsError = true
+				return result, nil
+			}
+			defer release()
+
+			return next(ctx, req)
+		}
+	}
+}
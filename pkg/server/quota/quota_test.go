@@ -0,0 +1,170 @@
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUnitConfigFromEnv(t *testing.T) {
+	t.Setenv("SLACK_MCP_RATE_LIMIT_READ", "120")
+	t.Setenv("SLACK_MCP_RATE_LIMIT_SEARCH", "")
+	t.Setenv("SLACK_MCP_RATE_LIMIT_WRITE", "10")
+	t.Setenv("SLACK_MCP_MAX_IN_FLIGHT", "4")
+
+	cfg, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 120, cfg.PerMinute[ClassRead])
+	assert.Equal(t, 0, cfg.PerMinute[ClassSearch])
+	assert.Equal(t, 10, cfg.PerMinute[ClassWrite])
+	assert.Equal(t, 4, cfg.MaxInFlight)
+	assert.True(t, cfg.Enabled())
+
+	t.Setenv("SLACK_MCP_RATE_LIMIT_WRITE", "-1")
+	_, err = ConfigFromEnv()
+	assert.Error(t, err)
+}
+
+func TestUnitManagerPerMinute(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	m := New(Config{PerMinute: map[Class]int{ClassWrite: 2}}, zap.NewNop())
+	m.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		release, throttled := m.acquire("a", ClassWrite)
+		require.Nil(t, throttled)
+		release()
+	}
+
+	_, throttled := m.acquire("a", ClassWrite)
+	require.NotNil(t, throttled)
+	assert.Equal(t, "rate_limited", throttled.Error)
+	assert.Equal(t, ClassWrite, throttled.Class)
+	assert.Equal(t, 30, throttled.RetryAfterSeconds)
+
+	// Other clients and classes have their own budgets.
+	_, throttled = m.acquire("b", ClassWrite)
+	assert.Nil(t, throttled)
+	_, throttled = m.acquire("a", ClassRead)
+	assert.Nil(t, throttled)
+
+	// The bucket refills over time.
+	now = now.Add(30 * time.Second)
+	_, throttled = m.acquire("a", ClassWrite)
+	assert.Nil(t, throttled)
+}
+
+func TestUnitManagerMaxInFlight(t *testing.T) {
+	m := New(Config{MaxInFlight: 1}, zap.NewNop())
+
+	release, throttled := m.acquire("a", ClassRead)
+	require.Nil(t, throttled)
+
+	_, throttled = m.acquire("a", ClassSearch)
+	require.NotNil(t, throttled)
+	assert.Equal(t, 1, throttled.RetryAfterSeconds)
+
+	release()
+	release() // releasing twice must not free an extra slot
+
+	release, throttled = m.acquire("a", ClassSearch)
+	require.Nil(t, throttled)
+	_, throttled = m.acquire("a", ClassRead)
+	assert.NotNil(t, throttled)
+	release()
+}
+
+func TestUnitManagerPrunesIdleClients(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	m := New(Config{PerMinute: map[Class]int{ClassRead: 10}}, zap.NewNop())
+	m.now = func() time.Time { return now }
+
+	release, throttled := m.acquire("idle", ClassRead)
+	require.Nil(t, throttled)
+	release()
+	busyRelease, throttled := m.acquire("busy", ClassRead)
+	require.Nil(t, throttled)
+
+	now = now.Add(2 * window)
+	release, throttled = m.acquire("new", ClassRead)
+	require.Nil(t, throttled)
+	release()
+
+	// Only the idle client is dropped, the one with a call in flight stays.
+	assert.NotContains(t, m.clients, "idle")
+	assert.Contains(t, m.clients, "busy")
+	assert.Contains(t, m.clients, "new")
+
+	busyRelease()
+	now = now.Add(2 * window)
+	release, throttled = m.acquire("new", ClassRead)
+	require.Nil(t, throttled)
+	assert.NotContains(t, m.clients, "busy")
+	assert.Len(t, m.clients, 1)
+
+	// Stats prunes too, so idle clients go away once traffic stops.
+	release()
+	now = now.Add(2 * window)
+	assert.Empty(t, m.Stats())
+}
+
+func TestUnitManagerStats(t *testing.T) {
+	m := New(Config{PerMinute: map[Class]int{ClassSearch: 1}}, zap.NewNop())
+
+	release, _ := m.acquire("a", ClassSearch)
+	release()
+	_, _ = m.acquire("a", ClassSearch)
+
+	stats := m.Stats()
+	require.Len(t, stats, 3)
+	assert.Equal(t, Stats{Client: "a", Class: ClassSearch, PerMinute: 1, Allowed: 1, Throttled: 1}, stats[1])
+
+	csv, err := gocsv.MarshalString(&stats)
+	require.NoError(t, err)
+	assert.Contains(t, csv, "a,search,1,1,1,0,0")
+}
+
+func TestUnitClassifyTool(t *testing.T) {
+	read := mcp.NewTool("channels_list", mcp.WithReadOnlyHintAnnotation(true))
+	search := mcp.NewTool("conversations_search_messages", mcp.WithReadOnlyHintAnnotation(true))
+	write := mcp.NewTool("conversations_add_message")
+
+	assert.Equal(t, ClassRead, ClassifyTool(&server.ServerTool{Tool: read}))
+	assert.Equal(t, ClassSearch, ClassifyTool(&server.ServerTool{Tool: search}))
+	assert.Equal(t, ClassWrite, ClassifyTool(&server.ServerTool{Tool: write}))
+	assert.Equal(t, ClassRead, ClassifyTool(nil))
+}
+
+func TestUnitBuildMiddleware(t *testing.T) {
+	m := New(Config{PerMinute: map[Class]int{ClassRead: 1}}, zap.NewNop())
+	calls := 0
+	handler := BuildMiddleware(m, func(string) Class { return ClassRead })(
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls++
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "channels_list"
+
+	res, err := handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, res.IsError)
+
+	res, err = handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+	assert.Equal(t, 1, calls)
+
+	throttled, ok := res.StructuredContent.(*ThrottledError)
+	require.True(t, ok)
+	assert.Equal(t, 60, throttled.RetryAfterSeconds)
+	assert.Equal(t, "anonymous", m.Stats()[0].Client)
+}
@@ -2,13 +2,15 @@ package server
 
 import (
 	"context"
-	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/korotovsky/slack-mcp-server/pkg/handler"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/quota"
 	"github.com/korotovsky/slack-mcp-server/pkg/text"
 	"github.com/korotovsky/slack-mcp-server/pkg/version"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -16,21 +18,46 @@ import (
 	"go.uber.org/zap"
 )
 
+const httpEndpointPath = "/mcp"
+
 type MCPServer struct {
 	server *server.MCPServer
 	logger *zap.Logger
 }
 
 func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger) *MCPServer {
-	s := server.NewMCPServer(
+	quotaCfg, err := quota.ConfigFromEnv()
+	if err != nil {
+		logger.Fatal("Invalid rate limit configuration",
+			zap.String("context", "console"),
+			zap.Error(err),
+		)
+	}
+	quotas := quota.New(quotaCfg, logger)
+
+	var s *server.MCPServer
+	s = server.NewMCPServer(
 		"Slack MCP Server",
 		version.Version,
 		server.WithLogging(),
 		server.WithRecovery(),
 		server.WithToolHandlerMiddleware(buildLoggerMiddleware(logger)),
 		server.WithToolHandlerMiddleware(auth.BuildMiddleware(provider.ServerTransport(), logger)),
+		server.WithToolHandlerMiddleware(quota.BuildMiddleware(quotas, func(name string) quota.Class {
+			return quota.ClassifyTool(s.GetTool(name))
+		})),
 	)
 
+	if quotaCfg.Enabled() {
+		logger.Info("Per-client tool call quotas enabled",
+			zap.String("context", "console"),
+			zap.Int("read_per_minute", quotaCfg.PerMinute[quota.ClassRead]),
+			zap.Int("search_per_minute", quotaCfg.PerMinute[quota.ClassSearch]),
+			zap.Int("write_per_minute", quotaCfg.PerMinute[quota.ClassWrite]),
+			zap.Int("max_in_flight", quotaCfg.MaxInFlight),
+		)
+	}
+
 	conversationsHandler := handler.NewConversationsHandler(provider, logger)
 
 	s.AddTool(mcp.NewTool("conversations_history",
@@ -39,7 +66,7 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger) *MCPServer
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("channel_id",
 			mcp.Required(),
-			mcp.Description("    - `channel_id` (string): ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			mcp.Description("    - `channel_id` (string): ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm. A message permalink is accepted too."),
 		),
 		mcp.WithBoolean("include_activity_messages",
 			mcp.Description("If true, the response will include activity messages such as 'channel_join' or 'channel_leave'. Default is boolean false."),
@@ -52,6 +79,14 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger) *MCPServer
 			mcp.DefaultString("1d"),
 			mcp.Description("Limit of messages to fetch in format of maximum ranges of time (e.g. 1d - 1 day, 1w - 1 week, 30d - 30 days, 90d - 90 days which is a default limit for free tier history) or number of messages (e.g. 50). Must be empty when 'cursor' is provided."),
 		),
+		mcp.WithBoolean("expand_threads",
+			mcp.Description("If true, the replies of threads started in the returned messages are listed right after their parent message. Parents whose replies are not all listed have truncated set to true, use conversations_replies to read them. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithNumber("max_threads",
+			mcp.DefaultNumber(10),
+			mcp.Description("Maximum number of threads to expand when 'expand_threads' is true, at most 50. Default is 10."),
+		),
 	), conversationsHandler.ConversationsHistoryHandler)
 
 	s.AddTool(mcp.NewTool("conversations_replies",
@@ -60,11 +95,10 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger) *MCPServer
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("channel_id",
 			mcp.Required(),
-			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm. A permalink of a message in the thread is accepted too, thread_ts can then be omitted."),
 		),
 		mcp.WithString("thread_ts",
-			mcp.Required(),
-			mcp.Description("Unique identifier of either a thread's parent message or a message in the thread. ts must be the timestamp in format 1234567890.123456 of an existing message with 0 or more replies."),
+			mcp.Description("Unique identifier of either a thread's parent message or a message in the thread. ts must be the timestamp in format 1234567890.123456 of an existing message with 0 or more replies, or the message's permalink. Required unless channel_id is a permalink."),
 		),
 		mcp.WithBoolean("include_activity_messages",
 			mcp.Description("If true, the response will include activity messages such as 'channel_join' or 'channel_leave'. Default is boolean false."),
@@ -79,6 +113,16 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger) *MCPServer
 		),
 	), conversationsHandler.ConversationsRepliesHandler)
 
+	s.AddTool(mcp.NewTool("messages_get",
+		mcp.WithDescription("Get messages by their permalinks or channel_id:ts pairs. A message in a thread is returned with its thread, the parent message followed by the replies."),
+		mcp.WithTitleAnnotation("Get Messages"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("messages",
+			mcp.Required(),
+			mcp.Description("Comma-separated list of up to 20 messages, each a permalink (e.g. https://example.slack.com/archives/C0123456789/p1234567890123456) or a channel and ts pair such as #general:1234567890.123456 or C0123456789:1234567890.123456."),
+		),
+	), conversationsHandler.MessagesGetHandler)
+
 	s.AddTool(mcp.NewTool("conversations_add_message",
 		mcp.WithDescription("Add a message to a public channel, private channel, or direct message (DM, or IM) conversation by channel_id and thread_ts."),
 		mcp.WithTitleAnnotation("Send Message"),
@@ -143,6 +187,64 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger) *MCPServer
 		),
 	), conversationsHandler.FilesGetHandler)
 
+	s.AddTool(mcp.NewTool("conversations_export",
+		mcp.WithDescription("Export the full history of a channel or DM, including all thread replies, to a file on the server in SLACK_MCP_EXPORT_DIR. Returns the path of the export and how many messages it holds."),
+		mcp.WithTitleAnnotation("Export Conversation"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+		),
+		mcp.WithString("date_from",
+			mcp.Description("Export messages from this day on, in format 'YYYY-MM-DD'. Example: '2024-01-31', 'July' or 'Yesterday'. If not provided the export starts with the oldest message."),
+		),
+		mcp.WithString("date_to",
+			mcp.Description("Export messages up to and including this day, in format 'YYYY-MM-DD'. If not provided the export ends with the newest message."),
+		),
+		mcp.WithString("format",
+			mcp.DefaultString("jsonl"),
+			mcp.Description("Output format. Allowed values: 'jsonl' - one message per line, 'slack' - Slack export compatible JSON directory, 'html' - a single HTML page with user names resolved."),
+		),
+		mcp.WithBoolean("include_files",
+			mcp.Description("If true, attached files are downloaded next to the export. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+	), conversationsHandler.ConversationsExportHandler)
+
+	// Bot users have no read state of their own
+	if !provider.IsBotToken() {
+		s.AddTool(mcp.NewTool("inbox_unreads",
+			mcp.WithDescription("List the conversations that need attention: DMs and group DMs with unread messages first, then channels where you were mentioned, then other channels with unread messages. Returns CSV with channel ID, name, type, mention count and the timestamps of the last read and the latest message."),
+			mcp.WithTitleAnnotation("Get Unread Conversations"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithBoolean("include_messages",
+				mcp.Description("If true, the unread messages of each conversation are returned as a second CSV, newest first. Default is boolean false."),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithNumber("max_messages",
+				mcp.DefaultNumber(10),
+				mcp.Description("The maximum number of unread messages to return per conversation when include_messages is true. Must be an integer between 1 and 100."),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(50),
+				mcp.Description("The maximum number of conversations to return."),
+			),
+		), conversationsHandler.InboxUnreadsHandler)
+
+		s.AddTool(mcp.NewTool("conversations_mark_read",
+			mcp.WithDescription("Mark a channel or DM as read up to a message, or up to its newest message."),
+			mcp.WithTitleAnnotation("Mark Conversation Read"),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("ts",
+				mcp.Description("Timestamp of the last message to mark as read, in format 1234567890.123456. If not provided the conversation is marked as read up to its newest message."),
+			),
+		), conversationsHandler.ConversationsMarkReadHandler)
+	}
+
 	conversationsSearchTool := mcp.NewTool("conversations_search_messages",
 		mcp.WithDescription("Search messages in a public channel, private channel, or direct message (DM, or IM) conversation using filters. All filters are optional, if not provided then search_query is required."),
 		mcp.WithTitleAnnotation("Search Messages"),
@@ -186,9 +288,24 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger) *MCPServer
 			mcp.Description("The maximum number of items to return. Must be an integer between 1 and 100."),
 		),
 	)
-	// Only register search tool for non-bot tokens (bot tokens cannot use search.messages API)
-	if !provider.IsBotToken() {
+	// Bot tokens cannot use the search.messages API, they search the local
+	// message cache instead when it's enabled
+	if !provider.IsBotToken() || provider.HasLocalSearch() {
 		s.AddTool(conversationsSearchTool, conversationsHandler.ConversationsSearchHandler)
+
+		s.AddTool(mcp.NewTool("activity_mentions",
+			mcp.WithDescription("Find messages that need your attention within a time window: mentions of you or of your user groups, and replies in threads you posted in. Results are deduplicated and grouped by thread, most recent activity first; each row has a permalink and tells whether you already replied."),
+			mcp.WithTitleAnnotation("Get Mentions and Activity"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("since",
+				mcp.DefaultString("1d"),
+				mcp.Description("Start of the time window, either a range back from today (e.g. 1d - 1 day, 1w - 1 week, 1m - 1 month) or a date in format 'YYYY-MM-DD'. Example: '7d', '2024-01-31', 'July' or 'Yesterday'."),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(100),
+				mcp.Description("The maximum number of messages to return."),
+			),
+		), conversationsHandler.ActivityMentionsHandler)
 	}
 
 	s.AddTool(mcp.NewTool("users_search",
@@ -372,7 +489,37 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger) *MCPServer
 			mcp.Description("Comma-separated channel types. Allowed values: 'mpim', 'im', 'public_channel', 'private_channel'. Example: 'public_channel,private_channel,im'"),
 		),
 		mcp.WithString("sort",
-			mcp.Description("Type of sorting. Allowed values: 'popularity' - sort by number of members/participants in each channel."),
+			mcp.Description("Type of sorting. Allowed values: 'popularity' - sort by number of members/participants in each channel, 'name' - alphabetically, 'created' - newest channels first, 'activity' - most recent message first (not available for bot tokens)."),
+		),
+		mcp.WithString("name",
+			mcp.Description("Only channels whose name contains this text, case-insensitive. Example: 'eng' matches #eng-backend and #team-eng."),
+		),
+		mcp.WithString("name_regex",
+			mcp.Description("Only channels whose name, without the leading # or @, matches this case-insensitive regular expression. Example: '^inc-[0-9]+$'."),
+		),
+		mcp.WithString("text",
+			mcp.Description("Only channels whose topic or purpose contains this text, case-insensitive."),
+		),
+		mcp.WithBoolean("member_only",
+			mcp.Description("If true, only channels the authenticated user is a member of."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("archived",
+			mcp.Description("Archived channels: 'exclude' (default), 'include' or 'only'. Archived channels are only cached when SLACK_MCP_CHANNELS_INCLUDE_ARCHIVED is set."),
+			mcp.DefaultString("exclude"),
+		),
+		mcp.WithString("shared",
+			mcp.Description("Shared channels: 'any' (default), 'shared' - shared with other workspaces or organizations, 'external' - shared with other organizations through Slack Connect, 'internal' - not shared."),
+			mcp.DefaultString("any"),
+		),
+		mcp.WithString("team",
+			mcp.Description("Only channels connected to this team ID, e.g. the workspace of a Slack Connect partner."),
+		),
+		mcp.WithNumber("min_members",
+			mcp.Description("Only channels with at least this many members."),
+		),
+		mcp.WithNumber("max_members",
+			mcp.Description("Only channels with at most this many members."),
 		),
 		mcp.WithNumber("limit",
 			mcp.DefaultNumber(100),
@@ -383,6 +530,385 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger) *MCPServer
 		),
 	), channelsHandler.ChannelsHandler)
 
+	s.AddTool(mcp.NewTool("channels_create",
+		mcp.WithDescription("Create a public or private channel, optionally setting its topic and purpose. Requires SLACK_MCP_CHANNEL_ADMIN_TOOL=true."),
+		mcp.WithTitleAnnotation("Create Channel"),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the channel, lowercase without spaces or periods, at most 80 characters. Example: 'inc-1234'."),
+		),
+		mcp.WithBoolean("is_private",
+			mcp.Description("If true, a private channel is created. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("topic",
+			mcp.Description("Topic of the new channel."),
+		),
+		mcp.WithString("purpose",
+			mcp.Description("Purpose of the new channel."),
+		),
+	), channelsHandler.ChannelsCreateHandler)
+
+	s.AddTool(mcp.NewTool("channels_archive",
+		mcp.WithDescription("Archive a channel. Requires SLACK_MCP_CHANNEL_ADMIN_TOOL=true."),
+		mcp.WithTitleAnnotation("Archive Channel"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+		),
+	), channelsHandler.ChannelsArchiveHandler)
+
+	s.AddTool(mcp.NewTool("channels_unarchive",
+		mcp.WithDescription("Unarchive a channel. Requires SLACK_MCP_CHANNEL_ADMIN_TOOL=true."),
+		mcp.WithTitleAnnotation("Unarchive Channel"),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the archived channel in format Cxxxxxxxxxx, or its name starting with #... aka #general when SLACK_MCP_CHANNELS_INCLUDE_ARCHIVED is set, as archived channels are only cached then."),
+		),
+	), channelsHandler.ChannelsUnarchiveHandler)
+
+	s.AddTool(mcp.NewTool("channels_rename",
+		mcp.WithDescription("Rename a channel. Requires SLACK_MCP_CHANNEL_ADMIN_TOOL=true."),
+		mcp.WithTitleAnnotation("Rename Channel"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+		),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("New name of the channel, lowercase without spaces or periods, at most 80 characters."),
+		),
+	), channelsHandler.ChannelsRenameHandler)
+
+	s.AddTool(mcp.NewTool("channels_set_topic",
+		mcp.WithDescription("Set the topic of a channel. Requires SLACK_MCP_CHANNEL_ADMIN_TOOL=true."),
+		mcp.WithTitleAnnotation("Set Channel Topic"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+		),
+		mcp.WithString("topic",
+			mcp.Description("New topic, empty to clear it."),
+		),
+	), channelsHandler.ChannelsSetTopicHandler)
+
+	s.AddTool(mcp.NewTool("channels_set_purpose",
+		mcp.WithDescription("Set the purpose of a channel. Requires SLACK_MCP_CHANNEL_ADMIN_TOOL=true."),
+		mcp.WithTitleAnnotation("Set Channel Purpose"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+		),
+		mcp.WithString("purpose",
+			mcp.Description("New purpose, empty to clear it."),
+		),
+	), channelsHandler.ChannelsSetPurposeHandler)
+
+	s.AddTool(mcp.NewTool("channels_members",
+		mcp.WithDescription("List the members of a channel with their names, the last row/column in the response is used as 'cursor' parameter for pagination if not empty"),
+		mcp.WithTitleAnnotation("List Channel Members"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+		),
+		mcp.WithNumber("limit",
+			mcp.DefaultNumber(100),
+			mcp.Description("The maximum number of members to return, at most 1000."),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("Cursor for pagination. Use the value of the last row and column in the response as next_cursor field returned from the previous request."),
+		),
+	), channelsHandler.ChannelsMembersHandler)
+
+	s.AddTool(mcp.NewTool("channels_join",
+		mcp.WithDescription("Join a public channel. Requires SLACK_MCP_CHANNEL_ADMIN_TOOL=true."),
+		mcp.WithTitleAnnotation("Join Channel"),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+		),
+	), channelsHandler.ChannelsJoinHandler)
+
+	s.AddTool(mcp.NewTool("channels_leave",
+		mcp.WithDescription("Leave a channel. Requires SLACK_MCP_CHANNEL_ADMIN_TOOL=true."),
+		mcp.WithTitleAnnotation("Leave Channel"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+		),
+	), channelsHandler.ChannelsLeaveHandler)
+
+	s.AddTool(mcp.NewTool("channels_invite",
+		mcp.WithDescription("Invite users to a channel. Requires SLACK_MCP_CHANNEL_ADMIN_TOOL=true."),
+		mcp.WithTitleAnnotation("Invite to Channel"),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+		),
+		mcp.WithString("users",
+			mcp.Required(),
+			mcp.Description("Comma-separated user IDs or usernames, e.g. 'U0123456789,@alice'. At most 1000 users."),
+		),
+	), channelsHandler.ChannelsInviteHandler)
+
+	s.AddTool(mcp.NewTool("channels_kick",
+		mcp.WithDescription("Remove a user from a channel. Requires SLACK_MCP_CHANNEL_ADMIN_TOOL=true."),
+		mcp.WithTitleAnnotation("Remove from Channel"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+		),
+		mcp.WithString("user",
+			mcp.Required(),
+			mcp.Description("User ID or username of the user to remove, e.g. 'U0123456789' or '@alice'."),
+		),
+	), channelsHandler.ChannelsKickHandler)
+
+	s.AddTool(mcp.NewTool("channels_info",
+		mcp.WithDescription("Get the metadata of a channel as JSON: creator, creation date, archived and Slack Connect status with the external teams, previous names, member count, last activity, pinned item count and bookmarks."),
+		mcp.WithTitleAnnotation("Get Channel Info"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+		),
+	), channelsHandler.ChannelsInfoHandler)
+
+	s.AddTool(mcp.NewTool("pins_list",
+		mcp.WithDescription("Get the messages pinned to a channel or DM, in the same CSV format as conversations_history."),
+		mcp.WithTitleAnnotation("List Pinned Messages"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+		),
+	), conversationsHandler.PinsListHandler)
+
+	s.AddTool(mcp.NewTool("pins_add",
+		mcp.WithDescription("Pin a message to its channel or DM. Requires SLACK_MCP_PIN_TOOL."),
+		mcp.WithTitleAnnotation("Pin Message"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+		),
+		mcp.WithString("timestamp",
+			mcp.Required(),
+			mcp.Description("Timestamp of the message to pin, in format 1234567890.123456."),
+		),
+	), conversationsHandler.PinsAddHandler)
+
+	s.AddTool(mcp.NewTool("pins_remove",
+		mcp.WithDescription("Unpin a message from its channel or DM. Requires SLACK_MCP_PIN_TOOL."),
+		mcp.WithTitleAnnotation("Unpin Message"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+		),
+		mcp.WithString("timestamp",
+			mcp.Required(),
+			mcp.Description("Timestamp of the message to unpin, in format 1234567890.123456."),
+		),
+	), conversationsHandler.PinsRemoveHandler)
+
+	s.AddTool(mcp.NewTool("bookmarks_list",
+		mcp.WithDescription("Get the bookmarks in the header of a channel or DM as CSV with their ID, title, link and type."),
+		mcp.WithTitleAnnotation("List Channel Bookmarks"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+		),
+	), conversationsHandler.BookmarksListHandler)
+
+	s.AddTool(mcp.NewTool("bookmarks_add",
+		mcp.WithDescription("Add a link bookmark to the header of a channel or DM. Requires SLACK_MCP_PIN_TOOL."),
+		mcp.WithTitleAnnotation("Add Bookmark"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+		),
+		mcp.WithString("title",
+			mcp.Required(),
+			mcp.Description("Title of the bookmark."),
+		),
+		mcp.WithString("link",
+			mcp.Required(),
+			mcp.Description("URL the bookmark points to."),
+		),
+		mcp.WithString("emoji",
+			mcp.Description("Optional emoji shown next to the bookmark, e.g. ':book:'."),
+		),
+	), conversationsHandler.BookmarksAddHandler)
+
+	s.AddTool(mcp.NewTool("bookmarks_remove",
+		mcp.WithDescription("Remove a bookmark from the header of a channel or DM. Requires SLACK_MCP_PIN_TOOL."),
+		mcp.WithTitleAnnotation("Remove Bookmark"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+		),
+		mcp.WithString("bookmark_id",
+			mcp.Required(),
+			mcp.Description("ID of the bookmark as returned by bookmarks_list, e.g. Bk0123456789."),
+		),
+	), conversationsHandler.BookmarksRemoveHandler)
+
+	// reminders.* is not available to bot tokens
+	if !provider.IsBotToken() {
+		remindersHandler := handler.NewRemindersHandler(provider, logger)
+
+		s.AddTool(mcp.NewTool("reminders_add",
+			mcp.WithDescription("Create a Slackbot reminder for yourself or another user. Returns CSV with the reminder ID, user, text and due time in the user's timezone. Requires SLACK_MCP_REMINDER_TOOL=true."),
+			mcp.WithTitleAnnotation("Add Reminder"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("text",
+				mcp.Required(),
+				mcp.Description("What to be reminded about."),
+			),
+			mcp.WithString("time",
+				mcp.Required(),
+				mcp.Description("When to remind, in the timezone of the reminded user. Example: 'in 2h', 'in 1 day 3 hours', 'tomorrow at 9am', 'friday 17:00', '2026-03-01 at 10:30', '3pm' or an RFC 3339 timestamp. A day without a time means 9am. Phrases starting with 'every' create a recurring reminder, e.g. 'every weekday at 9am'."),
+			),
+			mcp.WithString("user",
+				mcp.Description("User ID or username of the user to remind, e.g. 'U0123456789' or '@alice'. If not provided the reminder is for yourself."),
+			),
+		), remindersHandler.RemindersAddHandler)
+
+		s.AddTool(mcp.NewTool("reminders_list",
+			mcp.WithDescription("List the reminders created by or for you, soonest first. Returns CSV with the reminder ID, user, text, due time, whether it is recurring and when it was completed."),
+			mcp.WithTitleAnnotation("List Reminders"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithBoolean("include_completed",
+				mcp.Description("If true, completed reminders are listed as well. Default is boolean false."),
+				mcp.DefaultBool(false),
+			),
+		), remindersHandler.RemindersListHandler)
+
+		s.AddTool(mcp.NewTool("reminders_complete",
+			mcp.WithDescription("Mark a reminder as complete. Requires SLACK_MCP_REMINDER_TOOL=true."),
+			mcp.WithTitleAnnotation("Complete Reminder"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("reminder_id",
+				mcp.Required(),
+				mcp.Description("ID of the reminder as returned by reminders_list, e.g. Rm0123456789."),
+			),
+		), remindersHandler.RemindersCompleteHandler)
+
+		s.AddTool(mcp.NewTool("reminders_delete",
+			mcp.WithDescription("Delete a reminder. Requires SLACK_MCP_REMINDER_TOOL=true."),
+			mcp.WithTitleAnnotation("Delete Reminder"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("reminder_id",
+				mcp.Required(),
+				mcp.Description("ID of the reminder as returned by reminders_list, e.g. Rm0123456789."),
+			),
+		), remindersHandler.RemindersDeleteHandler)
+	}
+
+	usersHandler := handler.NewUsersHandler(provider, logger)
+
+	s.AddTool(mcp.NewTool("users_get_presence",
+		mcp.WithDescription("Check whether users can be reached right now before pinging them. Returns CSV with presence (active or away), custom status text and emoji with its expiration, whether they are in Do Not Disturb, their next DND window and snooze end, and their local time and timezone."),
+		mcp.WithTitleAnnotation("Get User Presence"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("users",
+			mcp.Description("Comma-separated user IDs or usernames, e.g. 'U0123456789,@alice'. At most 50. If not provided your own presence is returned."),
+		),
+	), usersHandler.UsersGetPresenceHandler)
+
+	s.AddTool(mcp.NewTool("users_get_profile",
+		mcp.WithDescription("Get the full profile of a user as JSON: names, email, title, phone, pronouns, manager, status, timezone with current local time and UTC offset, and the workspace's custom profile fields (e.g. team, location) with their labels."),
+		mcp.WithTitleAnnotation("Get User Profile"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("user",
+			mcp.Description("User ID or username, e.g. 'U0123456789' or '@alice'. If not provided your own profile is returned."),
+		),
+	), usersHandler.UsersGetProfileHandler)
+
+	// Status and DND can only be changed with a user token
+	if !provider.IsBotToken() {
+		s.AddTool(mcp.NewTool("users_set_status",
+			mcp.WithDescription("Set or clear your custom status. Leave status_text and status_emoji empty to clear it. Requires SLACK_MCP_STATUS_TOOL=true."),
+			mcp.WithTitleAnnotation("Set Status"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("status_text",
+				mcp.Description("Status text, e.g. 'On vacation'."),
+			),
+			mcp.WithString("status_emoji",
+				mcp.Description("Status emoji with or without colons, e.g. 'palm_tree'. Slack uses :speech_balloon: when only a text is set."),
+			),
+			mcp.WithString("expiration",
+				mcp.Description("When the status is cleared, in your timezone. Example: 'in 2h', 'tomorrow at 9am', 'friday 17:00' or an RFC 3339 timestamp. If not provided the status doesn't expire."),
+			),
+		), usersHandler.UsersSetStatusHandler)
+
+		s.AddTool(mcp.NewTool("dnd_set_snooze",
+			mcp.WithDescription("Pause your notifications for a number of minutes, or turn them back on with 0. Requires SLACK_MCP_STATUS_TOOL=true."),
+			mcp.WithTitleAnnotation("Snooze Notifications"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithNumber("minutes",
+				mcp.Required(),
+				mcp.Description("Minutes to snooze notifications for, between 1 and 1440, or 0 to end the current snooze."),
+			),
+		), usersHandler.DNDSetSnoozeHandler)
+	}
+
+	userGroupsHandler := handler.NewUserGroupsHandler(provider, logger)
+
+	s.AddTool(mcp.NewTool("usergroups_list",
+		mcp.WithDescription("List user groups (e.g. @oncall-platform) of the workspace. Returns CSV with ID, handle, name, description and member count."),
+		mcp.WithTitleAnnotation("List User Groups"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Description("Only list groups whose handle, name or description contains this text, e.g. 'oncall'."),
+		),
+	), userGroupsHandler.UserGroupsListHandler)
+
+	s.AddTool(mcp.NewTool("usergroups_members",
+		mcp.WithDescription("List the members of a user group. Returns CSV with user IDs, usernames and real names."),
+		mcp.WithTitleAnnotation("List User Group Members"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("usergroup",
+			mcp.Required(),
+			mcp.Description("ID or handle of the user group, e.g. 'S0123456789' or '@oncall-platform'."),
+		),
+	), userGroupsHandler.UserGroupsMembersHandler)
+
+	s.AddTool(mcp.NewTool("usergroups_update_members",
+		mcp.WithDescription("Add people to or remove them from a user group. Requires SLACK_MCP_USERGROUP_TOOL=true."),
+		mcp.WithTitleAnnotation("Update User Group Members"),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("usergroup",
+			mcp.Required(),
+			mcp.Description("ID or handle of the user group, e.g. 'S0123456789' or '@oncall-platform'."),
+		),
+		mcp.WithString("add",
+			mcp.Description("Comma-separated user IDs or usernames to add, e.g. 'U0123456789,@alice'."),
+		),
+		mcp.WithString("remove",
+			mcp.Description("Comma-separated user IDs or usernames to remove."),
+		),
+	), userGroupsHandler.UserGroupsUpdateMembersHandler)
+
+	if provider.IsArchive() {
+		disableWriteTools(s, logger)
+	}
+
 	logger.Info("Authenticating with Slack API...",
 		zap.String("context", "console"),
 	)
@@ -425,22 +951,32 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger) *MCPServer
 		mcp.WithMIMEType("text/csv"),
 	), conversationsHandler.UsersResource)
 
+	s.AddResource(mcp.NewResource(
+		"slack://"+ws+"/status",
+		"Server status",
+		mcp.WithResourceDescription("This resource provides your tool call counters and quotas."),
+		mcp.WithMIMEType("text/csv"),
+	), statusResource(quotas))
+
 	return &MCPServer{
 		server: s,
 		logger: logger,
 	}
 }
 
-func (s *MCPServer) ServeSSE(addr string) *server.SSEServer {
+func (s *MCPServer) ServeSSE(addr string, cfg HTTPConfig) *server.SSEServer {
 	s.logger.Info("Creating SSE server",
 		zap.String("context", "console"),
 		zap.String("version", version.Version),
 		zap.String("build_time", version.BuildTime),
 		zap.String("commit_hash", version.CommitHash),
 		zap.String("address", addr),
+		zap.String("base_url", cfg.BaseURL),
+		zap.String("base_path", cfg.BasePath),
 	)
 	return server.NewSSEServer(s.server,
-		server.WithBaseURL(fmt.Sprintf("http://%s", addr)),
+		server.WithBaseURL(cfg.BaseURL),
+		server.WithStaticBasePath(cfg.BasePath),
 		server.WithSSEContextFunc(func(ctx context.Context, r *http.Request) context.Context {
 			ctx = auth.AuthFromRequest(s.logger)(ctx, r)
 
@@ -449,16 +985,18 @@ func (s *MCPServer) ServeSSE(addr string) *server.SSEServer {
 	)
 }
 
-func (s *MCPServer) ServeHTTP(addr string) *server.StreamableHTTPServer {
+func (s *MCPServer) ServeHTTP(addr string, cfg HTTPConfig) *server.StreamableHTTPServer {
 	s.logger.Info("Creating HTTP server",
 		zap.String("context", "console"),
 		zap.String("version", version.Version),
 		zap.String("build_time", version.BuildTime),
 		zap.String("commit_hash", version.CommitHash),
 		zap.String("address", addr),
+		zap.String("base_url", cfg.BaseURL),
+		zap.String("base_path", cfg.BasePath),
 	)
 	return server.NewStreamableHTTPServer(s.server,
-		server.WithEndpointPath("/mcp"),
+		server.WithEndpointPath(cfg.BasePath+httpEndpointPath),
 		server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
 			ctx = auth.AuthFromRequest(s.logger)(ctx, r)
 
@@ -467,6 +1005,44 @@ func (s *MCPServer) ServeHTTP(addr string) *server.StreamableHTTPServer {
 	)
 }
 
+// StartSSE serves the SSE transport on addr, or on the Unix socket and with
+// the TLS settings described by cfg. Browser origins are checked against httpCfg.
+func (s *MCPServer) StartSSE(sseServer *server.SSEServer, addr string, cfg ListenerConfig, httpCfg HTTPConfig) error {
+	return s.listenAndServe(addr, cfg, originMiddleware(httpCfg, s.logger, sseServer))
+}
+
+// StartHTTP serves the Streamable HTTP transport on addr, or on the Unix
+// socket and with the TLS settings described by cfg. Browser origins are
+// checked against httpCfg.
+func (s *MCPServer) StartHTTP(httpServer *server.StreamableHTTPServer, addr string, cfg ListenerConfig, httpCfg HTTPConfig) error {
+	mux := http.NewServeMux()
+	mux.Handle(httpCfg.BasePath+httpEndpointPath, httpServer)
+
+	return s.listenAndServe(addr, cfg, originMiddleware(httpCfg, s.logger, mux))
+}
+
+func (s *MCPServer) listenAndServe(addr string, cfg ListenerConfig, handler http.Handler) error {
+	ln, err := newListener(addr, cfg, s.logger)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Listener started",
+		zap.String("context", "console"),
+		zap.String("network", ln.Addr().Network()),
+		zap.String("address", ln.Addr().String()),
+		zap.Bool("tls", cfg.TLSEnabled()),
+		zap.Bool("mtls", cfg.ClientCAFile != ""),
+	)
+
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return srv.Serve(ln)
+}
+
 func (s *MCPServer) ServeStdio() error {
 	s.logger.Info("Starting STDIO server",
 		zap.String("version", version.Version),
@@ -503,3 +1079,21 @@ func buildLoggerMiddleware(logger *zap.Logger) server.ToolHandlerMiddleware {
 		}
 	}
 }
+
+// disableWriteTools removes every tool that changes the workspace, an archive
+// is read-only. Lists are not part of Slack exports, so their tools go too.
+func disableWriteTools(s *server.MCPServer, logger *zap.Logger) {
+	var names []string
+	for name, tool := range s.ListTools() {
+		if quota.ClassifyTool(tool) == quota.ClassWrite || strings.HasPrefix(name, "lists_") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	s.DeleteTools(names...)
+
+	logger.Info("Serving an archive, write tools are disabled",
+		zap.String("context", "console"),
+		zap.Strings("tools", names),
+	)
+}
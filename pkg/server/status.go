@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/quota"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// statusResource serves the quota counters of the calling client as CSV,
+// other clients' usage is not shown.
+func statusResource(quotas *quota.Manager) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		id := quota.ClientID(ctx)
+		stats := []quota.Stats{}
+		for _, st := range quotas.Stats() {
+			if st.Client == id {
+				stats = append(stats, st)
+			}
+		}
+
+		csvBytes, err := gocsv.MarshalBytes(&stats)
+		if err != nil {
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "text/csv",
+				Text:     string(csvBytes),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,214 @@
+package fakeslack
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+func (s *Server) conversationsInfo(w http.ResponseWriter, req request) {
+	ch := s.channelByID(req.get("channel"))
+	if ch == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+	writeOK(w, map[string]any{"channel": ch})
+}
+
+func (s *Server) channelByName(name string) *slack.Channel {
+	for _, ch := range s.channels {
+		if ch.Name != "" && ch.Name == name {
+			return ch
+		}
+	}
+	return nil
+}
+
+// validChannelName checks the rules Slack applies to channel names.
+func validChannelName(name string) bool {
+	if name == "" || len(name) > 80 || strings.ToLower(name) != name {
+		return false
+	}
+	return !strings.ContainsAny(name, " .#")
+}
+
+func (s *Server) conversationsCreate(w http.ResponseWriter, req request) {
+	name := req.get("name")
+	if !validChannelName(name) {
+		writeError(w, "invalid_name_specials")
+		return
+	}
+	if s.channelByName(name) != nil {
+		writeError(w, "name_taken")
+		return
+	}
+
+	s.seq++
+	id := fmt.Sprintf("C9%05d", s.seq)
+	if req.get("is_private") == "true" {
+		id = fmt.Sprintf("G9%05d", s.seq)
+	}
+	ch := newChannel(id, name, "", []string{s.selfID})
+	ch.IsPrivate = req.get("is_private") == "true"
+	ch.Creator = s.selfID
+	s.channels = append(s.channels, ch)
+	writeOK(w, map[string]any{"channel": ch})
+}
+
+func (s *Server) conversationsArchive(w http.ResponseWriter, req request) {
+	s.setArchived(w, req, true)
+}
+
+func (s *Server) conversationsUnarchive(w http.ResponseWriter, req request) {
+	s.setArchived(w, req, false)
+}
+
+func (s *Server) setArchived(w http.ResponseWriter, req request, archived bool) {
+	ch := s.channelByID(req.get("channel"))
+	switch {
+	case ch == nil:
+		writeError(w, "channel_not_found")
+	case ch.IsIM || ch.IsMpIM:
+		writeError(w, "method_not_supported_for_channel_type")
+	case ch.IsArchived == archived && archived:
+		writeError(w, "already_archived")
+	case ch.IsArchived == archived:
+		writeError(w, "not_archived")
+	default:
+		ch.IsArchived = archived
+		writeOK(w, nil)
+	}
+}
+
+func (s *Server) conversationsRename(w http.ResponseWriter, req request) {
+	ch := s.channelByID(req.get("channel"))
+	name := req.get("name")
+	switch {
+	case ch == nil:
+		writeError(w, "channel_not_found")
+	case !validChannelName(name):
+		writeError(w, "invalid_name_specials")
+	case s.channelByName(name) != nil && s.channelByName(name) != ch:
+		writeError(w, "name_taken")
+	default:
+		ch.Name = name
+		ch.NameNormalized = name
+		writeOK(w, map[string]any{"channel": ch})
+	}
+}
+
+func (s *Server) conversationsSetTopic(w http.ResponseWriter, req request) {
+	ch := s.channelByID(req.get("channel"))
+	if ch == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+	ch.Topic.Value = req.get("topic")
+	ch.Topic.Creator = s.selfID
+	writeOK(w, map[string]any{"channel": ch})
+}
+
+func (s *Server) conversationsSetPurpose(w http.ResponseWriter, req request) {
+	ch := s.channelByID(req.get("channel"))
+	if ch == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+	ch.Purpose.Value = req.get("purpose")
+	ch.Purpose.Creator = s.selfID
+	writeOK(w, map[string]any{"channel": ch})
+}
+
+func (s *Server) conversationsMembers(w http.ResponseWriter, req request) {
+	ch := s.channelByID(req.get("channel"))
+	if ch == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+	limit, _ := strconv.Atoi(req.get("limit"))
+	start, end, next := paginate(len(ch.Members), req.get("cursor"), limit)
+	writeOK(w, map[string]any{
+		"members":           ch.Members[start:end],
+		"response_metadata": map[string]string{"next_cursor": next},
+	})
+}
+
+// setMembers replaces the members of ch and keeps its counters in line.
+func (s *Server) setMembers(ch *slack.Channel, members []string) {
+	ch.Members = members
+	ch.NumMembers = len(members)
+	ch.IsMember = contains(members, s.selfID)
+}
+
+func (s *Server) conversationsJoin(w http.ResponseWriter, req request) {
+	ch := s.channelByID(req.get("channel"))
+	switch {
+	case ch == nil:
+		writeError(w, "channel_not_found")
+	case ch.IsPrivate || ch.IsIM || ch.IsMpIM:
+		writeError(w, "method_not_supported_for_channel_type")
+	case ch.IsArchived:
+		writeError(w, "is_archived")
+	default:
+		if !contains(ch.Members, s.selfID) {
+			s.setMembers(ch, append(ch.Members, s.selfID))
+		}
+		writeOK(w, map[string]any{"channel": ch})
+	}
+}
+
+func (s *Server) conversationsLeave(w http.ResponseWriter, req request) {
+	ch := s.channelByID(req.get("channel"))
+	switch {
+	case ch == nil:
+		writeError(w, "channel_not_found")
+	case ch.IsIM:
+		writeError(w, "method_not_supported_for_channel_type")
+	case !contains(ch.Members, s.selfID):
+		writeOK(w, map[string]any{"not_in_channel": true})
+	default:
+		s.setMembers(ch, without(ch.Members, s.selfID))
+		writeOK(w, nil)
+	}
+}
+
+func (s *Server) conversationsInvite(w http.ResponseWriter, req request) {
+	ch := s.channelByID(req.get("channel"))
+	if ch == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+	members := append([]string(nil), ch.Members...)
+	for _, id := range strings.Split(req.get("users"), ",") {
+		if _, ok := s.userByID(id); !ok {
+			writeError(w, "user_not_found")
+			return
+		}
+		if contains(members, id) {
+			writeError(w, "already_in_channel")
+			return
+		}
+		members = append(members, id)
+	}
+	s.setMembers(ch, members)
+	writeOK(w, map[string]any{"channel": ch})
+}
+
+func (s *Server) conversationsKick(w http.ResponseWriter, req request) {
+	ch := s.channelByID(req.get("channel"))
+	user := req.get("user")
+	switch {
+	case ch == nil:
+		writeError(w, "channel_not_found")
+	case user == s.selfID:
+		writeError(w, "cant_kick_self")
+	case !contains(ch.Members, user):
+		writeError(w, "not_in_channel")
+	default:
+		s.setMembers(ch, without(ch.Members, user))
+		writeOK(w, nil)
+	}
+}
@@ -0,0 +1,249 @@
+package fakeslack
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// Undocumented webclient and edge cache endpoints used with browser session
+// tokens. Only the fields the edge client reads are populated.
+
+// SetEnterprise makes auth.test report an Enterprise Grid workspace, which
+// switches browser session tokens to the edge endpoints for channel listing.
+func (s *Server) SetEnterprise(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enterpriseID = id
+}
+
+func (s *Server) clientUserBoot(w http.ResponseWriter, _ request) {
+	self, _ := s.userByID(s.selfID)
+
+	var channels, ims []map[string]any
+	for _, ch := range s.channels {
+		if ch.IsIM {
+			ims = append(ims, imJSON(ch))
+			continue
+		}
+		if !ch.IsMember {
+			continue
+		}
+		channels = append(channels, map[string]any{
+			"id":              ch.ID,
+			"name":            ch.Name,
+			"name_normalized": ch.NameNormalized,
+			"is_channel":      ch.IsChannel,
+			"is_group":        ch.IsGroup,
+			"is_mpim":         ch.IsMpIM,
+			"is_private":      ch.IsPrivate,
+			"is_archived":     ch.IsArchived,
+			"is_shared":       ch.IsShared,
+			"is_ext_shared":   ch.IsExtShared,
+			"is_member":       true,
+			"created":         int64(ch.Created),
+			"members":         ch.Members,
+			"topic":           map[string]any{"value": ch.Topic.Value},
+			"purpose":         map[string]any{"value": ch.Purpose.Value},
+		})
+	}
+
+	subteams := []string{}
+	for _, g := range s.usergroups {
+		if slices.Contains(g.Users, s.selfID) {
+			subteams = append(subteams, g.ID)
+		}
+	}
+
+	writeOK(w, map[string]any{
+		"self": map[string]any{
+			"id":        self.ID,
+			"team_id":   TeamID,
+			"name":      self.Name,
+			"real_name": self.RealName,
+		},
+		"team": map[string]any{
+			"id":     TeamID,
+			"name":   TeamName,
+			"url":    s.URL + "/",
+			"domain": "fake",
+		},
+		"ims":      ims,
+		"channels": channels,
+		"subteams": map[string]any{"self": subteams},
+		"dnd": map[string]any{
+			"dnd_enabled":       s.dnd[s.selfID].Enabled,
+			"next_dnd_start_ts": s.dnd[s.selfID].NextStartTimestamp,
+			"next_dnd_end_ts":   s.dnd[s.selfID].NextEndTimestamp,
+			"snooze_enabled":    s.dnd[s.selfID].SnoozeEnabled,
+		},
+	})
+}
+
+func imJSON(ch *slack.Channel) map[string]any {
+	return map[string]any{
+		"id":            ch.ID,
+		"created":       int64(ch.Created),
+		"is_im":         true,
+		"is_shared":     ch.IsShared,
+		"is_ext_shared": ch.IsExtShared,
+		"user":          ch.User,
+		"is_open":       true,
+	}
+}
+
+// clientCounts reports the read state of every conversation: messages newer
+// than the last read one are unread, and count as mentions when they mention
+// the caller or are in a DM.
+func (s *Server) clientCounts(w http.ResponseWriter, _ request) {
+	counts := map[string][]map[string]any{"channels": {}, "mpims": {}, "ims": {}}
+	for _, ch := range s.channels {
+		key := "channels"
+		switch {
+		case ch.IsIM:
+			key = "ims"
+		case ch.IsMpIM:
+			key = "mpims"
+		}
+		latest, mentions := "", 0
+		for _, m := range s.messages[ch.ID] {
+			if m.ThreadTimestamp != "" && m.ThreadTimestamp != m.Timestamp {
+				continue
+			}
+			latest = m.Timestamp
+			if m.Timestamp > ch.LastRead && m.User != s.selfID && (ch.IsIM || strings.Contains(m.Text, "<@"+s.selfID+">")) {
+				mentions++
+			}
+		}
+		counts[key] = append(counts[key], map[string]any{
+			"id":            ch.ID,
+			"last_read":     ch.LastRead,
+			"latest":        latest,
+			"mention_count": mentions,
+			"has_unreads":   latest > ch.LastRead,
+		})
+	}
+	writeOK(w, map[string]any{
+		"channels": counts["channels"],
+		"mpims":    counts["mpims"],
+		"ims":      counts["ims"],
+	})
+}
+
+func (s *Server) imList(w http.ResponseWriter, _ request) {
+	var ims []map[string]any
+	for _, ch := range s.channels {
+		if ch.IsIM {
+			ims = append(ims, imJSON(ch))
+		}
+	}
+	writeOK(w, map[string]any{"ims": ims})
+}
+
+func (s *Server) searchModulesChannels(w http.ResponseWriter, req request) {
+	query := strings.ToLower(req.get("query"))
+
+	var items []map[string]any
+	for _, ch := range s.channels {
+		if ch.IsIM || ch.IsMpIM || (ch.IsPrivate && !ch.IsMember) {
+			continue
+		}
+		if query != "" && !strings.Contains(ch.Name, query) {
+			continue
+		}
+		items = append(items, map[string]any{
+			"id":              ch.ID,
+			"name":            ch.Name,
+			"name_normalized": ch.NameNormalized,
+			"is_private":      ch.IsPrivate,
+			"is_archived":     ch.IsArchived,
+			"is_member":       ch.IsMember,
+			"member_count":    ch.NumMembers,
+			"created":         int64(ch.Created),
+			"topic":           map[string]any{"value": ch.Topic.Value},
+			"purpose":         map[string]any{"value": ch.Purpose.Value},
+		})
+	}
+
+	cursor := req.get("cursor")
+	if cursor == "*" {
+		cursor = ""
+	}
+	count, _ := strconv.Atoi(req.get("count"))
+	start, end, next := paginate(len(items), cursor, count)
+	writeOK(w, map[string]any{
+		"module":     "channels",
+		"query":      req.get("query"),
+		"items":      items[start:end],
+		"pagination": map[string]any{"total_count": len(items), "next_cursor": next},
+	})
+}
+
+func (s *Server) conversationsGenericInfo(w http.ResponseWriter, req request) {
+	var updated map[string]int
+	_ = json.Unmarshal([]byte(req.get("updated_channels")), &updated)
+
+	channels := []*slack.Channel{}
+	for id := range updated {
+		if ch := s.channelByID(id); ch != nil {
+			channels = append(channels, ch)
+		}
+	}
+	writeOK(w, map[string]any{"channels": channels})
+}
+
+// handleEdge serves the edge cache API, https://edgeapi.slack.com/cache/<team>/<method>.
+func (s *Server) handleEdge(w http.ResponseWriter, r *http.Request) {
+	team, method, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/cache/"), "/")
+	req, err := parseRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, method)
+
+	if team != TeamID {
+		writeError(w, "invalid_team")
+		return
+	}
+	if !s.authorized(r, req) {
+		writeError(w, "invalid_auth")
+		return
+	}
+
+	switch method {
+	case "users/search":
+		s.usersSearch(w, req)
+	default:
+		writeError(w, "unknown_method")
+	}
+}
+
+func (s *Server) usersSearch(w http.ResponseWriter, req request) {
+	query := strings.ToLower(req.get("query"))
+	count, _ := strconv.Atoi(req.get("count"))
+	if count <= 0 {
+		count = 10
+	}
+
+	results := []slack.User{}
+	for _, u := range s.users {
+		if len(results) == count {
+			break
+		}
+		for _, field := range []string{u.Name, u.RealName, u.Profile.DisplayName, u.Profile.Email} {
+			if query != "" && strings.Contains(strings.ToLower(field), query) {
+				results = append(results, u)
+				break
+			}
+		}
+	}
+	writeOK(w, map[string]any{"results": results})
+}
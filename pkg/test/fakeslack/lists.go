@@ -0,0 +1,145 @@
+package fakeslack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/lists"
+)
+
+// itemJSON renders an item the way slackLists.items.* return it: a fields array
+// with one entry per column.
+func (l *List) itemJSON(item *ListItem) map[string]any {
+	var fields []map[string]any
+	for _, col := range l.Columns {
+		value, ok := item.Fields[col.ID]
+		if !ok {
+			continue
+		}
+		fields = append(fields, map[string]any{
+			"key":       col.Key,
+			"column_id": col.ID,
+			"value":     value,
+			"rich_text": value,
+		})
+	}
+	return map[string]any{"id": item.ID, "fields": fields}
+}
+
+func (l *List) item(id string) (int, *ListItem) {
+	for i, item := range l.Items {
+		if item.ID == id {
+			return i, item
+		}
+	}
+	return -1, nil
+}
+
+func (s *Server) listItemsList(w http.ResponseWriter, req request) {
+	l, ok := s.lists[req.get("list_id")]
+	if !ok {
+		writeError(w, "list_not_found")
+		return
+	}
+
+	limit, _ := strconv.Atoi(req.get("limit"))
+	start, end, next := paginate(len(l.Items), req.get("cursor"), limit)
+	items := make([]map[string]any, 0, end-start)
+	for _, item := range l.Items[start:end] {
+		items = append(items, l.itemJSON(item))
+	}
+	writeOK(w, map[string]any{
+		"items":             items,
+		"response_metadata": map[string]string{"next_cursor": next},
+	})
+}
+
+func (s *Server) listItemsInfo(w http.ResponseWriter, req request) {
+	l, ok := s.lists[req.get("list_id")]
+	if !ok {
+		writeError(w, "list_not_found")
+		return
+	}
+	_, item := l.item(req.get("id"))
+	if item == nil {
+		writeError(w, "item_not_found")
+		return
+	}
+	writeOK(w, map[string]any{
+		"list": lists.ListInfo{
+			ID:           l.ID,
+			Title:        l.Title,
+			ListMetadata: lists.ListMetadata{Schema: l.Columns},
+		},
+		"record": l.itemJSON(item),
+	})
+}
+
+type listCell struct {
+	RowID    string          `json:"row_id"`
+	ColumnID string          `json:"column_id"`
+	RichText json.RawMessage `json:"rich_text"`
+}
+
+func (s *Server) listItemsCreate(w http.ResponseWriter, req request) {
+	l, ok := s.lists[req.get("list_id")]
+	if !ok {
+		writeError(w, "list_not_found")
+		return
+	}
+	var cells []listCell
+	if raw, ok := req.json["initial_fields"]; ok {
+		if err := json.Unmarshal(raw, &cells); err != nil {
+			writeError(w, "invalid_arguments")
+			return
+		}
+	}
+
+	s.seq++
+	item := &ListItem{ID: fmt.Sprintf("Rec%06d", s.seq), Fields: map[string]json.RawMessage{}}
+	for _, c := range cells {
+		item.Fields[c.ColumnID] = c.RichText
+	}
+	l.Items = append(l.Items, item)
+	writeOK(w, map[string]any{"item": l.itemJSON(item)})
+}
+
+func (s *Server) listItemsUpdate(w http.ResponseWriter, req request) {
+	l, ok := s.lists[req.get("list_id")]
+	if !ok {
+		writeError(w, "list_not_found")
+		return
+	}
+	var cells []listCell
+	if err := json.Unmarshal(req.json["cells"], &cells); err != nil || len(cells) == 0 {
+		writeError(w, "invalid_arguments")
+		return
+	}
+
+	for _, c := range cells {
+		_, item := l.item(c.RowID)
+		if item == nil {
+			writeError(w, "item_not_found")
+			return
+		}
+		item.Fields[c.ColumnID] = c.RichText
+	}
+	writeOK(w, nil)
+}
+
+func (s *Server) listItemsDelete(w http.ResponseWriter, req request) {
+	l, ok := s.lists[req.get("list_id")]
+	if !ok {
+		writeError(w, "list_not_found")
+		return
+	}
+	i, _ := l.item(req.get("id"))
+	if i < 0 {
+		writeError(w, "item_not_found")
+		return
+	}
+	l.Items = append(l.Items[:i], l.Items[i+1:]...)
+	writeOK(w, nil)
+}
@@ -0,0 +1,81 @@
+package fakeslack
+
+import (
+	"net/http"
+
+	"github.com/slack-go/slack"
+)
+
+func (s *Server) pinsList(w http.ResponseWriter, req request) {
+	channel := req.get("channel")
+	if s.channelByID(channel) == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+	items := []slack.Item{}
+	for _, m := range s.messages[channel] {
+		if contains(m.PinnedTo, channel) {
+			msg := m
+			items = append(items, slack.Item{Type: "message", Channel: channel, Message: &msg})
+		}
+	}
+	writeOK(w, map[string]any{"items": items})
+}
+
+func (s *Server) pinsAdd(w http.ResponseWriter, req request) {
+	s.pin(w, req, true)
+}
+
+func (s *Server) pinsRemove(w http.ResponseWriter, req request) {
+	s.pin(w, req, false)
+}
+
+func (s *Server) pin(w http.ResponseWriter, req request, pin bool) {
+	if s.channelByID(req.get("channel")) == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+	if errMsg := s.pinLocked(req.get("channel"), req.get("timestamp"), pin); errMsg != "" {
+		writeError(w, errMsg)
+		return
+	}
+	writeOK(w, nil)
+}
+
+func (s *Server) bookmarksList(w http.ResponseWriter, req request) {
+	channel := req.get("channel_id")
+	if s.channelByID(channel) == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+	bookmarks := append([]slack.Bookmark{}, s.bookmarks[channel]...)
+	writeOK(w, map[string]any{"bookmarks": bookmarks})
+}
+
+func (s *Server) bookmarksAdd(w http.ResponseWriter, req request) {
+	channel := req.get("channel_id")
+	switch {
+	case s.channelByID(channel) == nil:
+		writeError(w, "channel_not_found")
+	case req.get("title") == "" || req.get("type") != "link":
+		writeError(w, "invalid_arguments")
+	case req.get("link") == "":
+		writeError(w, "invalid_link")
+	default:
+		b := s.addBookmarkLocked(channel, req.get("title"), req.get("type"), req.get("link"))
+		writeOK(w, map[string]any{"bookmark": b})
+	}
+}
+
+func (s *Server) bookmarksRemove(w http.ResponseWriter, req request) {
+	channel := req.get("channel_id")
+	bookmarks := s.bookmarks[channel]
+	for i, b := range bookmarks {
+		if b.ID == req.get("bookmark_id") {
+			s.bookmarks[channel] = append(bookmarks[:i:i], bookmarks[i+1:]...)
+			writeOK(w, nil)
+			return
+		}
+	}
+	writeError(w, "not_found")
+}
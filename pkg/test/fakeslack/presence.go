@@ -0,0 +1,97 @@
+package fakeslack
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+func (s *Server) usersGetPresence(w http.ResponseWriter, req request) {
+	user := req.get("user")
+	if user == "" {
+		user = s.selfID
+	}
+	if _, ok := s.userByID(user); !ok {
+		writeError(w, "user_not_found")
+		return
+	}
+	presence := s.presence[user]
+	if presence == "" {
+		presence = "away"
+	}
+	writeOK(w, map[string]any{"presence": presence, "online": presence == "active"})
+}
+
+func (s *Server) dndTeamInfo(w http.ResponseWriter, req request) {
+	users := map[string]slack.DNDStatus{}
+	for _, id := range strings.Split(req.get("users"), ",") {
+		if _, ok := s.userByID(strings.TrimSpace(id)); ok {
+			users[id] = s.dnd[id]
+		}
+	}
+	writeOK(w, map[string]any{"users": users})
+}
+
+// usersProfileSet only supports the status fields.
+func (s *Server) usersProfileSet(w http.ResponseWriter, req request) {
+	var profile struct {
+		StatusText       string `json:"status_text"`
+		StatusEmoji      string `json:"status_emoji"`
+		StatusExpiration int    `json:"status_expiration"`
+	}
+	if err := json.Unmarshal([]byte(req.get("profile")), &profile); err != nil {
+		writeError(w, "invalid_profile")
+		return
+	}
+	if profile.StatusText != "" && profile.StatusEmoji == "" {
+		profile.StatusEmoji = ":speech_balloon:"
+	}
+	for i := range s.users {
+		if s.users[i].ID == s.selfID {
+			s.users[i].Profile.StatusText = profile.StatusText
+			s.users[i].Profile.StatusEmoji = profile.StatusEmoji
+			s.users[i].Profile.StatusExpiration = profile.StatusExpiration
+			writeOK(w, map[string]any{"profile": s.users[i].Profile})
+			return
+		}
+	}
+	writeError(w, "user_not_found")
+}
+
+func (s *Server) dndSetSnooze(w http.ResponseWriter, req request) {
+	minutes, err := strconv.Atoi(req.get("num_minutes"))
+	if err != nil || minutes <= 0 {
+		writeError(w, "invalid_arguments")
+		return
+	}
+	dnd := s.dnd[s.selfID]
+	dnd.SnoozeEnabled = true
+	dnd.SnoozeEndTime = int(time.Now().Add(time.Duration(minutes) * time.Minute).Unix())
+	dnd.SnoozeRemaining = minutes * 60
+	s.dnd[s.selfID] = dnd
+	writeOK(w, map[string]any{
+		"snooze_enabled":   true,
+		"snooze_endtime":   dnd.SnoozeEndTime,
+		"snooze_remaining": dnd.SnoozeRemaining,
+	})
+}
+
+func (s *Server) dndEndSnooze(w http.ResponseWriter, _ request) {
+	dnd := s.dnd[s.selfID]
+	if !dnd.SnoozeEnabled {
+		writeError(w, "snooze_not_active")
+		return
+	}
+	dnd.SnoozeInfo = slack.SnoozeInfo{}
+	s.dnd[s.selfID] = dnd
+	writeOK(w, map[string]any{
+		"dnd_enabled":       dnd.Enabled,
+		"next_dnd_start_ts": dnd.NextStartTimestamp,
+		"next_dnd_end_ts":   dnd.NextEndTimestamp,
+		"snooze_enabled":    false,
+	})
+}
@@ -0,0 +1,43 @@
+package fakeslack
+
+import (
+	"net/http"
+
+	"github.com/slack-go/slack"
+)
+
+// usersProfileGet returns the profile of a user, custom fields carry their
+// labels when include_labels is set.
+func (s *Server) usersProfileGet(w http.ResponseWriter, req request) {
+	user := req.get("user")
+	if user == "" {
+		user = s.selfID
+	}
+	u, ok := s.userByID(user)
+	if !ok {
+		writeError(w, "user_not_found")
+		return
+	}
+
+	fields := map[string]slack.UserProfileCustomField{}
+	for id, f := range u.Profile.FieldsMap() {
+		if req.get("include_labels") == "true" {
+			for _, tf := range s.teamProfile {
+				if tf.ID == id {
+					f.Label = tf.Label
+				}
+			}
+		}
+		fields[id] = f
+	}
+	profile := u.Profile
+	profile.SetFieldsMap(fields)
+	writeOK(w, map[string]any{"profile": struct {
+		slack.UserProfile
+		Pronouns string `json:"pronouns,omitempty"`
+	}{profile, s.pronouns[user]}})
+}
+
+func (s *Server) teamProfileGet(w http.ResponseWriter, _ request) {
+	writeOK(w, map[string]any{"profile": slack.TeamProfile{Fields: s.teamProfile}})
+}
@@ -0,0 +1,93 @@
+package fakeslack
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// remindersAdd accepts a Unix timestamp, seconds from now below a day, or a
+// phrase starting with "every" for a recurring reminder. Other natural
+// language times are not understood.
+func (s *Server) remindersAdd(w http.ResponseWriter, req request) {
+	text := req.get("text")
+	if text == "" {
+		writeError(w, "no_text")
+		return
+	}
+	user := req.get("user")
+	if user == "" {
+		user = s.selfID
+	}
+	if _, ok := s.userByID(user); !ok {
+		writeError(w, "user_not_found")
+		return
+	}
+
+	r := slack.Reminder{Creator: s.selfID, User: user, Text: text}
+	when := strings.TrimSpace(req.get("time"))
+	if n, err := strconv.Atoi(when); err == nil {
+		if n < 24*60*60 {
+			n += int(time.Now().Unix())
+		}
+		r.Time = n
+	} else if strings.HasPrefix(strings.ToLower(when), "every ") {
+		r.Recurring = true
+	} else {
+		writeError(w, "cannot_parse")
+		return
+	}
+
+	s.seq++
+	r.ID = fmt.Sprintf("Rm%06d", s.seq)
+	s.reminders = append(s.reminders, r)
+	writeOK(w, map[string]any{"reminder": r})
+}
+
+func (s *Server) remindersList(w http.ResponseWriter, _ request) {
+	reminders := []slack.Reminder{}
+	for _, r := range s.reminders {
+		if r.Creator == s.selfID || r.User == s.selfID {
+			reminders = append(reminders, r)
+		}
+	}
+	writeOK(w, map[string]any{"reminders": reminders})
+}
+
+func (s *Server) remindersComplete(w http.ResponseWriter, req request) {
+	i := s.reminderIndex(req.get("reminder"))
+	switch {
+	case i < 0:
+		writeError(w, "not_found")
+	case s.reminders[i].Recurring:
+		writeError(w, "cannot_complete_recurring")
+	case s.reminders[i].CompleteTS != 0:
+		writeError(w, "already_complete")
+	default:
+		s.reminders[i].CompleteTS = int(time.Now().Unix())
+		writeOK(w, nil)
+	}
+}
+
+func (s *Server) remindersDelete(w http.ResponseWriter, req request) {
+	i := s.reminderIndex(req.get("reminder"))
+	if i < 0 {
+		writeError(w, "not_found")
+		return
+	}
+	s.reminders = append(s.reminders[:i], s.reminders[i+1:]...)
+	writeOK(w, nil)
+}
+
+func (s *Server) reminderIndex(id string) int {
+	for i, r := range s.reminders {
+		if r.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,176 @@
+package fakeslack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/lists"
+	"github.com/rusq/slackdump/v3/auth"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T, srv *Server) *slack.Client {
+	t.Helper()
+	return slack.New(Token, slack.OptionAPIURL(srv.APIURL()))
+}
+
+func TestUnitFakeSlackAuth(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+
+	resp, err := newClient(t, srv).AuthTest()
+	require.NoError(t, err)
+	assert.Equal(t, TeamID, resp.TeamID)
+	assert.Equal(t, "U001", resp.UserID)
+	assert.Equal(t, srv.URL+"/", resp.URL)
+
+	_, err = slack.New("xoxp-wrong", slack.OptionAPIURL(srv.APIURL())).AuthTest()
+	assert.EqualError(t, err, "invalid_auth")
+}
+
+func TestUnitFakeSlackConversations(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	ctx := context.Background()
+	api := newClient(t, srv)
+
+	channels, _, err := api.GetConversationsContext(ctx, &slack.GetConversationsParameters{Types: []string{"public_channel", "private_channel"}})
+	require.NoError(t, err)
+	assert.Len(t, channels, 3)
+
+	history, err := api.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{ChannelID: "C001", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, history.Messages, 1)
+	assert.True(t, history.HasMore)
+	parent := history.Messages[0]
+	assert.Equal(t, "Release planning thread", parent.Text)
+	assert.Equal(t, 2, parent.ReplyCount)
+	require.Len(t, parent.Reactions, 1)
+	assert.Equal(t, "thumbsup", parent.Reactions[0].Name)
+
+	_, ts, err := api.PostMessageContext(ctx, "C001", slack.MsgOptionText("Sounds good", false), slack.MsgOptionTS(parent.Timestamp))
+	require.NoError(t, err)
+	require.NoError(t, api.AddReactionContext(ctx, "eyes", slack.NewRefToMessage("C001", ts)))
+
+	replies, _, _, err := api.GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{ChannelID: "C001", Timestamp: parent.Timestamp})
+	require.NoError(t, err)
+	require.Len(t, replies, 4)
+	assert.Equal(t, "Sounds good", replies[3].Text)
+	assert.Equal(t, "eyes", replies[3].Reactions[0].Name)
+
+	require.NoError(t, api.RemoveReactionContext(ctx, "eyes", slack.NewRefToMessage("C001", ts)))
+	assert.Empty(t, srv.Messages("C001")[4].Reactions)
+
+	msgs, _, err := api.SearchContext(ctx, "changelog in:#general from:@bob", slack.NewSearchParameters())
+	require.NoError(t, err)
+	require.Len(t, msgs.Matches, 1)
+	assert.Equal(t, "I can take the changelog", msgs.Matches[0].Text)
+	assert.Equal(t, "general", msgs.Matches[0].Channel.Name)
+}
+
+func TestUnitFakeSlackFilesAndCanvases(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	ctx := context.Background()
+	api := newClient(t, srv)
+
+	file, _, _, err := api.GetFileInfoContext(ctx, "F001", 0, 0)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, api.GetFileContext(ctx, file.URLPrivateDownload, &buf))
+	assert.Equal(t, "Meeting notes: ship it.", buf.String())
+
+	id, err := api.CreateCanvasContext(ctx, "Plan", slack.DocumentContent{Type: "markdown", Markdown: "# Goals\nShip\n# Risks\nNone"})
+	require.NoError(t, err)
+
+	canvases, _, err := api.GetFilesContext(ctx, slack.GetFilesParameters{Types: "canvases", Count: 100, Page: 1})
+	require.NoError(t, err)
+	assert.Len(t, canvases, 2)
+
+	sections, err := api.LookupCanvasSectionsContext(ctx, slack.LookupCanvasSectionsParams{
+		CanvasID: id,
+		Criteria: slack.LookupCanvasSectionsCriteria{ContainsText: "Risks"},
+	})
+	require.NoError(t, err)
+	require.Len(t, sections, 1)
+
+	err = api.EditCanvasContext(ctx, slack.EditCanvasParams{
+		CanvasID: id,
+		Changes: []slack.CanvasChange{
+			{Operation: "replace", SectionID: sections[0].ID, DocumentContent: slack.DocumentContent{Type: "markdown", Markdown: "# Risks\nDeadline"}},
+			{Operation: "insert_at_end", DocumentContent: slack.DocumentContent{Type: "markdown", Markdown: "# Owners\nalice"}},
+		},
+	})
+	require.NoError(t, err)
+
+	canvas, ok := srv.Canvas(id)
+	require.True(t, ok)
+	assert.Equal(t, "# Goals\nShip\n# Risks\nDeadline\n# Owners\nalice\n", canvas.Content)
+}
+
+func TestUnitFakeSlackLists(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	ctx := context.Background()
+	cl := lists.NewClient(Token, nil, lists.OptionAPIURL(srv.APIURL()))
+
+	added, err := cl.AddItem(ctx, "F003", map[string]json.RawMessage{"Col001": lists.WrapTextAsRichText("Publish blog post")})
+	require.NoError(t, err)
+
+	_, err = cl.UpdateItem(ctx, "F003", added.Item.ID, map[string]json.RawMessage{"Col002": lists.WrapTextAsRichText("carol")})
+	require.NoError(t, err)
+
+	item, err := cl.GetItem(ctx, "F003", added.Item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Launch checklist", item.List.Title)
+	assert.Len(t, item.List.ListMetadata.Schema, 2)
+	assert.Len(t, item.Record.Fields, 2)
+
+	require.NoError(t, cl.DeleteItem(ctx, "F003", "Rec001"))
+	items, err := cl.GetItems(ctx, "F003", "", 10)
+	require.NoError(t, err)
+	require.Len(t, items.Items, 1)
+	assert.Equal(t, added.Item.ID, items.Items[0].ID)
+
+	_, err = cl.GetItems(ctx, "F999", "", 10)
+	assert.EqualError(t, err, "slackLists.items.list failed: list_not_found")
+}
+
+func TestUnitFakeSlackEdge(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	srv.SetEnterprise("E0FAKE")
+	ctx := context.Background()
+
+	info, err := newClient(t, srv).AuthTest()
+	require.NoError(t, err)
+	assert.Equal(t, "E0FAKE", info.EnterpriseID)
+
+	prov, err := auth.NewValueAuth(Token, "")
+	require.NoError(t, err)
+	cl, err := edge.NewWithInfo(info, prov, edge.OptionAPIURL(srv.APIURL()))
+	require.NoError(t, err)
+
+	boot, err := cl.ClientUserBoot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "U001", boot.Self.ID)
+	assert.Len(t, boot.Channels, 3)
+	require.Len(t, boot.IMs, 1)
+	assert.Equal(t, "U002", boot.IMs[0].User)
+
+	channels, _, err := cl.GetConversationsContext(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, channels, 4)
+
+	users, err := cl.UsersSearch(ctx, "carol", 5)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "U003", users[0].ID)
+
+	assert.Contains(t, srv.Calls(), "users/search")
+}
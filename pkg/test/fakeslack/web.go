@@ -0,0 +1,748 @@
+package fakeslack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+type handler func(w http.ResponseWriter, req request)
+
+func (s *Server) methods() map[string]handler {
+	return map[string]handler{
+		"auth.test": s.authTest,
+		"team.info": s.teamInfo,
+
+		"users.list": s.usersList,
+		"users.info": s.usersInfo,
+
+		"users.getPresence": s.usersGetPresence,
+		"users.profile.set": s.usersProfileSet,
+		"users.profile.get": s.usersProfileGet,
+		"team.profile.get":  s.teamProfileGet,
+		"dnd.teamInfo":      s.dndTeamInfo,
+		"dnd.setSnooze":     s.dndSetSnooze,
+		"dnd.endSnooze":     s.dndEndSnooze,
+
+		"usergroups.list":         s.usergroupsList,
+		"usergroups.users.list":   s.usergroupsUsersList,
+		"usergroups.users.update": s.usergroupsUsersUpdate,
+
+		"conversations.list":       s.conversationsList,
+		"conversations.info":       s.conversationsInfo,
+		"conversations.history":    s.conversationsHistory,
+		"conversations.replies":    s.conversationsReplies,
+		"conversations.mark":       s.conversationsMark,
+		"conversations.create":     s.conversationsCreate,
+		"conversations.archive":    s.conversationsArchive,
+		"conversations.unarchive":  s.conversationsUnarchive,
+		"conversations.rename":     s.conversationsRename,
+		"conversations.setTopic":   s.conversationsSetTopic,
+		"conversations.setPurpose": s.conversationsSetPurpose,
+		"conversations.members":    s.conversationsMembers,
+		"conversations.join":       s.conversationsJoin,
+		"conversations.leave":      s.conversationsLeave,
+		"conversations.invite":     s.conversationsInvite,
+		"conversations.kick":       s.conversationsKick,
+		"chat.postMessage":         s.chatPostMessage,
+		"reactions.add":            s.reactionsAdd,
+		"reactions.remove":         s.reactionsRemove,
+		"search.all":               s.searchMessages,
+		"search.messages":          s.searchMessages,
+		"pins.list":                s.pinsList,
+		"pins.add":                 s.pinsAdd,
+		"pins.remove":              s.pinsRemove,
+		"bookmarks.list":           s.bookmarksList,
+		"bookmarks.add":            s.bookmarksAdd,
+		"bookmarks.remove":         s.bookmarksRemove,
+		"reminders.add":            s.remindersAdd,
+		"reminders.list":           s.remindersList,
+		"reminders.complete":       s.remindersComplete,
+		"reminders.delete":         s.remindersDelete,
+
+		"files.info": s.filesInfo,
+		"files.list": s.filesList,
+
+		"canvases.create":          s.canvasesCreate,
+		"canvases.edit":            s.canvasesEdit,
+		"canvases.sections.lookup": s.canvasesSectionsLookup,
+
+		"slackLists.items.list":   s.listItemsList,
+		"slackLists.items.info":   s.listItemsInfo,
+		"slackLists.items.create": s.listItemsCreate,
+		"slackLists.items.update": s.listItemsUpdate,
+		"slackLists.items.delete": s.listItemsDelete,
+
+		"client.userBoot":           s.clientUserBoot,
+		"client.counts":             s.clientCounts,
+		"im.list":                   s.imList,
+		"search.modules.channels":   s.searchModulesChannels,
+		"conversations.genericInfo": s.conversationsGenericInfo,
+	}
+}
+
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, "/api/")
+	req, err := parseRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, method)
+
+	h, ok := s.methods()[method]
+	if !ok {
+		writeError(w, "unknown_method")
+		return
+	}
+	if !s.authorized(r, req) {
+		writeError(w, "invalid_auth")
+		return
+	}
+	if codes := s.failures[method]; len(codes) > 0 {
+		s.failures[method] = codes[1:]
+		writeError(w, codes[0])
+		return
+	}
+	h(w, req)
+}
+
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, "files.download")
+
+	if !s.authorized(r, request{}) {
+		http.Error(w, "invalid_auth", http.StatusUnauthorized)
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/files/")
+	content, ok := s.content[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if f := s.files[id]; f.Mimetype != "" {
+		w.Header().Set("Content-Type", f.Mimetype)
+	}
+	_, _ = w.Write(content)
+}
+
+func (s *Server) authTest(w http.ResponseWriter, _ request) {
+	self, _ := s.userByID(s.selfID)
+	writeOK(w, map[string]any{
+		"url":     s.URL + "/",
+		"team":    TeamName,
+		"user":    self.Name,
+		"team_id": TeamID,
+		"user_id": self.ID,
+
+		"enterprise_id": s.enterpriseID,
+	})
+}
+
+func (s *Server) teamInfo(w http.ResponseWriter, req request) {
+	id := req.get("team")
+	if id == "" {
+		id = TeamID
+	}
+	name, ok := s.teams[id]
+	if !ok {
+		writeError(w, "team_not_found")
+		return
+	}
+	writeOK(w, map[string]any{"team": map[string]any{"id": id, "name": name}})
+}
+
+func (s *Server) usersList(w http.ResponseWriter, req request) {
+	limit, _ := strconv.Atoi(req.get("limit"))
+	start, end, next := paginate(len(s.users), req.get("cursor"), limit)
+	writeOK(w, map[string]any{
+		"members":           s.users[start:end],
+		"response_metadata": map[string]string{"next_cursor": next},
+	})
+}
+
+func (s *Server) usersInfo(w http.ResponseWriter, req request) {
+	var users []slack.User
+	for _, id := range strings.Split(req.get("users"), ",") {
+		if u, ok := s.userByID(strings.TrimSpace(id)); ok {
+			users = append(users, u)
+		}
+	}
+	if len(users) == 0 {
+		writeError(w, "user_not_found")
+		return
+	}
+	writeOK(w, map[string]any{"users": users})
+}
+
+func channelType(ch *slack.Channel) string {
+	switch {
+	case ch.IsIM:
+		return "im"
+	case ch.IsMpIM:
+		return "mpim"
+	case ch.IsPrivate:
+		return "private_channel"
+	}
+	return "public_channel"
+}
+
+func (s *Server) conversationsList(w http.ResponseWriter, req request) {
+	types := map[string]bool{"public_channel": true}
+	if t := req.get("types"); t != "" {
+		types = map[string]bool{}
+		for _, v := range strings.Split(t, ",") {
+			types[v] = true
+		}
+	}
+
+	var matched []*slack.Channel
+	for _, ch := range s.channels {
+		if !types[channelType(ch)] || (ch.IsArchived && req.get("exclude_archived") == "true") {
+			continue
+		}
+		matched = append(matched, ch)
+	}
+
+	limit, _ := strconv.Atoi(req.get("limit"))
+	start, end, next := paginate(len(matched), req.get("cursor"), limit)
+	writeOK(w, map[string]any{
+		"channels":          matched[start:end],
+		"response_metadata": map[string]string{"next_cursor": next},
+	})
+}
+
+// inRange reports whether ts is within the oldest/latest bounds of a request.
+func inRange(ts string, req request) bool {
+	inclusive := req.get("inclusive") == "true" || req.get("inclusive") == "1"
+	if oldest := req.get("oldest"); oldest != "" && oldest != "0" {
+		if ts < oldest || (ts == oldest && !inclusive) {
+			return false
+		}
+	}
+	if latest := req.get("latest"); latest != "" {
+		if ts > latest || (ts == latest && !inclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) conversationsHistory(w http.ResponseWriter, req request) {
+	channel := req.get("channel")
+	if s.channelByID(channel) == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+
+	// history is newest first and only holds thread parents and top level messages
+	var msgs []slack.Message
+	all := s.messages[channel]
+	for i := len(all) - 1; i >= 0; i-- {
+		m := all[i]
+		if m.ThreadTimestamp != "" && m.ThreadTimestamp != m.Timestamp {
+			continue
+		}
+		if inRange(m.Timestamp, req) {
+			msgs = append(msgs, m)
+		}
+	}
+
+	limit, _ := strconv.Atoi(req.get("limit"))
+	start, end, next := paginate(len(msgs), req.get("cursor"), limit)
+	writeOK(w, map[string]any{
+		"messages":          msgs[start:end],
+		"has_more":          next != "",
+		"response_metadata": map[string]string{"next_cursor": next},
+	})
+}
+
+func (s *Server) conversationsReplies(w http.ResponseWriter, req request) {
+	channel, ts := req.get("channel"), req.get("ts")
+	if s.channelByID(channel) == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+
+	// like Slack, the parent comes first whatever the range
+	var msgs []slack.Message
+	for _, m := range s.messages[channel] {
+		if m.Timestamp == ts || (m.ThreadTimestamp == ts && inRange(m.Timestamp, req)) {
+			msgs = append(msgs, m)
+		}
+	}
+	if len(msgs) == 0 {
+		writeError(w, "thread_not_found")
+		return
+	}
+
+	limit, _ := strconv.Atoi(req.get("limit"))
+	start, end, next := paginate(len(msgs), req.get("cursor"), limit)
+	writeOK(w, map[string]any{
+		"messages":          msgs[start:end],
+		"has_more":          next != "",
+		"response_metadata": map[string]string{"next_cursor": next},
+	})
+}
+
+func (s *Server) usergroupsList(w http.ResponseWriter, req request) {
+	groups := []slack.UserGroup{}
+	for _, g := range s.usergroups {
+		if req.get("include_count") == "true" {
+			g.UserCount = len(g.Users)
+		}
+		if req.get("include_users") != "true" {
+			g.Users = nil
+		}
+		groups = append(groups, g)
+	}
+	writeOK(w, map[string]any{"usergroups": groups})
+}
+
+func (s *Server) usergroupsUsersList(w http.ResponseWriter, req request) {
+	for _, g := range s.usergroups {
+		if g.ID == req.get("usergroup") {
+			writeOK(w, map[string]any{"users": append([]string{}, g.Users...)})
+			return
+		}
+	}
+	writeError(w, "no_such_subteam")
+}
+
+func (s *Server) usergroupsUsersUpdate(w http.ResponseWriter, req request) {
+	var users []string
+	for _, id := range strings.Split(req.get("users"), ",") {
+		if _, ok := s.userByID(strings.TrimSpace(id)); !ok {
+			writeError(w, "invalid_users")
+			return
+		}
+		users = append(users, strings.TrimSpace(id))
+	}
+	for i := range s.usergroups {
+		if s.usergroups[i].ID == req.get("usergroup") {
+			s.usergroups[i].Users = users
+			g := s.usergroups[i]
+			g.UserCount = len(users)
+			writeOK(w, map[string]any{"usergroup": g})
+			return
+		}
+	}
+	writeError(w, "no_such_subteam")
+}
+
+func (s *Server) conversationsMark(w http.ResponseWriter, req request) {
+	ch := s.channelByID(req.get("channel"))
+	if ch == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+	ch.LastRead = req.get("ts")
+	writeOK(w, nil)
+}
+
+func (s *Server) chatPostMessage(w http.ResponseWriter, req request) {
+	channel := req.get("channel")
+	if s.channelByID(channel) == nil {
+		writeError(w, "channel_not_found")
+		return
+	}
+	text := req.get("text")
+	if text == "" && req.get("blocks") == "" {
+		writeError(w, "no_text")
+		return
+	}
+
+	if text == "" {
+		text = blocksText(req.get("blocks"))
+	}
+
+	msg := s.addMessageLocked(channel, s.selfID, text, req.get("thread_ts"))
+	writeOK(w, map[string]any{
+		"channel": channel,
+		"ts":      msg.Timestamp,
+		"message": msg,
+	})
+}
+
+// blocksText flattens the text of Block Kit blocks, standing in for the
+// fallback text Slack derives for messages posted without one.
+func blocksText(raw string) string {
+	var blocks any
+	if err := json.Unmarshal([]byte(raw), &blocks); err != nil {
+		return ""
+	}
+
+	var parts []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if text, ok := v["text"].(string); ok {
+				parts = append(parts, text)
+			}
+			for _, k := range sortedKeys(v) {
+				if k != "text" {
+					walk(v[k])
+				}
+			}
+			if text, ok := v["text"].(map[string]any); ok {
+				walk(text)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(blocks)
+	return strings.Join(parts, "")
+}
+
+func (s *Server) reactionsAdd(w http.ResponseWriter, req request) {
+	s.react(w, req, true)
+}
+
+func (s *Server) reactionsRemove(w http.ResponseWriter, req request) {
+	s.react(w, req, false)
+}
+
+func (s *Server) react(w http.ResponseWriter, req request, add bool) {
+	if !s.reactLocked(req.get("channel"), req.get("timestamp"), s.selfID, req.get("name"), add) {
+		writeError(w, "message_not_found")
+		return
+	}
+	writeOK(w, nil)
+}
+
+// searchMessages supports plain words plus the in:, from:, after: and before:
+// modifiers, which is what the search tools generate.
+func (s *Server) searchMessages(w http.ResponseWriter, req request) {
+	var words []string
+	var inChannel, fromUser, withUser, after, before string
+	threads := false
+	for _, term := range strings.Fields(req.get("query")) {
+		key, value, found := strings.Cut(term, ":")
+		switch {
+		case found && key == "is" && value == "thread":
+			threads = true
+		case found && key == "with":
+			withUser = strings.TrimPrefix(strings.Trim(value, "<>"), "@")
+		case found && key == "in":
+			inChannel = strings.TrimPrefix(strings.Trim(value, "<>"), "#")
+		case found && key == "from":
+			fromUser = strings.TrimPrefix(strings.Trim(value, "<>"), "@")
+		case found && key == "after":
+			after = value
+		case found && key == "before":
+			before = value
+		default:
+			words = append(words, strings.ToLower(term))
+		}
+	}
+
+	var matches []slack.SearchMessage
+	for _, ch := range s.channels {
+		if inChannel != "" && inChannel != ch.ID && inChannel != ch.Name && !strings.HasPrefix(inChannel, ch.ID+"|") {
+			continue
+		}
+		for _, m := range s.messages[ch.ID] {
+			u, _ := s.userByID(m.User)
+			if fromUser != "" && fromUser != u.ID && fromUser != u.Name {
+				continue
+			}
+			if threads && m.ThreadTimestamp == "" {
+				continue
+			}
+			if withUser != "" && !s.isWith(ch, m, withUser) {
+				continue
+			}
+			day := tsDay(m.Timestamp)
+			if (after != "" && day <= after) || (before != "" && day >= before) {
+				continue
+			}
+			if !containsAll(strings.ToLower(m.Text), words) {
+				continue
+			}
+			matches = append(matches, slack.SearchMessage{
+				Type:      "message",
+				Channel:   slack.CtxChannel{ID: ch.ID, Name: ch.Name, IsPrivate: ch.IsPrivate},
+				User:      m.User,
+				Username:  u.Name,
+				Timestamp: m.Timestamp,
+				Text:      m.Text,
+				Permalink: s.messagePermalink(ch.ID, m),
+			})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Timestamp > matches[j].Timestamp })
+
+	count, _ := strconv.Atoi(req.get("count"))
+	if count <= 0 {
+		count = 20
+	}
+	page, _ := strconv.Atoi(req.get("page"))
+	if page <= 0 {
+		page = 1
+	}
+	start, end, _ := paginate(len(matches), strconv.Itoa((page-1)*count), count)
+	pages := (len(matches) + count - 1) / count
+
+	writeOK(w, map[string]any{
+		"query": req.get("query"),
+		"messages": slack.SearchMessages{
+			Matches:    matches[start:end],
+			Paging:     slack.Paging{Count: count, Total: len(matches), Page: page, Pages: pages},
+			Pagination: slack.Pagination{TotalCount: len(matches), Page: page, PerPage: count, PageCount: pages, First: start + 1, Last: end},
+			Total:      len(matches),
+		},
+		"files": slack.SearchFiles{},
+	})
+}
+
+// isWith reports whether m is in the DM with user or in a thread user posted in.
+func (s *Server) isWith(ch *slack.Channel, m slack.Message, user string) bool {
+	for _, u := range s.users {
+		if u.Name == user {
+			user = u.ID
+		}
+	}
+	if ch.IsIM && ch.User == user {
+		return true
+	}
+	if m.ThreadTimestamp == "" {
+		return false
+	}
+	for _, other := range s.messages[ch.ID] {
+		if (other.ThreadTimestamp == m.ThreadTimestamp || other.Timestamp == m.ThreadTimestamp) && other.User == user {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(text string, words []string) bool {
+	for _, w := range words {
+		if !strings.Contains(text, strings.Trim(w, `"`)) {
+			return false
+		}
+	}
+	return true
+}
+
+// tsDay formats the date of a Slack timestamp as YYYY-MM-DD in UTC.
+func tsDay(ts string) string {
+	secs, _ := strconv.ParseInt(strings.Split(ts, ".")[0], 10, 64)
+	return slack.JSONTime(secs).Time().UTC().Format("2006-01-02")
+}
+
+func (s *Server) permalink(channel, ts string) string {
+	return fmt.Sprintf("%s/archives/%s/p%s", s.URL, channel, strings.ReplaceAll(ts, ".", ""))
+}
+
+// messagePermalink is the permalink of m, carrying thread_ts for replies like
+// the ones search.messages returns.
+func (s *Server) messagePermalink(channel string, m slack.Message) string {
+	link := s.permalink(channel, m.Timestamp)
+	if m.ThreadTimestamp != "" && m.ThreadTimestamp != m.Timestamp {
+		link += fmt.Sprintf("?thread_ts=%s&cid=%s", m.ThreadTimestamp, channel)
+	}
+	return link
+}
+
+func (s *Server) filesInfo(w http.ResponseWriter, req request) {
+	f, ok := s.files[req.get("file")]
+	if !ok {
+		writeError(w, "file_not_found")
+		return
+	}
+	writeOK(w, map[string]any{
+		"file":     f,
+		"comments": []slack.Comment{},
+		"paging":   slack.Paging{Count: 100, Total: 0, Page: 1, Pages: 0},
+	})
+}
+
+func (s *Server) filesList(w http.ResponseWriter, req request) {
+	var files []slack.File
+	for _, id := range sortedKeys(s.files) {
+		f := s.files[id]
+		if t := req.get("types"); t == "canvases" && s.canvases[id] == nil {
+			continue
+		}
+		if c := req.get("channel"); c != "" && !contains(f.Channels, c) {
+			continue
+		}
+		if u := req.get("user"); u != "" && f.User != u {
+			continue
+		}
+		files = append(files, *f)
+	}
+
+	count, _ := strconv.Atoi(req.get("count"))
+	if count <= 0 {
+		count = 100
+	}
+	page, _ := strconv.Atoi(req.get("page"))
+	if page <= 0 {
+		page = 1
+	}
+	start, end, _ := paginate(len(files), strconv.Itoa((page-1)*count), count)
+	writeOK(w, map[string]any{
+		"files":  files[start:end],
+		"paging": slack.Paging{Count: count, Total: len(files), Page: page, Pages: (len(files) + count - 1) / count},
+	})
+}
+
+func contains(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) canvasesCreate(w http.ResponseWriter, req request) {
+	var doc slack.DocumentContent
+	if raw := req.get("document_content"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+			writeError(w, "invalid_arguments")
+			return
+		}
+	}
+
+	s.seq++
+	id := fmt.Sprintf("F%06d", 100+s.seq)
+	s.addCanvasLocked(id, req.get("title"), s.selfID, doc.Markdown)
+	writeOK(w, map[string]any{"canvas_id": id})
+}
+
+// canvasSections splits canvas markdown into sections, each starting at a heading.
+func canvasSections(markdown string) []string {
+	var sections []string
+	for _, line := range strings.SplitAfter(markdown, "\n") {
+		if line == "" {
+			continue
+		}
+		if len(sections) == 0 || strings.HasPrefix(line, "#") {
+			sections = append(sections, line)
+			continue
+		}
+		sections[len(sections)-1] += line
+	}
+	return sections
+}
+
+func sectionID(i int) string {
+	return fmt.Sprintf("temp:C:sec%d", i)
+}
+
+func sectionIndex(sections []string, id string) int {
+	for i := range sections {
+		if sectionID(i) == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) canvasesEdit(w http.ResponseWriter, req request) {
+	c, ok := s.canvases[req.get("canvas_id")]
+	if !ok {
+		writeError(w, "canvas_not_found")
+		return
+	}
+	var changes []slack.CanvasChange
+	if err := json.Unmarshal([]byte(req.get("changes")), &changes); err != nil || len(changes) == 0 {
+		writeError(w, "invalid_arguments")
+		return
+	}
+
+	for _, ch := range changes {
+		sections := canvasSections(c.Content)
+		md := ch.DocumentContent.Markdown
+		if md != "" && !strings.HasSuffix(md, "\n") {
+			md += "\n"
+		}
+		idx := -1
+		if ch.SectionID != "" {
+			if idx = sectionIndex(sections, ch.SectionID); idx < 0 {
+				writeError(w, "canvas_section_not_found")
+				return
+			}
+		}
+
+		switch ch.Operation {
+		case "insert_at_start":
+			sections = append([]string{md}, sections...)
+		case "insert_at_end":
+			if n := len(sections); n > 0 && !strings.HasSuffix(sections[n-1], "\n") {
+				sections[n-1] += "\n"
+			}
+			sections = append(sections, md)
+		case "insert_before", "insert_after":
+			if idx < 0 {
+				writeError(w, "invalid_arguments")
+				return
+			}
+			if ch.Operation == "insert_after" {
+				idx++
+			}
+			sections = append(sections[:idx], append([]string{md}, sections[idx:]...)...)
+		case "replace":
+			if idx < 0 {
+				sections = []string{md}
+			} else {
+				sections[idx] = md
+			}
+		case "delete":
+			if idx < 0 {
+				writeError(w, "invalid_arguments")
+				return
+			}
+			sections = append(sections[:idx], sections[idx+1:]...)
+		default:
+			writeError(w, "invalid_operation")
+			return
+		}
+		c.Content = strings.Join(sections, "")
+	}
+
+	s.content[c.File.ID] = []byte(c.Content)
+	writeOK(w, nil)
+}
+
+func (s *Server) canvasesSectionsLookup(w http.ResponseWriter, req request) {
+	c, ok := s.canvases[req.get("canvas_id")]
+	if !ok {
+		writeError(w, "canvas_not_found")
+		return
+	}
+	var criteria slack.LookupCanvasSectionsCriteria
+	if err := json.Unmarshal([]byte(req.get("criteria")), &criteria); err != nil {
+		writeError(w, "invalid_arguments")
+		return
+	}
+
+	var found []slack.CanvasSection
+	for i, sec := range canvasSections(c.Content) {
+		if criteria.ContainsText != "" && !strings.Contains(sec, criteria.ContainsText) {
+			continue
+		}
+		if len(criteria.SectionTypes) > 0 && !contains(criteria.SectionTypes, "any_header") && !strings.HasPrefix(sec, "#") {
+			continue
+		}
+		found = append(found, slack.CanvasSection{ID: sectionID(i)})
+	}
+	writeOK(w, map[string]any{"sections": found})
+}
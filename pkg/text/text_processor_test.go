@@ -149,6 +149,11 @@ func TestFilterSpecialCharsWithCommas(t *testing.T) {
 			input:    "Check this [Google](https://google.com) out",
 			expected: "Check this https://google.com - Google, out",
 		},
+		{
+			name:     "Mentions keep their @",
+			input:    "<@U001> and @oncall-platform, please look!",
+			expected: "@U001 and @oncall-platform, please look",
+		},
 	}
 
 	for _, tt := range tests {
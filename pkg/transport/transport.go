@@ -15,6 +15,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/korotovsky/slack-mcp-server/pkg/cassette"
+	"github.com/korotovsky/slack-mcp-server/pkg/retry"
 	"github.com/korotovsky/slack-mcp-server/pkg/text"
 	utls "github.com/refraction-networking/utls"
 	"go.uber.org/zap"
@@ -440,7 +442,9 @@ func ProvideHTTPClient(cookies []*http.Cookie, logger *zap.Logger) *http.Client
 		}
 	}
 
+	transport = cassetteTransport(transport, logger)
 	transport = NewUserAgentTransport(transport, userAgent, cookies, logger)
+	transport = retry.NewTransport(transport, retry.DefaultPolicy(), logger)
 
 	client := &http.Client{
 		Transport: transport,
@@ -449,3 +453,41 @@ func ProvideHTTPClient(cookies []*http.Cookie, logger *zap.Logger) *http.Client
 
 	return client
 }
+
+// cassetteTransport records Slack traffic to SLACK_MCP_RECORD or replays it
+// from SLACK_MCP_REPLAY instead of going to the network.
+func cassetteTransport(transport http.RoundTripper, logger *zap.Logger) http.RoundTripper {
+	recordPath, replayPath := os.Getenv("SLACK_MCP_RECORD"), os.Getenv("SLACK_MCP_REPLAY")
+	if recordPath != "" && replayPath != "" {
+		logger.Fatal("SLACK_MCP_RECORD and SLACK_MCP_REPLAY cannot be used together")
+	}
+
+	if replayPath != "" {
+		player, err := cassette.Replay(replayPath)
+		if err != nil {
+			logger.Fatal("Failed to load cassette",
+				zap.String("cassette", replayPath),
+				zap.Error(err))
+		}
+		logger.Warn("Replaying Slack traffic from cassette, no requests reach Slack",
+			zap.String("context", "console"),
+			zap.String("cassette", replayPath),
+			zap.Int("interactions", player.Len()))
+		return player
+	}
+
+	if recordPath != "" {
+		recorder, err := cassette.Record(recordPath)
+		if err != nil {
+			logger.Fatal("Failed to create cassette",
+				zap.String("cassette", recordPath),
+				zap.Error(err))
+		}
+		logger.Warn("Recording Slack traffic to cassette, tokens and cookies are scrubbed but message content is not",
+			zap.String("context", "console"),
+			zap.String("cassette", recordPath))
+		return recorder.Wrap(transport)
+	}
+
+	return transport
+}